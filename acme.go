@@ -0,0 +1,363 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// DNSProvider publishes the TXT records used by the ACME DNS-01 challenge.
+// Implementations talk to the API of a DNS host (Route 53, Cloudflare, ...).
+type DNSProvider interface {
+	// Present creates the TXT record "_acme-challenge.<domain>" with value.
+	Present(ctx context.Context, domain, value string) error
+	// CleanUp removes the record created by Present.
+	CleanUp(ctx context.Context, domain, value string) error
+}
+
+// ACMEManager obtains and renews a certificate through the ACME DNS-01
+// challenge. Unlike autocert, which only supports the HTTP-01 and
+// TLS-ALPN-01 challenges, it can issue wildcard certificates such as
+// "*.example.com" for subdomain-per-tenant deployments.
+type ACMEManager struct {
+	// Domains covered by the certificate, wildcards allowed.
+	Domains []string
+	// Email is the contact address of the ACME account.
+	Email string
+	// DNS publishes the challenge records.
+	DNS DNSProvider
+	// Cache stores the account key and the certificate, use autocert.DirCache
+	// to survive restarts. Nothing is persisted when nil.
+	Cache autocert.Cache
+	// DirectoryURL of the CA, Let's Encrypt production when empty.
+	DirectoryURL string
+	// RenewBefore is how long before expiry the certificate is renewed, 30 days when zero.
+	RenewBefore time.Duration
+	// PropagationDelay is waited after publishing the records and before
+	// asking the CA to validate them.
+	PropagationDelay time.Duration
+
+	mu       sync.Mutex
+	obtainMu sync.Mutex
+	// clientMu guards client so that registering the account does not hold
+	// mu, taken by every handshake.
+	clientMu sync.Mutex
+	client   *acme.Client
+	cert     *tls.Certificate
+	renewing bool
+}
+
+// acmeObtainTimeout bounds obtaining a certificate, DNS propagation
+// included.
+const acmeObtainTimeout = 10 * time.Minute
+
+// TLSConfig returns a TLS configuration serving the managed certificate.
+func (m *ACMEManager) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: m.GetCertificate,
+		NextProtos:     []string{"h2", "http/1.1"},
+	}
+}
+
+// GetCertificate implements tls.Config.GetCertificate. The first call blocks
+// until a certificate is available; renewals happen in the background.
+func (m *ACMEManager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	if hello.ServerName != "" && !m.covers(hello.ServerName) {
+		return nil, fmt.Errorf("acme: %q is not a managed domain", hello.ServerName)
+	}
+
+	m.mu.Lock()
+	cert := m.cert
+	if cert != nil && m.needsRenewal(cert) && !m.renewing {
+		m.renewing = true
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), acmeObtainTimeout)
+			defer cancel()
+			if err := m.Obtain(ctx); err != nil {
+				log.Printf("[ACME] renewal failed: %v", err)
+			}
+			m.mu.Lock()
+			m.renewing = false
+			m.mu.Unlock()
+		}()
+	}
+	m.mu.Unlock()
+
+	if cert != nil {
+		return cert, nil
+	}
+
+	// Only one handshake obtains the first certificate, the others wait.
+	m.obtainMu.Lock()
+	defer m.obtainMu.Unlock()
+
+	m.mu.Lock()
+	cert = m.cert
+	m.mu.Unlock()
+	if cert != nil {
+		return cert, nil
+	}
+
+	// The handshake deadline is too short for DNS propagation: obtain the
+	// certificate with a budget of its own.
+	ctx, cancel := context.WithTimeout(context.Background(), acmeObtainTimeout)
+	defer cancel()
+	if err := m.load(ctx); err == nil {
+		m.mu.Lock()
+		cert = m.cert
+		m.mu.Unlock()
+		if !m.needsRenewal(cert) {
+			return cert, nil
+		}
+	}
+
+	if err := m.Obtain(ctx); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.cert, nil
+}
+
+// Obtain requests a new certificate for Domains and stores it.
+func (m *ACMEManager) Obtain(ctx context.Context) error {
+	if len(m.Domains) == 0 || m.DNS == nil {
+		return errors.New("acme: Domains and DNS are required")
+	}
+
+	client, err := m.acmeClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(m.Domains...))
+	if err != nil {
+		return err
+	}
+
+	for _, u := range order.AuthzURLs {
+		if err := m.authorize(ctx, client, u); err != nil {
+			return err
+		}
+	}
+
+	if order, err = client.WaitOrder(ctx, order.URI); err != nil {
+		return err
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{DNSNames: m.Domains}, key)
+	if err != nil {
+		return err
+	}
+
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	_ = pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	for _, b := range der {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: b})
+	}
+
+	if err := m.setCert(buf.Bytes()); err != nil {
+		return err
+	}
+	if m.Cache != nil {
+		return m.Cache.Put(ctx, m.certKey(), buf.Bytes())
+	}
+	return nil
+}
+
+func (m *ACMEManager) authorize(ctx context.Context, client *acme.Client, u string) error {
+	authz, err := client.GetAuthorization(ctx, u)
+	if err != nil {
+		return err
+	}
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var chal *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			chal = c
+			break
+		}
+	}
+	if chal == nil {
+		return fmt.Errorf("acme: no dns-01 challenge offered for %s", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(chal.Token)
+	if err != nil {
+		return err
+	}
+
+	domain := authz.Identifier.Value
+	if err := m.DNS.Present(ctx, domain, value); err != nil {
+		return err
+	}
+	defer func() {
+		if err := m.DNS.CleanUp(context.WithoutCancel(ctx), domain, value); err != nil {
+			log.Printf("[ACME] cleanup of %s failed: %v", domain, err)
+		}
+	}()
+
+	if m.PropagationDelay > 0 {
+		select {
+		case <-time.After(m.PropagationDelay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if _, err := client.Accept(ctx, chal); err != nil {
+		return err
+	}
+	_, err = client.WaitAuthorization(ctx, authz.URI)
+	return err
+}
+
+func (m *ACMEManager) acmeClient(ctx context.Context) (*acme.Client, error) {
+	m.clientMu.Lock()
+	defer m.clientMu.Unlock()
+
+	if m.client != nil {
+		return m.client, nil
+	}
+
+	key, err := m.accountKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &acme.Client{Key: key, DirectoryURL: m.DirectoryURL}
+	if client.DirectoryURL == "" {
+		client.DirectoryURL = acme.LetsEncryptURL
+	}
+
+	acct := &acme.Account{}
+	if m.Email != "" {
+		acct.Contact = []string{"mailto:" + m.Email}
+	}
+	if _, err := client.Register(ctx, acct, acme.AcceptTOS); err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, err
+	}
+
+	m.client = client
+	return client, nil
+}
+
+func (m *ACMEManager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	const cacheKey = "acme_account+key"
+
+	if m.Cache != nil {
+		if data, err := m.Cache.Get(ctx, cacheKey); err == nil {
+			if block, _ := pem.Decode(data); block != nil {
+				return x509.ParseECPrivateKey(block.Bytes)
+			}
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if m.Cache != nil {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		if err := m.Cache.Put(ctx, cacheKey, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})); err != nil {
+			return nil, err
+		}
+	}
+	return key, nil
+}
+
+// load reads the certificate from the cache.
+func (m *ACMEManager) load(ctx context.Context) error {
+	if m.Cache == nil {
+		return autocert.ErrCacheMiss
+	}
+	data, err := m.Cache.Get(ctx, m.certKey())
+	if err != nil {
+		return err
+	}
+	return m.setCert(data)
+}
+
+func (m *ACMEManager) setCert(data []byte) error {
+	cert, err := tls.X509KeyPair(data, data)
+	if err != nil {
+		return err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.cert = &cert
+	m.mu.Unlock()
+	return nil
+}
+
+func (m *ACMEManager) needsRenewal(cert *tls.Certificate) bool {
+	before := m.RenewBefore
+	if before == 0 {
+		before = 30 * 24 * time.Hour
+	}
+	return cert.Leaf == nil || time.Until(cert.Leaf.NotAfter) < before
+}
+
+func (m *ACMEManager) certKey() string {
+	return "acme_cert+" + strings.Join(m.Domains, ",")
+}
+
+// covers reports whether name matches one of the managed domains.
+func (m *ACMEManager) covers(name string) bool {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	for _, d := range m.Domains {
+		d = strings.ToLower(d)
+		if d == name {
+			return true
+		}
+		if base, ok := strings.CutPrefix(d, "*."); ok {
+			if sub, found := strings.CutSuffix(name, "."+base); found && sub != "" && !strings.Contains(sub, ".") {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestACMEManager_covers(t *testing.T) {
+	m := &ACMEManager{Domains: []string{"example.com", "*.example.com"}}
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{"example.com", true},
+		{"Tenant.Example.com.", true},
+		{"a.b.example.com", false},
+		{"example.org", false},
+		{".example.com", false},
+	}
+
+	for _, tt := range tests {
+		if got := m.covers(tt.name); got != tt.want {
+			t.Errorf("covers(%q) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// stallingCache blocks reading the account key until release is closed and
+// refuses to store anything.
+type stallingCache struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (c *stallingCache) Get(ctx context.Context, key string) ([]byte, error) {
+	close(c.entered)
+	<-c.release
+	return nil, autocert.ErrCacheMiss
+}
+
+func (c *stallingCache) Put(context.Context, string, []byte) error {
+	return errors.New("read-only cache")
+}
+
+func (c *stallingCache) Delete(context.Context, string) error { return nil }
+
+type nopDNS struct{}
+
+func (nopDNS) Present(context.Context, string, string) error { return nil }
+func (nopDNS) CleanUp(context.Context, string, string) error { return nil }
+
+func TestACMEManager_RenewalDoesNotBlockHandshakes(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, "example.com")
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cache := &stallingCache{entered: make(chan struct{}), release: make(chan struct{})}
+	m := &ACMEManager{
+		Domains:     []string{"example.com"},
+		DNS:         nopDNS{},
+		Cache:       cache,
+		RenewBefore: 2 * time.Hour,
+		cert:        &cert,
+	}
+	defer func() {
+		close(cache.release)
+		for {
+			m.mu.Lock()
+			renewing := m.renewing
+			m.mu.Unlock()
+			if !renewing {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+
+	// The first handshake starts the renewal, which stalls loading the
+	// account key.
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	<-cache.entered
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("handshake blocked by the renewal")
+	}
+}
@@ -0,0 +1,54 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// AfterCommit registers f to run once the handlers of the request have
+// completed, only when the response has a status below 400, so side
+// effects such as publishing events or sending emails happen only for
+// requests that succeeded. Functions registered while the Tx middleware
+// runs are also dropped when its transaction is not committed.
+//
+// The functions run in the order they were registered, before the request
+// is released and after its response was written, although the server may
+// still hold part of it; slow ones should start their own goroutine. A
+// panicking function is logged and does not prevent the others.
+func (c *Context) AfterCommit(f func()) {
+	c.afterCommit = append(c.afterCommit, f)
+}
+
+// runAfterCommit runs the AfterCommit functions of a successful request,
+// and resets them.
+func (c *Context) runAfterCommit() {
+	if len(c.afterCommit) == 0 {
+		return
+	}
+	if c.StatusCode() < http.StatusBadRequest {
+		for _, f := range c.afterCommit {
+			c.callAfterCommit(f)
+		}
+	}
+	c.discardAfterCommit(0)
+}
+
+func (c *Context) callAfterCommit(f func()) {
+	defer func() {
+		if err := recover(); err != nil {
+			c.Logf("[AFTERCOMMIT] panic: %v\n%s", err, debug.Stack())
+		}
+	}()
+	f()
+}
+
+// discardAfterCommit drops the AfterCommit functions registered after the
+// first n.
+func (c *Context) discardAfterCommit(n int) {
+	clear(c.afterCommit[n:])
+	c.afterCommit = c.afterCommit[:n]
+}
@@ -0,0 +1,72 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_AfterCommit(t *testing.T) {
+	log := &txLog{}
+	db := sql.OpenDB(dsnConnector{driver: txTestDriver{log}})
+	defer db.Close()
+
+	var ran []string
+	an := New()
+	an.POST("/ok", func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "first") })
+		c.AfterCommit(func() { panic("boom") })
+		c.AfterCommit(func() { ran = append(ran, "second") })
+		c.Status(http.StatusCreated)
+	})
+	an.POST("/invalid", func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "invalid") })
+		c.Error(http.StatusUnprocessableEntity, "invalid")
+	})
+	an.POST("/panic", func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "panic") })
+		panic("boom")
+	})
+	an.POST("/tx", func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "outside") })
+		c.Next()
+	}, Tx(db), func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "committed") })
+		c.Status(http.StatusNoContent)
+	})
+	an.POST("/rollback", Tx(db), func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "rolled back") })
+		c.Error(http.StatusConflict, "conflict")
+	})
+	an.POST("/written", Tx(db), func(c *Context) {
+		c.AfterCommit(func() { ran = append(ran, "written") })
+		c.Status(http.StatusOK)
+		panic("boom")
+	})
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/ok", "first second"},
+		{"/invalid", ""},
+		{"/panic", ""},
+		{"/tx", "outside committed"},
+		{"/rollback", ""},
+		// The status stays 200, the transaction is rolled back.
+		{"/written", ""},
+	}
+	for _, tt := range tests {
+		ran = nil
+		an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, tt.path, nil))
+		if got := strings.Join(ran, " "); got != tt.want {
+			t.Errorf("%s: ran %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,8 @@ package alsonow
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"log"
 	"net"
@@ -14,23 +16,82 @@ import (
 	"os"
 	"os/signal"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
+
+	"github.com/quic-go/quic-go/http3"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
+// defaultShutdownTimeout is how long the graceful shutdown waits for the
+// requests in flight by default.
+const defaultShutdownTimeout = 30 * time.Second
+
 type AlsoNow struct {
 	Router
 	server   *http.Server
 	stop     chan struct{}
 	stopOnce sync.Once
+
+	// shutdownTimeout bounds the graceful shutdown, and signals trigger it.
+	shutdownTimeout time.Duration
+	signals         []os.Signal
+	// serving is set once a server started, and done closed when it has
+	// stopped with shutdownErr.
+	serving     atomic.Bool
+	done        chan struct{}
+	doneOnce    sync.Once
+	shutdownErr error
+
+	// proxyProtocol makes the listeners expect a PROXY protocol header.
+	proxyProtocol bool
+	// h2c makes the plain HTTP listeners speak HTTP/2 as well.
+	h2c bool
+	// httpRedirectAddr is the address of the plain HTTP listener of the
+	// TLS servers, see WithHTTPRedirect.
+	httpRedirectAddr string
+
+	// notifiers are told about shutdown before connections are drained.
+	notifiers []ShutdownNotifier
+
+	// streamDrainTimeout bounds the drain of streaming responses.
+	streamDrainTimeout time.Duration
+
+	// startHooks and shutdownHooks are run by OnStart and OnShutdown.
+	startHooks    []func(context.Context) error
+	shutdownHooks []func(context.Context) error
+
+	// clientAuth and clientCAs configure mutual TLS, see WithClientAuth.
+	clientAuth tls.ClientAuthType
+	clientCAs  *x509.CertPool
+
+	// broker runs the consumers and closes the publisher, see Consume.
+	broker *brokerBridge
+
+	// quic is the HTTP/3 server of RunQUIC, announced in every response
+	// while it runs.
+	quic atomic.Pointer[http3.Server]
+}
+
+// ShutdownNotifier is implemented by registries of long-lived connections,
+// such as SSE brokers or WebSocket hubs, that need to tell their clients to
+// reconnect elsewhere before the server stops.
+type ShutdownNotifier interface {
+	NotifyShutdown(ctx context.Context)
 }
 
 // New returns a new AlsoNow instance.
 func New() *AlsoNow {
 	router := newRouter()
 	an := &AlsoNow{
-		Router: router,
-		stop:   make(chan struct{}),
+		Router:             router,
+		stop:               make(chan struct{}),
+		done:               make(chan struct{}),
+		shutdownTimeout:    defaultShutdownTimeout,
+		signals:            []os.Signal{syscall.SIGINT, syscall.SIGTERM},
+		streamDrainTimeout: defaultStreamDrainTimeout,
 		server: &http.Server{
 			ReadHeaderTimeout: 10 * time.Second,
 			ReadTimeout:       30 * time.Second,
@@ -42,9 +103,27 @@ func New() *AlsoNow {
 	an.server.Handler = an
 	an.Use(Recover())
 
+	// The directories served by Static are watched while the server runs.
+	var stopWatching func() error
+	an.OnStart(func(context.Context) error {
+		stopWatching = an.watchStatic()
+		return nil
+	})
+	an.OnShutdown(func(context.Context) error {
+		if stopWatching == nil {
+			return nil
+		}
+		return stopWatching()
+	})
+
 	return an
 }
 
+// router returns the concrete router behind an.
+func (an *AlsoNow) router() *routerImpl {
+	return an.Router.(*routerImpl)
+}
+
 func (an *AlsoNow) WithLogger() *AlsoNow {
 	an.Use(Logger())
 	return an
@@ -56,10 +135,95 @@ func (an *AlsoNow) WithServer(server *http.Server) *AlsoNow {
 			server.Handler = an
 		}
 		an.server = server
+		if an.h2c {
+			an.server.Handler = newH2CHandler(an.server)
+		}
+	}
+	return an
+}
+
+// WithH2C makes Run, RunContext and RunListener serve HTTP/2 over cleartext
+// TCP, h2c, next to HTTP/1.1: clients can start with HTTP/2 directly or
+// upgrade to it. It is needed to serve gRPC-web and grpc-gateway traffic,
+// and HTTP/2 behind load balancers terminating TLS. TLS listeners negotiate
+// HTTP/2 on their own.
+func (an *AlsoNow) WithH2C() *AlsoNow {
+	if !an.h2c {
+		an.h2c = true
+		an.server.Handler = newH2CHandler(an.server)
+	}
+	return an
+}
+
+// newH2CHandler wraps the handler of server to serve h2c connections.
+func newH2CHandler(server *http.Server) http.Handler {
+	return h2c.NewHandler(server.Handler, &http2.Server{IdleTimeout: server.IdleTimeout})
+}
+
+// WithHTTPRedirect makes RunTLS, RunAutoTLS, RunQUIC and the other TLS
+// servers also listen for plain HTTP on addr, ":80" when empty, to
+// permanently redirect the requests to HTTPS. The paths exempted with
+// WithExemptPaths, such as ACME HTTP-01 challenges and health checks, are
+// served by the application on both listeners.
+func (an *AlsoNow) WithHTTPRedirect(addr string) *AlsoNow {
+	if addr == "" {
+		addr = ":80"
 	}
+	an.httpRedirectAddr = addr
 	return an
 }
 
+// WithProxyProtocol makes Run and RunTLS accept the PROXY protocol (v1 and
+// v2) on their listener, for deployments behind HAProxy or TCP load balancers.
+func (an *AlsoNow) WithProxyProtocol() *AlsoNow {
+	an.proxyProtocol = true
+	return an
+}
+
+// WithRedirectTrailingSlash redirects requests whose trailing slash differs
+// from the pattern of the matched route to its canonical form, instead of
+// serving both: "/users/" goes to "/users" and, for a route registered as
+// "/docs/", "/docs" goes to "/docs/". GET and HEAD get a 301, other methods
+// a 308.
+func (an *AlsoNow) WithRedirectTrailingSlash() *AlsoNow {
+	an.router().redirectTrailingSlash = true
+	return an
+}
+
+// FixedPathMode tells how requests matching a route only when compared
+// case-insensitively are handled.
+type FixedPathMode int
+
+const (
+	// FixedPathOff lets them fall through to 404.
+	FixedPathOff FixedPathMode = iota
+	// FixedPathMatch serves them with the route.
+	FixedPathMatch
+	// FixedPathRedirect redirects them to the path with the case of the
+	// route, 301 for GET and HEAD and 308 otherwise.
+	FixedPathRedirect
+)
+
+// WithFixedPath makes "/Users/42" match the route "/users/:id" according to
+// mode. The case-insensitive lookup only runs for requests no route matched
+// exactly, parameter values keep their case.
+func (an *AlsoNow) WithFixedPath(mode FixedPathMode) *AlsoNow {
+	an.router().fixedPath = mode
+	return an
+}
+
+// listen opens the TCP listener for addr.
+func (an *AlsoNow) listen(addr string) (net.Listener, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	if an.proxyProtocol {
+		ln = &ProxyProtoListener{Listener: ln}
+	}
+	return ln, nil
+}
+
 func formatListenURL(addr string, isTLS bool) string {
 	host, port, err := net.SplitHostPort(addr)
 	if err != nil {
@@ -82,73 +246,287 @@ func formatListenURL(addr string, isTLS bool) string {
 	return fmt.Sprintf("%s://%s:%s", scheme, host, port)
 }
 
-func (an *AlsoNow) Run(addr ...string) {
-	runAddr := ":1221"
+// Run serves HTTP on addr, the ALSONOW_ADDR environment variable or :1221,
+// until Stop is called or a signal of WithSignals, SIGINT or SIGTERM by
+// default, is received, then shuts down gracefully. It returns the error
+// preventing the server from listening or serving, or the one of a forced
+// shutdown, and nil after a graceful stop, so applications can retry or
+// report bind failures themselves.
+func (an *AlsoNow) Run(addr ...string) error {
+	ctx, cancel := an.signalContext()
+	defer cancel()
 
-	if len(addr) > 0 && addr[0] != "" {
+	runAddr := ""
+	if len(addr) > 0 {
 		runAddr = addr[0]
-	} else if env := os.Getenv("ALSONOW_ADDR"); env != "" {
-		runAddr = env
 	}
+	return an.RunContext(ctx, runAddr)
+}
 
-	an.server.Addr = runAddr
-	log.Printf("🌠 AlsoNow starting on %s", formatListenURL(runAddr, false))
-
-	go func() {
-		if err := an.server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("Server error: %v", err)
+// RunContext is Run shutting down gracefully when ctx is done, for
+// applications driving the lifecycle of their components themselves, e.g.
+// with an errgroup. It does not handle signals.
+//
+//	g, ctx := errgroup.WithContext(ctx)
+//	g.Go(func() error { return an.RunContext(ctx, ":8080") })
+func (an *AlsoNow) RunContext(ctx context.Context, addr string) error {
+	if addr == "" {
+		addr = ":1221"
+		if env := os.Getenv("ALSONOW_ADDR"); env != "" {
+			addr = env
 		}
-	}()
+	}
+
+	an.server.Addr = addr
+	ln, err := an.listen(addr)
+	if err != nil {
+		return err
+	}
+	if err := an.start(ctx, ln); err != nil {
+		return err
+	}
+	log.Printf("🌠 AlsoNow starting on %s", formatListenURL(addr, false))
+
+	return an.serve(ctx, func() error { return an.server.Serve(ln) })
+}
+
+// RunListener is Run serving HTTP on l, such as a listener on an ephemeral
+// ":0" port in tests, an in-memory listener or one wrapping connections
+// with rate limiting. l is wrapped for the PROXY protocol when enabled,
+// and closed when RunListener returns.
+func (an *AlsoNow) RunListener(l net.Listener) error {
+	ctx, cancel := an.signalContext()
+	defer cancel()
+
+	if err := an.start(ctx, l); err != nil {
+		return err
+	}
+	if an.proxyProtocol {
+		l = &ProxyProtoListener{Listener: l}
+	}
+	an.server.Addr = l.Addr().String()
+	log.Printf("🌠 AlsoNow starting on %s", formatListenURL(an.server.Addr, false))
+
+	return an.serve(ctx, func() error { return an.server.Serve(l) })
+}
+
+// certPollInterval is how often RunTLS checks the certificate files for changes.
+const certPollInterval = 30 * time.Second
+
+// RunTLS serves HTTPS with the certificate in certFile and keyFile. The files
+// are watched and reloaded when they are rotated, without downtime, and
+// right away on SIGHUP. It returns like Run.
+func (an *AlsoNow) RunTLS(addr, certFile, keyFile string) error {
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("TLS certificate error: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, certPollInterval)
+	go reloader.ReloadOnSignal(ctx, syscall.SIGHUP)
+
+	return an.RunTLSWithCertificate(addr, reloader.GetCertificate)
+}
+
+// RunTLSWithCertificate serves HTTPS with certificates returned by getCert,
+// for callers managing certificates themselves.
+func (an *AlsoNow) RunTLSWithCertificate(addr string, getCert func(*tls.ClientHelloInfo) (*tls.Certificate, error)) error {
+	return an.runTLS(addr, &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: getCert,
+	})
+}
 
-	an.waitStopSignal()
+// RunAutoTLS is like RunTLS but serves the certificate obtained and renewed
+// by m through the ACME DNS-01 challenge.
+func (an *AlsoNow) RunAutoTLS(addr string, m *ACMEManager) error {
+	return an.runTLS(addr, m.TLSConfig())
 }
 
-func (an *AlsoNow) RunTLS(addr, certFile, keyFile string) {
+func (an *AlsoNow) runTLS(addr string, config *tls.Config) error {
 	if addr == "" {
 		addr = ":443"
 	}
 
+	ctx, cancel := an.signalContext()
+	defer cancel()
+
+	an.applyClientAuth(config)
 	an.server.Addr = addr
-	an.server.TLSConfig = &tls.Config{
-		MinVersion: tls.VersionTLS12,
+	an.server.TLSConfig = config
+	ln, err := an.listen(addr)
+	if err != nil {
+		return err
+	}
+	if an.httpRedirectAddr != "" {
+		stop, err := an.serveHTTPRedirect(addr)
+		if err != nil {
+			_ = ln.Close()
+			return err
+		}
+		defer stop()
+	}
+	if err := an.start(ctx, ln); err != nil {
+		return err
 	}
 
 	log.Printf("🌠 AlsoNow starting on %s", formatListenURL(addr, true))
 
+	return an.serve(ctx, func() error { return an.server.ServeTLS(ln, "", "") })
+}
+
+// serveHTTPRedirect starts the plain HTTP listener of WithHTTPRedirect in
+// the background, and returns the function shutting it down.
+func (an *AlsoNow) serveHTTPRedirect(httpsAddr string) (func(), error) {
+	ln, err := an.listen(an.httpRedirectAddr)
+	if err != nil {
+		return nil, err
+	}
+	srv := &http.Server{
+		Handler:           an.httpsRedirectHandler(httpsAddr),
+		ReadHeaderTimeout: an.server.ReadHeaderTimeout,
+		ReadTimeout:       an.server.ReadTimeout,
+		WriteTimeout:      an.server.WriteTimeout,
+		IdleTimeout:       an.server.IdleTimeout,
+	}
+	log.Printf("🌠 AlsoNow redirecting %s to HTTPS", formatListenURL(an.httpRedirectAddr, false))
 	go func() {
-		if err := an.server.ListenAndServeTLS(certFile, keyFile); err != nil && err != http.ErrServerClosed {
-			log.Fatalf("TLS Server error: %v", err)
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("[HTTP] %v", err)
 		}
 	}()
 
-	an.waitStopSignal()
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(ctx)
+	}, nil
 }
 
-func (an *AlsoNow) waitStopSignal() {
+// signalContext returns a context cancelled on the signals of WithSignals.
+func (an *AlsoNow) signalContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(context.Background())
+	if len(an.signals) == 0 {
+		return ctx, cancel
+	}
 	sig := make(chan os.Signal, 1)
-	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	signal.Notify(sig, an.signals...)
+	go func() {
+		defer signal.Stop(sig)
+		select {
+		case s := <-sig:
+			log.Printf("Received signal: %v, shutting down gracefully...", s)
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
+
+// serve runs serve in the background until it fails, Stop is called or
+// ctx is done, and returns the serving or shutdown error.
+func (an *AlsoNow) serve(ctx context.Context, serve func() error) (err error) {
+	an.serving.Store(true)
+	defer func() {
+		an.doneOnce.Do(func() {
+			an.shutdownErr = err
+			close(an.done)
+		})
+	}()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- serve()
+	}()
 
 	select {
+	case err := <-errc:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		// The server is gone, the resources of the application are
+		// released all the same.
+		return errors.Join(err, an.stopHooks())
 	case <-an.stop:
 		log.Println("Received Stop() call")
-	case s := <-sig:
-		log.Printf("Received signal: %v, shutting down gracefully...", s)
+	case <-ctx.Done():
 	}
 
-	log.Println("Shutting down server, will timeout after 30 seconds...")
+	return an.shutdown()
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+// shutdown drains the connections of the server, forcing them closed after
+// the shutdown timeout.
+func (an *AlsoNow) shutdown() error {
+	log.Printf("Shutting down server, will timeout after %v...", an.shutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), an.shutdownTimeout)
 	defer cancel()
 
+	// Streaming clients are told to reconnect to a healthy instance instead
+	// of seeing their connection break when the drain times out.
+	for _, n := range an.notifiers {
+		n.NotifyShutdown(ctx)
+	}
+	if an.streamDrainTimeout > 0 {
+		an.router().streams.drain(an.streamDrainTimeout)
+	}
+
 	if err := an.server.Shutdown(ctx); err != nil {
 		log.Printf("Forced shutdown: %v", err)
-	} else {
-		log.Println("Server stopped gracefully.")
+		_ = an.server.Close()
+		return errors.Join(err, an.stopHooks())
+	}
+	if err := an.stopHooks(); err != nil {
+		return err
 	}
+	log.Println("Server stopped gracefully.")
+	return nil
+}
+
+// RegisterShutdownNotifier adds n to the registries notified when the server
+// starts shutting down, before waiting for active connections to finish.
+func (an *AlsoNow) RegisterShutdownNotifier(n ShutdownNotifier) {
+	an.notifiers = append(an.notifiers, n)
 }
 
+// Stop makes the server shut down gracefully, and returns at once.
 func (an *AlsoNow) Stop() {
 	an.stopOnce.Do(func() {
 		close(an.stop)
 	})
 }
+
+// Shutdown is Stop waiting until the server has shut down, or ctx is done.
+// It returns the error returned by Run, or that of ctx, and nil at once when
+// the server was never started.
+func (an *AlsoNow) Shutdown(ctx context.Context) error {
+	an.Stop()
+	if !an.serving.Load() {
+		return nil
+	}
+	select {
+	case <-an.done:
+		return an.shutdownErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// WithShutdownTimeout sets how long the graceful shutdown waits for the
+// requests in flight before closing the remaining connections, and then
+// for the OnShutdown hooks, 30 seconds by default.
+func (an *AlsoNow) WithShutdownTimeout(d time.Duration) *AlsoNow {
+	an.shutdownTimeout = d
+	return an
+}
+
+// WithSignals sets the signals making Run and the other Run methods shut
+// down gracefully, SIGINT and SIGTERM by default. Without signals, the
+// application handles them itself and calls Stop or Shutdown.
+func (an *AlsoNow) WithSignals(sigs ...os.Signal) *AlsoNow {
+	an.signals = sigs
+	return an
+}
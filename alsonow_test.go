@@ -5,15 +5,169 @@
 package alsonow
 
 import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
 	"os"
 	"testing"
 	"time"
+
+	"golang.org/x/net/http2"
 )
 
 func TestAlsoNowRun(t *testing.T) {
 	_ = os.Setenv("ALSONOW_ADDR", "0.0.0.0:2025")
 	an := New()
-	go an.Run()
+	done := make(chan error, 1)
+	go func() { done <- an.Run() }()
 	time.Sleep(3 * time.Second)
 	an.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("Run = %v after Stop", err)
+	}
+}
+
+func TestAlsoNowRun_BindError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	if err := New().Run(ln.Addr().String()); err == nil {
+		t.Error("Run on a used address returned nil")
+	}
+}
+
+func TestAlsoNowRunContext(t *testing.T) {
+	an := New()
+	an.GET("/", func(c *Context) {})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- an.RunContext(ctx, "127.0.0.1:2026") }()
+
+	var resp *http.Response
+	var err error
+	for i := 0; i < 50; i++ {
+		if resp, err = http.Get("http://127.0.0.1:2026/"); err == nil {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunContext = %v after cancel", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("RunContext did not return after cancel")
+	}
+}
+
+func TestAlsoNowRunListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	an := New()
+	an.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte("ok")) })
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+
+	resp, err := http.Get("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "ok" {
+		t.Errorf("body = %q", body)
+	}
+
+	an.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("RunListener = %v after Stop", err)
+	}
+}
+
+func TestAlsoNowWithH2C(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	an := New().WithH2C()
+	an.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte(c.Req.Proto)) })
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+
+	client := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, addr)
+		},
+	}}
+	resp, err := client.Get("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "HTTP/2.0" {
+		t.Errorf("proto = %q", body)
+	}
+
+	// HTTP/1.1 clients are still served.
+	if resp, err = http.Get("http://" + ln.Addr().String() + "/"); err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "HTTP/1.1" {
+		t.Errorf("proto = %q", body)
+	}
+
+	an.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("RunListener = %v after Stop", err)
+	}
+}
+
+func TestAlsoNowWithHTTPRedirect(t *testing.T) {
+	an := New().WithHTTPRedirect("127.0.0.1:2029").WithExemptPaths("/healthz")
+	an.GET("/healthz", func(c *Context) { _, _ = c.Writer.Write([]byte("ok")) })
+	done := make(chan error, 1)
+	go func() { done <- an.RunDevTLS("127.0.0.1:2028") }()
+	time.Sleep(200 * time.Millisecond)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get("http://127.0.0.1:2029/users?page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMovedPermanently || resp.Header.Get("Location") != "https://127.0.0.1:2028/users?page=2" {
+		t.Errorf("redirect = %d %q", resp.StatusCode, resp.Header.Get("Location"))
+	}
+
+	if resp, err = client.Get("http://127.0.0.1:2029/healthz"); err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+		t.Errorf("exempted path = %d %q", resp.StatusCode, body)
+	}
+
+	an.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("RunDevTLS = %v after Stop", err)
+	}
 }
@@ -0,0 +1,444 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedMediaType is returned by Bind for bodies it cannot decode.
+var ErrUnsupportedMediaType = errors.New("unsupported media type")
+
+// maxMultipartMemory is the part of a multipart form kept in memory, the
+// rest being stored in temporary files.
+const maxMultipartMemory = 32 << 20
+
+// defaultJSONBodyLimit is the size of the bodies read by BindJSON unless
+// changed with WithJSONBodyLimit.
+const defaultJSONBodyLimit = 10 << 20
+
+// WithJSONBodyLimit sets the size of the bodies read by BindJSON, 10MB by
+// default, as a size understood by BodyLimit. On routes behind BodyLimit,
+// its limit applies instead.
+func (an *AlsoNow) WithJSONBodyLimit(limit string) *AlsoNow {
+	n, err := parseByteSize(limit)
+	if err != nil {
+		panic("alsonow: WithJSONBodyLimit: " + err.Error())
+	}
+	an.router().jsonBodyLimit = n
+	return an
+}
+
+// Bind decodes the request into v according to its Content-Type: JSON
+// bodies with BindJSON, forms with BindForm. Requests without a body, such
+// as GET, are bound from the query string.
+//
+// Like the other Bind methods, it then validates v and returns
+// ValidationErrors when it is invalid.
+func (c *Context) Bind(v any) error {
+	if c.Req.Body == nil || c.Req.Body == http.NoBody || c.Req.ContentLength == 0 {
+		return c.BindQuery(v)
+	}
+
+	ct, _, _ := mime.ParseMediaType(c.Req.Header.Get("Content-Type"))
+	switch {
+	case ct == "application/json" || strings.HasSuffix(ct, "+json"):
+		return c.BindJSON(v)
+	case ct == "application/x-www-form-urlencoded" || ct == "multipart/form-data":
+		return c.BindForm(v)
+	}
+	return fmt.Errorf("%w: %q", ErrUnsupportedMediaType, ct)
+}
+
+// BindJSON decodes the JSON request body into v. Malformed bodies and
+// values of the wrong type give ValidationErrors locating the problem, with
+// the path of the field and the line and column in the body.
+//
+// Bodies larger than the limit set with WithJSONBodyLimit, or by BodyLimit,
+// give an *http.MaxBytesError.
+func (c *Context) BindJSON(v any) error {
+	if c.Req.Body == nil {
+		return errors.New("binding: empty body")
+	}
+	r := c.Req.Body
+	if _, limited := r.(*limitedBody); !limited {
+		limit := c.jsonBodyLimit
+		if limit == 0 {
+			limit = defaultJSONBodyLimit
+		}
+		if c.Req.ContentLength > limit {
+			return fmt.Errorf("binding: %w", &http.MaxBytesError{Limit: limit})
+		}
+		r = http.MaxBytesReader(c.resp.ResponseWriter, r, limit)
+	}
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("binding: %w", err)
+	}
+	if err := json.NewDecoder(bytes.NewReader(body)).Decode(v); err != nil {
+		return jsonBindError(body, err)
+	}
+	return c.validate(v)
+}
+
+// jsonBindError turns the syntax and type errors of decoding body into
+// ValidationErrors.
+func jsonBindError(body []byte, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		// Offset counts the offending byte.
+		line, col := lineColumn(body, syntaxErr.Offset-1)
+		return ValidationErrors{{
+			Rule:    "syntax",
+			Message: fmt.Sprintf("invalid JSON at line %d, column %d: %v", line, col, syntaxErr),
+			Offset:  syntaxErr.Offset, Line: line, Column: col,
+			Err: err,
+		}}
+	case errors.As(err, &typeErr):
+		line, col := lineColumn(body, typeErr.Offset)
+		return ValidationErrors{{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("expected %s, got JSON %s at line %d, column %d", typeErr.Type, typeErr.Value, line, col),
+			Offset:  typeErr.Offset, Line: line, Column: col,
+			Err: err,
+		}}
+	case errors.Is(err, io.EOF):
+		return ValidationErrors{{Rule: "syntax", Message: "empty body", Err: err}}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		line, col := lineColumn(body, int64(len(body)))
+		return ValidationErrors{{
+			Rule:    "syntax",
+			Message: fmt.Sprintf("unexpected end of JSON at line %d, column %d", line, col),
+			Offset:  int64(len(body)), Line: line, Column: col,
+			Err: err,
+		}}
+	}
+	return fmt.Errorf("binding: %w", err)
+}
+
+// lineColumn returns the 1-based line and column of the byte at offset.
+func lineColumn(data []byte, offset int64) (line, col int) {
+	offset = min(max(offset, 0), int64(len(data)))
+	before := data[:offset]
+	line = bytes.Count(before, []byte("\n")) + 1
+	col = len(before) - bytes.LastIndexByte(before, '\n')
+	return line, col
+}
+
+// BindForm decodes the form values of the request, query string included,
+// into the struct pointed to by v. Fields are matched by their "form" tag,
+// or their name when untagged; `form:"-"` skips a field. The fields of a
+// nested struct are named after it, "address.city", while those of
+// embedded structs are promoted. Values that do not parse give
+// ValidationErrors naming the field, "tag[1]" for the second "tag".
+func (c *Context) BindForm(v any) error {
+	var err error
+	if ct, _, _ := mime.ParseMediaType(c.Req.Header.Get("Content-Type")); ct == "multipart/form-data" {
+		err = c.Req.ParseMultipartForm(maxMultipartMemory)
+	} else {
+		err = c.Req.ParseForm()
+	}
+	if err != nil {
+		return fmt.Errorf("binding: %w", err)
+	}
+	if err := bindValues(v, c.Req.Form); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+// BindQuery decodes the query string into the struct pointed to by v, with
+// the same rules as BindForm.
+func (c *Context) BindQuery(v any) error {
+	if err := bindValues(v, c.Req.URL.Query()); err != nil {
+		return err
+	}
+	return c.validate(v)
+}
+
+func bindValues(v any, values url.Values) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("binding: target must be a non-nil pointer to a struct")
+	}
+	var errs ValidationErrors
+	if err := bindStruct(rv.Elem(), "", values, &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// bindStruct binds the fields of rv, named prefix followed by their name,
+// collecting the values that do not parse in errs. It only fails for
+// types that cannot be bound.
+func bindStruct(rv reflect.Value, prefix string, values url.Values, errs *ValidationErrors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+
+		// The exported fields of embedded structs are promoted, even when
+		// the embedded type itself is unexported.
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			if err := bindStruct(rv.Field(i), prefix, values, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = prefix + name
+
+		if isNestedStruct(field.Type) {
+			if err := bindStruct(rv.Field(i), name+".", values, errs); err != nil {
+				return err
+			}
+			continue
+		}
+
+		vals, ok := values[name]
+		if !ok || len(vals) == 0 {
+			continue
+		}
+		if err := setField(rv.Field(i), name, vals, field, errs); err != nil {
+			return fmt.Errorf("binding: field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isNestedStruct reports whether fields of type t hold a struct whose own
+// fields are bound, unlike time.Time and other text unmarshalers.
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && !reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// errUnsupportedType reports a field type binding cannot decode into.
+var errUnsupportedType = errors.New("unsupported type")
+
+func setField(f reflect.Value, name string, vals []string, field reflect.StructField, errs *ValidationErrors) error {
+	if f.Kind() == reflect.Slice && !f.Type().Implements(textUnmarshalerType) && f.Type().Elem().Kind() != reflect.Uint8 {
+		slice := reflect.MakeSlice(f.Type(), len(vals), len(vals))
+		for i, s := range vals {
+			if err := setValue(slice.Index(i), s, field); err != nil {
+				if errors.Is(err, errUnsupportedType) {
+					return err
+				}
+				errs.add(name+"["+strconv.Itoa(i)+"]", s, f.Type().Elem(), err)
+			}
+		}
+		f.Set(slice)
+		return nil
+	}
+	if err := setValue(f, vals[0], field); err != nil {
+		if errors.Is(err, errUnsupportedType) {
+			return err
+		}
+		errs.add(name, vals[0], f.Type(), err)
+	}
+	return nil
+}
+
+// add records that value, for a field of type t, could not be parsed.
+func (ve *ValidationErrors) add(field, value string, t reflect.Type, err error) {
+	*ve = append(*ve, FieldError{
+		Field:   field,
+		Rule:    "type",
+		Param:   t.String(),
+		Message: fmt.Sprintf("invalid value %q for %s", value, t),
+		Err:     err,
+	})
+}
+
+func setValue(f reflect.Value, s string, field reflect.StructField) error {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		return setValue(f.Elem(), s, field)
+	}
+
+	if f.CanAddr() && f.Addr().Type().Implements(textUnmarshalerType) {
+		return f.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch f.Interface().(type) {
+	case time.Duration:
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(n)
+	default:
+		return fmt.Errorf("%w %s", errUnsupportedType, field.Type)
+	}
+	return nil
+}
+
+var textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+
+// EncodeQuery is the mirror of BindQuery: it encodes the struct v, or the
+// struct it points to, into query values following the same "form" tags,
+// so URLs built for links and templates decode back into the same struct.
+// Slices give repeated values. Zero values and nil pointers are omitted,
+// like missing parameters leave fields unset when binding, and fields of
+// types binding does not support are skipped.
+//
+//	q := alsonow.EncodeQuery(Filter{Tags: []string{"go", "web"}, Page: 2})
+//	// q.Encode() == "page=2&tag=go&tag=web"
+//
+// It panics when v is neither a struct nor a pointer to one.
+func EncodeQuery(v any) url.Values {
+	values := make(url.Values)
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("alsonow: EncodeQuery of non-struct type %T", v))
+	}
+	encodeStruct(rv, "", values)
+	return values
+}
+
+func encodeStruct(rv reflect.Value, prefix string, values url.Values) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name := field.Tag.Get("form")
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			encodeStruct(rv.Field(i), prefix, values)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		name = prefix + name
+
+		f := rv.Field(i)
+		if isNestedStruct(field.Type) {
+			encodeStruct(f, name+".", values)
+			continue
+		}
+		if f.IsZero() {
+			continue
+		}
+		if f.Kind() == reflect.Slice && !f.Type().Implements(textMarshalerType) && f.Type().Elem().Kind() != reflect.Uint8 {
+			for j := 0; j < f.Len(); j++ {
+				if s, ok := encodeValue(f.Index(j)); ok {
+					values.Add(name, s)
+				}
+			}
+			continue
+		}
+		if s, ok := encodeValue(f); ok {
+			values.Add(name, s)
+		}
+	}
+}
+
+// encodeValue formats f as setValue parses it.
+func encodeValue(f reflect.Value) (string, bool) {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() {
+			return "", false
+		}
+		f = f.Elem()
+	}
+
+	if f.Type().Implements(textMarshalerType) {
+		b, err := f.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err == nil
+	}
+	if f.CanAddr() && f.Addr().Type().Implements(textMarshalerType) {
+		b, err := f.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err == nil
+	}
+	if d, ok := f.Interface().(time.Duration); ok {
+		return d.String(), true
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		return f.String(), true
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(f.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(f.Uint(), 10), true
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(f.Float(), 'g', -1, f.Type().Bits()), true
+	}
+	return "", false
+}
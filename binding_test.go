@@ -0,0 +1,189 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bindPaging struct {
+	Page int `form:"page"`
+}
+
+type bindTarget struct {
+	bindPaging
+	Name    string        `form:"name" json:"name"`
+	Tags    []string      `form:"tag" json:"tags"`
+	Active  *bool         `form:"active"`
+	Timeout time.Duration `form:"timeout"`
+	Start   time.Time     `form:"start"`
+	Secret  string        `form:"-"`
+}
+
+func TestContext_Bind(t *testing.T) {
+	bind := func(method, target, ctype, body string) (bindTarget, error) {
+		var v bindTarget
+		an := New()
+		var err error
+		h := func(c *Context) { err = c.Bind(&v) }
+		an.GET("/", h)
+		an.POST("/", h)
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if ctype != "" {
+			req.Header.Set("Content-Type", ctype)
+		}
+		an.ServeHTTP(httptest.NewRecorder(), req)
+		return v, err
+	}
+
+	v, err := bind(http.MethodGet, "/?name=ann&tag=a&tag=b&page=2&active=true&timeout=5s&start=2025-01-02T03:04:05Z&Secret=x", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Name != "ann" || !reflect.DeepEqual(v.Tags, []string{"a", "b"}) || v.Page != 2 ||
+		v.Active == nil || !*v.Active || v.Timeout != 5*time.Second || v.Start.Year() != 2025 || v.Secret != "" {
+		t.Errorf("query: %+v", v)
+	}
+
+	v, err = bind(http.MethodPost, "/", "application/x-www-form-urlencoded", "name=bob&page=3")
+	if err != nil || v.Name != "bob" || v.Page != 3 {
+		t.Errorf("form: %+v, %v", v, err)
+	}
+
+	v, err = bind(http.MethodPost, "/", "application/json; charset=utf-8", `{"name":"cy","tags":["x"]}`)
+	if err != nil || v.Name != "cy" || len(v.Tags) != 1 {
+		t.Errorf("json: %+v, %v", v, err)
+	}
+
+	if _, err = bind(http.MethodPost, "/", "text/plain", "hello"); !errors.Is(err, ErrUnsupportedMediaType) {
+		t.Errorf("text/plain: err = %v", err)
+	}
+	if _, err = bind(http.MethodGet, "/?page=two", "", ""); err == nil {
+		t.Error("invalid int accepted")
+	}
+}
+
+func TestEncodeQuery(t *testing.T) {
+	active := false
+	in := bindTarget{
+		bindPaging: bindPaging{Page: 2},
+		Name:       "a b",
+		Tags:       []string{"go", "web"},
+		Active:     &active,
+		Timeout:    90 * time.Second,
+		Start:      time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Secret:     "hidden",
+	}
+	q := EncodeQuery(&in)
+	if want := "active=false&name=a+b&page=2&start=2025-01-02T03%3A04%3A05Z&tag=go&tag=web&timeout=1m30s"; q.Encode() != want {
+		t.Errorf("EncodeQuery = %q, want %q", q.Encode(), want)
+	}
+	if len(EncodeQuery(bindTarget{})) != 0 {
+		t.Errorf("zero values encoded: %v", EncodeQuery(bindTarget{}))
+	}
+
+	var out bindTarget
+	if err := bindValues(&out, q); err != nil {
+		t.Fatal(err)
+	}
+	out.Secret = in.Secret
+	if !reflect.DeepEqual(in, out) {
+		t.Errorf("round trip = %+v, want %+v", out, in)
+	}
+
+	an := New()
+	an.GET("/users/:id/posts", func(c *Context) {}).Name("user.posts")
+	if u, err := an.URLQuery("user.posts", bindPaging{Page: 3}, "id", "42"); err != nil || u != "/users/42/posts?page=3" {
+		t.Errorf("URLQuery = %q, %v", u, err)
+	}
+}
+
+func TestContext_BindErrors(t *testing.T) {
+	type address struct {
+		City string `form:"city" json:"city"`
+		Zip  int    `form:"zip" json:"zip"`
+	}
+	type order struct {
+		Address address `form:"address" json:"address"`
+		Qty     []int   `form:"qty" json:"qty"`
+	}
+	bind := func(ctype, body string) ValidationErrors {
+		var err error
+		an := New()
+		an.POST("/", func(c *Context) { err = c.Bind(&order{}) })
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		req.Header.Set("Content-Type", ctype)
+		an.ServeHTTP(httptest.NewRecorder(), req)
+		var ve ValidationErrors
+		if !errors.As(err, &ve) {
+			t.Fatalf("%s: err = %v", body, err)
+		}
+		return ve
+	}
+
+	ve := bind("application/json", "{\n  \"address\": {\"zip\": \"x\"}\n}")
+	if ve[0].Field != "address.zip" || ve[0].Rule != "type" || ve[0].Line != 2 || ve[0].Column == 0 {
+		t.Errorf("type error: %+v", ve[0])
+	}
+	ve = bind("application/json", "{\n  \"qty\": [1,,2]\n}")
+	if ve[0].Rule != "syntax" || ve[0].Line != 2 || ve[0].Column != 13 || !strings.Contains(ve[0].Message, "line 2, column 13") {
+		t.Errorf("syntax error: %+v", ve[0])
+	}
+
+	ve = bind("application/x-www-form-urlencoded", "address.city=Paris&address.zip=x&qty=1&qty=two")
+	if len(ve) != 2 || ve[0].Field != "address.zip" || ve[1].Field != "qty[1]" {
+		t.Errorf("form errors: %+v", ve)
+	}
+	if q := EncodeQuery(order{Address: address{City: "Paris"}}); q.Encode() != "address.city=Paris" {
+		t.Errorf("EncodeQuery nested = %q", q.Encode())
+	}
+}
+
+func TestContext_BindJSONLimit(t *testing.T) {
+	bind := func(an *AlsoNow, size int, chunked bool, mw ...HandlerFunc) error {
+		var err error
+		an.POST("/", append(mw, func(c *Context) {
+			var v map[string]any
+			err = c.BindJSON(&v)
+		})...)
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"a":"`+strings.Repeat("a", size-8)+`"}`))
+		if chunked {
+			req.ContentLength = -1
+		}
+		an.ServeHTTP(httptest.NewRecorder(), req)
+		return err
+	}
+	limitOf := func(err error) int64 {
+		var tooLarge *http.MaxBytesError
+		if !errors.As(err, &tooLarge) {
+			return 0
+		}
+		return tooLarge.Limit
+	}
+
+	for _, chunked := range []bool{false, true} {
+		if err := bind(New(), defaultJSONBodyLimit, chunked); err != nil {
+			t.Errorf("default limit (chunked %t): %v", chunked, err)
+		}
+		if err := bind(New(), defaultJSONBodyLimit+1, chunked); limitOf(err) != defaultJSONBodyLimit {
+			t.Errorf("over the default limit (chunked %t): %v", chunked, err)
+		}
+		if err := bind(New().WithJSONBodyLimit("1KB"), 1025, chunked); limitOf(err) != 1024 {
+			t.Errorf("WithJSONBodyLimit (chunked %t): %v", chunked, err)
+		}
+		if err := bind(New().WithJSONBodyLimit("1KB"), 4096, chunked, BodyLimit("4KB")); err != nil {
+			t.Errorf("BodyLimit above the limit (chunked %t): %v", chunked, err)
+		}
+		if err := bind(New(), 1025, chunked, BodyLimit("1KB")); limitOf(err) != 1024 {
+			t.Errorf("BodyLimit below the limit (chunked %t): %v", chunked, err)
+		}
+	}
+}
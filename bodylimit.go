@@ -0,0 +1,128 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// bodyLimitKey is the Context key of the *limitedBody installed by the
+// first BodyLimit of a chain.
+const bodyLimitKey = "alsonow.body_limit"
+
+// BodyLimit answers 413 Request Entity Too Large to the requests whose body
+// exceeds limit, a size such as "512KB", "2MB" or "1GB" in multiples of
+// 1024 bytes, or a number of bytes. The body is read through
+// http.MaxBytesReader, failing at once when its Content-Length announces
+// more; handlers get the *http.MaxBytesError from the Bind methods, and
+// the request is answered 413 unless they answered already.
+//
+// The last BodyLimit of a chain applies, so routes and groups can allow
+// more than a global limit:
+//
+//	an.Use(alsonow.BodyLimit("2MB"))
+//	an.POST("/uploads", alsonow.BodyLimit("100MB"), upload)
+func BodyLimit(limit string) HandlerFunc {
+	n, err := parseByteSize(limit)
+	if err != nil {
+		panic("alsonow: BodyLimit: " + err.Error())
+	}
+
+	return func(c *Context) {
+		if c.Req.Body == nil || c.Req.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		// A BodyLimit earlier in the chain only sees its limit replaced;
+		// the limit is enforced when the body is first read.
+		if v, ok := c.Get(bodyLimitKey); ok {
+			if lb, ok := v.(*limitedBody); ok && c.Req.Body == lb {
+				lb.limit = n
+				c.Next()
+				return
+			}
+		}
+		lb := &limitedBody{body: c.Req.Body, limit: n, length: c.Req.ContentLength, w: c.resp.ResponseWriter}
+		c.Req.Body = lb
+		c.Set(bodyLimitKey, lb)
+
+		c.Next()
+
+		if lb.exceeded && !c.Written() {
+			c.Error(http.StatusRequestEntityTooLarge, "")
+		}
+	}
+}
+
+// limitedBody reads the request body through an http.MaxBytesReader set
+// up on the first read with the limit of the last BodyLimit, and records
+// whether the limit was exceeded.
+type limitedBody struct {
+	body     io.ReadCloser
+	limit    int64
+	length   int64
+	w        http.ResponseWriter
+	r        io.ReadCloser
+	exceeded bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.r == nil {
+		if b.length > b.limit {
+			b.exceeded = true
+			return 0, &http.MaxBytesError{Limit: b.limit}
+		}
+		b.r = http.MaxBytesReader(b.w, b.body, b.limit)
+	}
+	n, err := b.r.Read(p)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		b.exceeded = true
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	if b.r != nil {
+		return b.r.Close()
+	}
+	return b.body.Close()
+}
+
+// byteUnits are the units of parseByteSize, longest suffixes first.
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KIB", 1 << 10}, {"MIB", 1 << 20}, {"GIB", 1 << 30},
+	{"KB", 1 << 10}, {"MB", 1 << 20}, {"GB", 1 << 30},
+	{"K", 1 << 10}, {"M", 1 << 20}, {"G", 1 << 30},
+	{"B", 1},
+}
+
+// parseByteSize parses sizes such as "100", "512KB", "1.5MB" or "2GiB".
+func parseByteSize(s string) (int64, error) {
+	num, unit := strings.TrimSpace(s), int64(1)
+	upper := strings.ToUpper(num)
+	for _, u := range byteUnits {
+		if strings.HasSuffix(upper, u.suffix) {
+			num, unit = strings.TrimSpace(num[:len(num)-len(u.suffix)]), u.size
+			break
+		}
+	}
+
+	v, err := strconv.ParseFloat(num, 64)
+	if err != nil || v < 0 || math.IsInf(v, 0) || math.IsNaN(v) || v*float64(unit) >= math.MaxInt64 {
+		return 0, fmt.Errorf("invalid size %q", s)
+	}
+	return int64(v * float64(unit)), nil
+}
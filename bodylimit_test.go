@@ -0,0 +1,98 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyLimit(t *testing.T) {
+	var bindErr error
+	read := func(c *Context) {
+		if _, err := io.ReadAll(c.Req.Body); err != nil {
+			return
+		}
+		c.JSON(http.StatusOK, "read")
+	}
+
+	an := New()
+	an.Use(BodyLimit("1KB"))
+	an.POST("/small", read)
+	an.POST("/bind", func(c *Context) {
+		var v map[string]any
+		bindErr = c.BindJSON(&v)
+		c.Error(http.StatusBadRequest, "bad body")
+	})
+	uploads := an.Group("/uploads", BodyLimit("4KB"))
+	uploads.POST("/", read)
+	uploads.POST("/tiny", BodyLimit("10B"), read)
+
+	tests := []struct {
+		path   string
+		size   int
+		chunk  bool
+		status int
+	}{
+		{"/small", 1024, false, http.StatusOK},
+		{"/small", 1025, false, http.StatusRequestEntityTooLarge},
+		{"/small", 1025, true, http.StatusRequestEntityTooLarge},
+		{"/uploads/", 2000, false, http.StatusOK},
+		{"/uploads/", 4096, false, http.StatusOK},
+		{"/uploads/", 4096, true, http.StatusOK},
+		{"/uploads/", 4097, false, http.StatusRequestEntityTooLarge},
+		{"/uploads/tiny", 11, false, http.StatusRequestEntityTooLarge},
+		{"/uploads/tiny", 11, true, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(strings.Repeat("a", tt.size)))
+		if tt.chunk {
+			req.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %d bytes (chunked %t): status %d, want %d", tt.path, tt.size, tt.chunk, w.Code, tt.status)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/bind", strings.NewReader(`{"a":"`+strings.Repeat("a", 2048)+`"}`))
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	var tooLarge *http.MaxBytesError
+	if !errors.As(bindErr, &tooLarge) || tooLarge.Limit != 1024 {
+		t.Errorf("bind error = %v, want *http.MaxBytesError", bindErr)
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("bind: status %d, the handler's answer should be kept", w.Code)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := map[string]int64{
+		"100":   100,
+		"10B":   10,
+		"512KB": 512 << 10,
+		"2MB":   2 << 20,
+		"2 mb":  2 << 20,
+		"1.5M":  3 << 19,
+		"1GiB":  1 << 30,
+	}
+	for s, want := range tests {
+		if got, err := parseByteSize(s); err != nil || got != want {
+			t.Errorf("parseByteSize(%q) = %d, %v, want %d", s, got, err, want)
+		}
+	}
+	for _, s := range []string{"", "MB", "-1KB", "2TB", "abc", "Inf", "NaN", "+InfMB", "1e30GB"} {
+		if _, err := parseByteSize(s); err == nil {
+			t.Errorf("parseByteSize(%q) succeeded", s)
+		}
+	}
+}
@@ -0,0 +1,268 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrNoPublisher is returned by Publish when no Publisher was set with
+// WithPublisher.
+var ErrNoPublisher = errors.New("no publisher")
+
+// ErrBrokerClosed is returned by MemoryBroker.Publish once closed.
+var ErrBrokerClosed = errors.New("broker closed")
+
+// consumerRetryDelay is the wait before a failed subscription is retried.
+const consumerRetryDelay = time.Second
+
+// Message is a message published to or consumed from a broker.
+type Message struct {
+	Topic string
+	// Key orders or partitions the messages where the broker supports it,
+	// as Kafka does.
+	Key    string
+	Data   []byte
+	Header map[string]string
+}
+
+// Publisher sends messages to a broker. Adapting the client of NATS, Kafka,
+// AMQP or any other broker takes little more than these two methods, which
+// keeps the framework free of their dependencies.
+type Publisher interface {
+	Publish(ctx context.Context, msg *Message) error
+	// Close flushes the messages buffered by the client and closes it,
+	// within ctx.
+	Close(ctx context.Context) error
+}
+
+// Subscriber receives the messages of a broker.
+type Subscriber interface {
+	// Subscribe passes the messages of topic to handle until ctx is done,
+	// and returns nil then, or an error when the subscription fails. What
+	// happens to the messages handle fails on, redelivery or dead-letter,
+	// is up to the broker.
+	Subscribe(ctx context.Context, topic string, handle func(ctx context.Context, msg *Message) error) error
+}
+
+// WithPublisher sets the Publisher of Context.Publish and AlsoNow.Publish.
+// It is closed on shutdown, after the requests in flight and the consumers
+// have completed, so the messages they publish are flushed.
+func (an *AlsoNow) WithPublisher(p Publisher) *AlsoNow {
+	an.router().publisher = p
+	an.bridge()
+	return an
+}
+
+// Publish sends msg through the Publisher, from code running outside of
+// requests.
+func (an *AlsoNow) Publish(ctx context.Context, msg *Message) error {
+	if an.router().publisher == nil {
+		return ErrNoPublisher
+	}
+	return an.router().publisher.Publish(ctx, msg)
+}
+
+// Publish sends msg through the Publisher of the application. Events that
+// must only be published when the request succeeds, as once its
+// transaction is committed, are published from AfterCommit:
+//
+//	c.AfterCommit(func() {
+//		_ = c.Publish(&alsonow.Message{Topic: "orders.created", Data: data})
+//	})
+func (c *Context) Publish(msg *Message) error {
+	if c.publisher == nil {
+		return ErrNoPublisher
+	}
+	return c.publisher.Publish(c.Context(), msg)
+}
+
+// Consume runs a consumer of topic, passing its messages to handle, for the
+// lifetime of the server: it starts after the OnStart hooks added before it
+// and is stopped on shutdown, handle calls in progress completing first. A
+// failed subscription is logged and retried. Panics in handle are recovered
+// and returned to the subscriber as errors.
+func (an *AlsoNow) Consume(sub Subscriber, topic string, handle func(ctx context.Context, msg *Message) error) *AlsoNow {
+	b := an.bridge()
+	an.OnStart(func(context.Context) error {
+		b.start(sub, topic, handle)
+		return nil
+	})
+	return an
+}
+
+// brokerBridge runs the consumers of an application.
+type brokerBridge struct {
+	an     *AlsoNow
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// bridge returns the brokerBridge of an, creating it on first use with its
+// shutdown hook: consumers are stopped, then the publisher closed.
+func (an *AlsoNow) bridge() *brokerBridge {
+	if an.broker != nil {
+		return an.broker
+	}
+	b := &brokerBridge{an: an}
+	b.ctx, b.cancel = context.WithCancel(context.Background())
+	an.broker = b
+	an.OnShutdown(b.stop)
+	return b
+}
+
+func (b *brokerBridge) start(sub Subscriber, topic string, handle func(context.Context, *Message) error) {
+	safe := func(ctx context.Context, msg *Message) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		return handle(ctx, msg)
+	}
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		for {
+			err := sub.Subscribe(b.ctx, topic, safe)
+			if b.ctx.Err() != nil {
+				return
+			}
+			log.Printf("[BROKER] consumer of %q: %v, retrying in %v", topic, err, consumerRetryDelay)
+			select {
+			case <-time.After(consumerRetryDelay):
+			case <-b.ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+func (b *brokerBridge) stop(ctx context.Context) error {
+	b.cancel()
+	done := make(chan struct{})
+	go func() {
+		b.wg.Wait()
+		close(done)
+	}()
+
+	var err error
+	select {
+	case <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("stop consumers: %w", ctx.Err())
+	}
+	if p := b.an.router().publisher; p != nil {
+		if cerr := p.Close(ctx); cerr != nil {
+			err = errors.Join(err, fmt.Errorf("close publisher: %w", cerr))
+		}
+	}
+	return err
+}
+
+// memoryQueueSize is the number of messages queued per MemoryBroker
+// subscription.
+const memoryQueueSize = 64
+
+// MemoryBroker is an in-process Publisher and Subscriber, so event-driven
+// services stay a single binary, and for tests. Every subscription of a
+// topic receives its messages, in order; Publish waits when a subscriber
+// is behind by more than 64 messages. Messages published to a topic
+// without subscribers are dropped.
+type MemoryBroker struct {
+	mu     sync.RWMutex
+	subs   map[string][]*memorySubscription
+	closed bool
+}
+
+// memorySubscription is the queue of a MemoryBroker subscription, done
+// being closed when it ends.
+type memorySubscription struct {
+	queue chan *Message
+	done  chan struct{}
+}
+
+// NewMemoryBroker returns a broker without subscriptions.
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{subs: make(map[string][]*memorySubscription)}
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, msg *Message) error {
+	b.mu.RLock()
+	closed, subs := b.closed, b.subs[msg.Topic]
+	b.mu.RUnlock()
+	if closed {
+		return ErrBrokerClosed
+	}
+
+	for _, sub := range subs {
+		select {
+		case sub.queue <- msg:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// Subscribe implements Subscriber. Once ctx is done, the messages already
+// queued are still handled before it returns. Errors of handle are logged.
+func (b *MemoryBroker) Subscribe(ctx context.Context, topic string, handle func(context.Context, *Message) error) error {
+	sub := &memorySubscription{queue: make(chan *Message, memoryQueueSize), done: make(chan struct{})}
+	b.mu.Lock()
+	b.subs[topic] = append(b.subs[topic], sub)
+	b.mu.Unlock()
+
+	run := func(ctx context.Context, msg *Message) {
+		if err := handle(ctx, msg); err != nil {
+			log.Printf("[BROKER] %s: %v", topic, err)
+		}
+	}
+	for {
+		select {
+		case msg := <-sub.queue:
+			run(ctx, msg)
+		case <-ctx.Done():
+			close(sub.done)
+			b.unsubscribe(topic, sub)
+			for {
+				select {
+				case msg := <-sub.queue:
+					run(context.WithoutCancel(ctx), msg)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func (b *MemoryBroker) unsubscribe(topic string, sub *memorySubscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	subs := b.subs[topic]
+	for i, s := range subs {
+		if s == sub {
+			b.subs[topic] = append(subs[:i:i], subs[i+1:]...)
+			break
+		}
+	}
+}
+
+// Close makes Publish fail from now on.
+func (b *MemoryBroker) Close(context.Context) error {
+	b.mu.Lock()
+	b.closed = true
+	b.mu.Unlock()
+	return nil
+}
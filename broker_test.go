@@ -0,0 +1,92 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAlsoNow_Consume(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	broker := NewMemoryBroker()
+	var mu sync.Mutex
+	var received []string
+	consumed := make(chan struct{}, 2)
+
+	an := New().WithSignals().WithPublisher(broker)
+	an.Consume(broker, "orders", func(ctx context.Context, msg *Message) error {
+		mu.Lock()
+		received = append(received, string(msg.Data))
+		mu.Unlock()
+		consumed <- struct{}{}
+		if string(msg.Data) == "bad" {
+			panic("bad order")
+		}
+		return nil
+	})
+	an.POST("/orders/:id", func(c *Context) {
+		c.AfterCommit(func() {
+			if err := c.Publish(&Message{Topic: "orders", Data: []byte(c.Param("id"))}); err != nil {
+				t.Error(err)
+			}
+		})
+		c.Status(http.StatusCreated)
+	})
+
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+	// The consumer subscribes in the background once started.
+	for subscribed := false; !subscribed; time.Sleep(time.Millisecond) {
+		broker.mu.RLock()
+		subscribed = len(broker.subs["orders"]) == 1
+		broker.mu.RUnlock()
+	}
+	for _, id := range []string{"bad", "42"} {
+		resp, err := http.Post("http://"+ln.Addr().String()+"/orders/"+id, "", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		select {
+		case <-consumed:
+		case <-time.After(time.Second):
+			t.Fatalf("order %s not consumed", id)
+		}
+	}
+
+	if err := an.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 || received[1] != "42" {
+		t.Errorf("received %q", received)
+	}
+	if err := an.Publish(context.Background(), &Message{Topic: "orders"}); !errors.Is(err, ErrBrokerClosed) {
+		t.Errorf("Publish after shutdown = %v, want ErrBrokerClosed", err)
+	}
+}
+
+func TestContext_PublishWithoutPublisher(t *testing.T) {
+	an := New()
+	an.GET("/", func(c *Context) {
+		if err := c.Publish(&Message{Topic: "t"}); !errors.Is(err, ErrNoPublisher) {
+			t.Errorf("Publish = %v, want ErrNoPublisher", err)
+		}
+	})
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
@@ -0,0 +1,245 @@
+// Package cache
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package cache provides the in-memory cache shared by the alsonow
+// middlewares, exported for application use.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// EvictReason tells why an entry left the cache.
+type EvictReason int
+
+const (
+	// Evicted entries were the least recently used of a full cache.
+	Evicted EvictReason = iota
+	// Expired entries outlived their TTL.
+	Expired
+	// Deleted entries were removed by Delete, Purge or replaced by Set.
+	Deleted
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Evicted:
+		return "evicted"
+	case Expired:
+		return "expired"
+	default:
+		return "deleted"
+	}
+}
+
+// Options configures a Memory cache.
+type Options[K comparable, V any] struct {
+	// MaxEntries bounds the number of entries, the least recently used
+	// being evicted first. Zero means unbounded.
+	MaxEntries int
+	// TTL is the lifetime of entries added by Set. Zero means forever.
+	TTL time.Duration
+
+	// OnHit and OnMiss are called on every Get, OnEvict when an entry
+	// leaves the cache, to feed metrics. They run outside the cache lock.
+	OnHit   func(key K)
+	OnMiss  func(key K)
+	OnEvict func(key K, value V, reason EvictReason)
+}
+
+// Stats are the counters of a cache since its creation.
+type Stats struct {
+	Hits, Misses           uint64
+	Evictions, Expirations uint64
+	Entries                int
+}
+
+// Memory is a concurrency-safe LRU cache whose entries may expire.
+type Memory[K comparable, V any] struct {
+	opts Options[K, V]
+
+	mu        sync.Mutex
+	ll        *list.List
+	items     map[K]*list.Element
+	stats     Stats
+	lastSweep time.Time
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictReason
+}
+
+// NewMemory returns an empty cache.
+func NewMemory[K comparable, V any](opts Options[K, V]) *Memory[K, V] {
+	return &Memory[K, V]{
+		opts:      opts,
+		ll:        list.New(),
+		items:     make(map[K]*list.Element),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get returns the value of key and whether it was found and fresh.
+func (m *Memory[K, V]) Get(key K) (V, bool) {
+	var evicted []eviction[K, V]
+
+	m.mu.Lock()
+	el, ok := m.items[key]
+	if ok && m.expired(el, time.Now()) {
+		evicted = append(evicted, m.remove(el, Expired))
+		ok = false
+	}
+	var value V
+	if ok {
+		m.ll.MoveToFront(el)
+		value = el.Value.(*entry[K, V]).value
+		m.stats.Hits++
+	} else {
+		m.stats.Misses++
+	}
+	m.mu.Unlock()
+
+	m.notify(evicted)
+	if ok && m.opts.OnHit != nil {
+		m.opts.OnHit(key)
+	} else if !ok && m.opts.OnMiss != nil {
+		m.opts.OnMiss(key)
+	}
+	return value, ok
+}
+
+// Set adds or replaces the value of key with the default TTL.
+func (m *Memory[K, V]) Set(key K, value V) {
+	m.SetTTL(key, value, m.opts.TTL)
+}
+
+// SetTTL adds or replaces the value of key, expiring after ttl unless it
+// is zero.
+func (m *Memory[K, V]) SetTTL(key K, value V, ttl time.Duration) {
+	now := time.Now()
+	e := &entry[K, V]{key: key, value: value}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+
+	var evicted []eviction[K, V]
+
+	m.mu.Lock()
+	evicted = m.sweep(now, evicted)
+	if el, ok := m.items[key]; ok {
+		evicted = append(evicted, m.remove(el, Deleted))
+	}
+	m.items[key] = m.ll.PushFront(e)
+	if m.opts.MaxEntries > 0 && m.ll.Len() > m.opts.MaxEntries {
+		evicted = append(evicted, m.remove(m.ll.Back(), Evicted))
+	}
+	m.mu.Unlock()
+
+	m.notify(evicted)
+}
+
+// Delete removes key.
+func (m *Memory[K, V]) Delete(key K) {
+	var evicted []eviction[K, V]
+
+	m.mu.Lock()
+	if el, ok := m.items[key]; ok {
+		evicted = append(evicted, m.remove(el, Deleted))
+	}
+	m.mu.Unlock()
+
+	m.notify(evicted)
+}
+
+// Purge removes every entry.
+func (m *Memory[K, V]) Purge() {
+	var evicted []eviction[K, V]
+
+	m.mu.Lock()
+	for el := m.ll.Front(); el != nil; el = m.ll.Front() {
+		evicted = append(evicted, m.remove(el, Deleted))
+	}
+	m.mu.Unlock()
+
+	m.notify(evicted)
+}
+
+// Len returns the number of entries, including expired ones not yet
+// dropped.
+func (m *Memory[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.ll.Len()
+}
+
+// Stats returns the counters of the cache.
+func (m *Memory[K, V]) Stats() Stats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s := m.stats
+	s.Entries = m.ll.Len()
+	return s
+}
+
+func (m *Memory[K, V]) expired(el *list.Element, now time.Time) bool {
+	e := el.Value.(*entry[K, V])
+	return !e.expires.IsZero() && now.After(e.expires)
+}
+
+// sweep drops expired entries, at most once per TTL or minute, so that
+// entries never read again do not pile up.
+func (m *Memory[K, V]) sweep(now time.Time, evicted []eviction[K, V]) []eviction[K, V] {
+	every := m.opts.TTL
+	if every <= 0 {
+		every = time.Minute
+	}
+	if now.Sub(m.lastSweep) < every {
+		return evicted
+	}
+	m.lastSweep = now
+
+	for el := m.ll.Back(); el != nil; {
+		prev := el.Prev()
+		if m.expired(el, now) {
+			evicted = append(evicted, m.remove(el, Expired))
+		}
+		el = prev
+	}
+	return evicted
+}
+
+func (m *Memory[K, V]) remove(el *list.Element, reason EvictReason) eviction[K, V] {
+	e := el.Value.(*entry[K, V])
+	m.ll.Remove(el)
+	delete(m.items, e.key)
+
+	switch reason {
+	case Evicted:
+		m.stats.Evictions++
+	case Expired:
+		m.stats.Expirations++
+	}
+	return eviction[K, V]{key: e.key, value: e.value, reason: reason}
+}
+
+func (m *Memory[K, V]) notify(evicted []eviction[K, V]) {
+	if m.opts.OnEvict == nil {
+		return
+	}
+	for _, ev := range evicted {
+		m.opts.OnEvict(ev.key, ev.value, ev.reason)
+	}
+}
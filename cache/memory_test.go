@@ -0,0 +1,78 @@
+// Package cache
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package cache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMemory_LRU(t *testing.T) {
+	var evicted []string
+	m := NewMemory(Options[string, int]{
+		MaxEntries: 2,
+		OnEvict: func(key string, _ int, reason EvictReason) {
+			evicted = append(evicted, key+":"+reason.String())
+		},
+	})
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Get("a") // b is now the least recently used
+	m.Set("c", 3)
+
+	if _, ok := m.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	if v, ok := m.Get("a"); !ok || v != 1 {
+		t.Errorf("Get(a) = %d, %v", v, ok)
+	}
+	if len(evicted) != 1 || evicted[0] != "b:evicted" {
+		t.Errorf("evicted = %v", evicted)
+	}
+
+	s := m.Stats()
+	if s.Hits != 2 || s.Misses != 1 || s.Evictions != 1 || s.Entries != 2 {
+		t.Errorf("Stats = %+v", s)
+	}
+}
+
+func TestMemory_TTL(t *testing.T) {
+	var misses int
+	m := NewMemory(Options[string, string]{
+		TTL:    20 * time.Millisecond,
+		OnMiss: func(string) { misses++ },
+	})
+
+	m.Set("k", "v")
+	m.SetTTL("forever", "v", 0)
+	if _, ok := m.Get("k"); !ok {
+		t.Fatal("fresh entry missing")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if _, ok := m.Get("k"); ok {
+		t.Error("expired entry returned")
+	}
+	if _, ok := m.Get("forever"); !ok {
+		t.Error("entry without TTL expired")
+	}
+	if misses != 1 || m.Stats().Expirations != 1 {
+		t.Errorf("misses = %d, stats = %+v", misses, m.Stats())
+	}
+}
+
+func BenchmarkMemory(b *testing.B) {
+	m := NewMemory(Options[int, int]{MaxEntries: 1024, TTL: time.Minute})
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if _, ok := m.Get(i % 2048); !ok {
+				m.Set(i%2048, i)
+			}
+			i++
+		}
+	})
+}
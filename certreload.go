@@ -0,0 +1,136 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// CertReloader serves a certificate loaded from a cert/key file pair and
+// reloads it when the files change, so certificates rotated by certbot or
+// cert-manager are picked up without restarting the server.
+type CertReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.RWMutex
+	cert    *tls.Certificate
+	certMod time.Time
+	keyMod  time.Time
+}
+
+// NewCertReloader loads the certificate from certFile and keyFile.
+func NewCertReloader(certFile, keyFile string) (*CertReloader, error) {
+	r := &CertReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (r *CertReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cert, nil
+}
+
+// Reload reads the files again. On failure the current certificate is kept.
+func (r *CertReloader) Reload() error {
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return err
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return err
+	}
+	if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.cert = &cert
+	r.certMod, r.keyMod = certMod, keyMod
+	r.mu.Unlock()
+	return nil
+}
+
+// Watch polls the files every interval and reloads the certificate when
+// either changes, until ctx is done.
+func (r *CertReloader) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		certMod, keyMod, err := r.modTimes()
+		if err != nil {
+			log.Printf("[TLS] stat certificate: %v", err)
+			continue
+		}
+
+		r.mu.RLock()
+		changed := !certMod.Equal(r.certMod) || !keyMod.Equal(r.keyMod)
+		r.mu.RUnlock()
+		if !changed {
+			continue
+		}
+
+		if err := r.Reload(); err != nil {
+			// The pair may be half written, try again on the next tick.
+			log.Printf("[TLS] reload certificate: %v", err)
+			continue
+		}
+		log.Printf("[TLS] certificate reloaded from %s", r.certFile)
+	}
+}
+
+// ReloadOnSignal reloads the certificate whenever the process receives one
+// of sigs, until ctx is done, so rotation hooks of cert-manager or Vault
+// agents can apply a new certificate at once with "kill -HUP".
+func (r *CertReloader) ReloadOnSignal(ctx context.Context, sigs ...os.Signal) {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, sigs...)
+	defer signal.Stop(c)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-c:
+		}
+		if err := r.Reload(); err != nil {
+			log.Printf("[TLS] reload certificate: %v", err)
+			continue
+		}
+		log.Printf("[TLS] certificate reloaded from %s", r.certFile)
+	}
+}
+
+func (r *CertReloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
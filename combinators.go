@@ -0,0 +1,50 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import "strings"
+
+// When runs mw only for requests matching pred; other requests skip it and
+// continue down the chain.
+func When(pred func(*Context) bool, mw HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		if pred(c) {
+			mw(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+// Unless runs mw for every request except those matching pred.
+func Unless(pred func(*Context) bool, mw HandlerFunc) HandlerFunc {
+	return When(func(c *Context) bool { return !pred(c) }, mw)
+}
+
+// ForMethods runs mw only for requests using one of methods, e.g.
+//
+//	an.Use(alsonow.ForMethods(auditLog, "POST", "PUT", "DELETE"))
+func ForMethods(mw HandlerFunc, methods ...string) HandlerFunc {
+	return When(func(c *Context) bool {
+		for _, m := range methods {
+			if strings.EqualFold(c.Method(), m) {
+				return true
+			}
+		}
+		return false
+	}, mw)
+}
+
+// ForPathPrefix runs mw only for requests whose path starts with one of prefixes.
+func ForPathPrefix(mw HandlerFunc, prefixes ...string) HandlerFunc {
+	return When(func(c *Context) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(c.Path(), p) {
+				return true
+			}
+		}
+		return false
+	}, mw)
+}
@@ -0,0 +1,49 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCombinators(t *testing.T) {
+	deny := func(c *Context) { c.AbortWithStatus(http.StatusForbidden) }
+	isAdmin := func(c *Context) bool { return c.Header("X-Admin") != "" }
+
+	an := New()
+	an.Use(
+		ForMethods(deny, http.MethodDelete),
+		Unless(isAdmin, ForPathPrefix(deny, "/admin")),
+	)
+	h := func(c *Context) { c.Status(http.StatusOK) }
+	an.GET("/admin/stats", h)
+	an.GET("/items", h)
+	an.DELETE("/items", h)
+
+	tests := []struct {
+		method, path string
+		admin        bool
+		code         int
+	}{
+		{http.MethodGet, "/items", false, http.StatusOK},
+		{http.MethodDelete, "/items", false, http.StatusForbidden},
+		{http.MethodGet, "/admin/stats", false, http.StatusForbidden},
+		{http.MethodGet, "/admin/stats", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.admin {
+			req.Header.Set("X-Admin", "1")
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Code != tt.code {
+			t.Errorf("%s %s (admin %v): status = %d, want %d", tt.method, tt.path, tt.admin, w.Code, tt.code)
+		}
+	}
+}
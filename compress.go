@@ -0,0 +1,406 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// defaultCompressMinLength is the size below which responses are not
+// compressed by default, the saving not being worth the CPU.
+const defaultCompressMinLength = 1024
+
+// Encoder compresses a response in a content coding. The writers of
+// compress/gzip, github.com/andybalholm/brotli and
+// github.com/klauspost/compress/zstd implement it.
+type Encoder interface {
+	io.WriteCloser
+	// Flush sends the data written so far, as when streaming.
+	Flush() error
+	// Reset discards the state of the encoder and makes it write to w, so
+	// encoders can be reused.
+	Reset(w io.Writer)
+}
+
+// NewEncoderFunc returns an encoder writing to w at level, the default
+// level of the coding when level is zero.
+type NewEncoderFunc func(w io.Writer, level int) (Encoder, error)
+
+var (
+	encodersMu sync.RWMutex
+	encoders   = map[string]NewEncoderFunc{}
+)
+
+// defaultEncodings are the codings Compress prefers, when the client
+// accepts several of them equally, to the other registered ones.
+var defaultEncodings = []string{"br", "zstd", "gzip"}
+
+func init() {
+	RegisterEncoder("gzip", func(w io.Writer, level int) (Encoder, error) {
+		if level == 0 {
+			level = gzip.DefaultCompression
+		}
+		return gzip.NewWriterLevel(w, level)
+	})
+	RegisterEncoder("br", func(w io.Writer, level int) (Encoder, error) {
+		if level == 0 {
+			level = brotli.DefaultCompression
+		}
+		if level < brotli.BestSpeed || level > brotli.BestCompression {
+			return nil, fmt.Errorf("brotli: invalid compression level: %d", level)
+		}
+		return brotli.NewWriterLevel(w, level), nil
+	})
+	RegisterEncoder("zstd", func(w io.Writer, level int) (Encoder, error) {
+		opts := []zstd.EOption{
+			zstd.WithEncoderConcurrency(1),
+			// Browsers only support windows of up to 8 MB.
+			zstd.WithWindowSize(8 << 20),
+		}
+		if level != 0 {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	})
+}
+
+// RegisterEncoder makes the content coding name, such as "br", available to
+// Compress, newEncoder creating its encoders. It replaces the encoder
+// registered for the coding before, if any, and must be called before
+// Compress.
+func RegisterEncoder(name string, newEncoder NewEncoderFunc) {
+	name = strings.ToLower(name)
+	if name == "" || name == "identity" || name == "*" {
+		panic(fmt.Sprintf("alsonow: invalid content coding %q", name))
+	}
+	encodersMu.Lock()
+	encoders[name] = newEncoder
+	encodersMu.Unlock()
+}
+
+// CompressConfig configures Compress.
+type CompressConfig struct {
+	// Encodings lists the content codings used, in order of preference
+	// when the client accepts several of them equally. It defaults to br,
+	// zstd and gzip, followed by the other registered codings by name.
+	Encodings []string
+	// Level is the gzip compression level, gzip.DefaultCompression when
+	// zero.
+	Level int
+	// Levels sets the compression level of other codings, on their own
+	// scales: 0 to 11 for br, 1 to 22 for zstd. Codings missing from it use
+	// their default level.
+	Levels map[string]int
+	// MinLength is the size from which responses are compressed, 1024
+	// bytes when zero.
+	MinLength int
+	// SkipTypes lists further media types sent as is, next to images,
+	// audio, video, fonts and archives, which are already compressed.
+	SkipTypes []string
+}
+
+// incompressibleTypes are the media types already compressed, sent as is.
+var incompressibleTypes = map[string]bool{
+	"application/gzip":             true,
+	"application/x-gzip":           true,
+	"application/zip":              true,
+	"application/zstd":             true,
+	"application/x-7z-compressed":  true,
+	"application/x-rar-compressed": true,
+	"application/pdf":              true,
+	"application/octet-stream":     true,
+}
+
+// Compress compresses the responses of the clients accepting one of the
+// registered content codings, br, zstd and gzip by default, chosen by the
+// quality values of their Accept-Encoding header. Small responses, already
+// compressed media types and responses carrying a Content-Encoding, such
+// as pre-compressed static files, are sent as is. The encoders are pooled,
+// so compressing allocates little per request.
+func Compress(cfg ...CompressConfig) HandlerFunc {
+	var conf CompressConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.MinLength == 0 {
+		conf.MinLength = defaultCompressMinLength
+	}
+	skip := make(map[string]bool, len(conf.SkipTypes))
+	for _, t := range conf.SkipTypes {
+		skip[strings.ToLower(t)] = true
+	}
+
+	encodersMu.RLock()
+	encodings := conf.Encodings
+	if encodings == nil {
+		encodings = registeredEncodings()
+	}
+	pools := make(map[string]*sync.Pool, len(encodings))
+	conf.Encodings = make([]string, len(encodings))
+	for i, name := range encodings {
+		name = strings.ToLower(name)
+		conf.Encodings[i] = name
+		newEncoder, ok := encoders[name]
+		if !ok {
+			panic(fmt.Sprintf("alsonow: no encoder registered for content coding %q", name))
+		}
+		level := conf.Levels[name]
+		if name == "gzip" && conf.Level != 0 {
+			level = conf.Level
+		}
+		pools[name] = encoderPool(name, newEncoder, level)
+	}
+	encodersMu.RUnlock()
+	writers := sync.Pool{New: func() any { return &compressWriter{} }}
+
+	return func(c *Context) {
+		coding := negotiateEncoding(c.Header("Accept-Encoding"), conf.Encodings)
+		if coding == "" {
+			c.Next()
+			return
+		}
+
+		w := writers.Get().(*compressWriter)
+		w.ResponseWriter, w.minLength, w.skip = c.Writer, conf.MinLength, skip
+		w.coding, w.encoders = coding, pools[coding]
+		c.Writer = w
+		completed := false
+		defer func() {
+			// A panicking chain leaves the response to Recover.
+			w.close(completed)
+			c.Writer = w.ResponseWriter
+			w.reset()
+			writers.Put(w)
+		}()
+
+		c.Next()
+		completed = true
+	}
+}
+
+// registeredEncodings returns the defaultEncodings followed by the other
+// registered codings by name. encodersMu must be held.
+func registeredEncodings() []string {
+	var names, others []string
+	for _, name := range defaultEncodings {
+		if encoders[name] != nil {
+			names = append(names, name)
+		}
+	}
+	for name := range encoders {
+		if encoders[name] != nil && !containsString(defaultEncodings, name) {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// encoderPool returns a pool of the encoders of coding name at level. It
+// panics when the encoder cannot be created, at startup rather than on the
+// first request.
+func encoderPool(name string, newEncoder NewEncoderFunc, level int) *sync.Pool {
+	pool := &sync.Pool{New: func() any {
+		enc, err := newEncoder(io.Discard, level)
+		if err != nil {
+			panic(fmt.Sprintf("alsonow: %s encoder: %v", name, err))
+		}
+		return enc
+	}}
+	pool.Put(pool.Get())
+	return pool
+}
+
+// negotiateEncoding returns the coding of offers with the highest quality
+// value in the Accept-Encoding header, the first one of offers on ties, or
+// "" when the client accepts none of them. A coding listed by name takes
+// its quality value from its own entry rather than from "*".
+func negotiateEncoding(header string, offers []string) string {
+	if header == "" {
+		return ""
+	}
+	qualities := make(map[string]float64, 4)
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			var err error
+			if q, err = strconv.ParseFloat(v, 64); err != nil {
+				q = 0
+			}
+		}
+		qualities[name] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		q, ok := qualities[offer]
+		if !ok {
+			q = qualities["*"]
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+	return best
+}
+
+// compressWriter buffers the beginning of a response until it knows whether
+// to compress it, then writes it compressed or as is.
+type compressWriter struct {
+	http.ResponseWriter
+	minLength int
+	skip      map[string]bool
+	coding    string
+	encoders  *sync.Pool
+
+	status  int
+	buf     []byte
+	decided bool
+	enc     Encoder
+}
+
+func (w *compressWriter) reset() {
+	w.ResponseWriter, w.skip, w.encoders = nil, nil, nil
+	w.status = 0
+	w.buf = w.buf[:0]
+	w.decided = false
+	w.enc = nil
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.status == 0 {
+		w.status = code
+	}
+}
+
+func (w *compressWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	if !w.decided {
+		w.buf = append(w.buf, p...)
+		if len(w.buf) < w.minLength {
+			return len(p), nil
+		}
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if w.enc != nil {
+		return w.enc.Write(p)
+	}
+	return w.ResponseWriter.Write(p)
+}
+
+// decide writes the headers, compressing the response when allowed and
+// compressible, then the buffered body.
+func (w *compressWriter) decide(allowed bool) error {
+	w.decided = true
+	h := w.Header()
+	if h.Get("Content-Type") == "" && h.Get("Content-Encoding") == "" && len(w.buf) > 0 {
+		// Sniffed here, the server would sniff the compressed bytes.
+		h.Set("Content-Type", http.DetectContentType(w.buf))
+	}
+
+	if allowed && w.compressible() {
+		h.Del("Content-Length")
+		h.Set("Content-Encoding", w.coding)
+		w.enc = w.encoders.Get().(Encoder)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+
+	if len(w.buf) == 0 {
+		return nil
+	}
+	var err error
+	if w.enc != nil {
+		_, err = w.enc.Write(w.buf)
+	} else {
+		_, err = w.ResponseWriter.Write(w.buf)
+	}
+	return err
+}
+
+// compressible reports whether the response can be compressed, and marks it
+// as varying with Accept-Encoding when it can.
+func (w *compressWriter) compressible() bool {
+	h := w.Header()
+	if h.Get("Content-Encoding") != "" || h.Get("Content-Range") != "" ||
+		w.status < http.StatusOK || w.status == http.StatusNoContent || w.status == http.StatusNotModified {
+		return false
+	}
+	mediaType, _, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if incompressibleTypes[mediaType] || w.skip[mediaType] || strings.HasPrefix(mediaType, "video/") ||
+		strings.HasPrefix(mediaType, "audio/") || strings.HasPrefix(mediaType, "font/woff") ||
+		(strings.HasPrefix(mediaType, "image/") && mediaType != "image/svg+xml") {
+		return false
+	}
+	h.Add("Vary", "Accept-Encoding")
+	return true
+}
+
+// close ends the response once the chain returned, sending the responses
+// shorter than minLength as is. Nothing is sent when the chain panicked.
+func (w *compressWriter) close(completed bool) {
+	if !w.decided && completed && w.status != 0 {
+		_ = w.decide(false)
+	}
+	if w.enc != nil {
+		_ = w.enc.Close()
+		w.enc.Reset(io.Discard)
+		w.encoders.Put(w.enc)
+	}
+}
+
+// Flush implements http.Flusher. Streamed responses are compressed, data
+// flushed so far being sent at once.
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(true)
+	}
+	if w.enc != nil {
+		_ = w.enc.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,222 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompress(t *testing.T) {
+	long := strings.Repeat("alsonow ", 512)
+
+	an := New()
+	g := an.Group("/", Compress())
+	g.GET("/text", func(c *Context) {
+		c.SetHeader("Content-Type", "text/plain; charset=utf-8")
+		c.Writer.Write([]byte(long))
+	})
+	g.GET("/sniffed", func(c *Context) { c.Writer.Write([]byte("<html>" + long)) })
+	g.GET("/small", func(c *Context) {
+		c.SetHeader("Content-Type", "text/plain")
+		c.Status(http.StatusAccepted)
+		c.Writer.Write([]byte("small"))
+	})
+	g.GET("/image", func(c *Context) {
+		c.SetHeader("Content-Type", "image/png")
+		c.Writer.Write([]byte(long))
+	})
+	g.GET("/encoded", func(c *Context) {
+		c.SetHeader("Content-Encoding", "br")
+		c.Writer.Write([]byte(long))
+	})
+	g.GET("/empty", func(c *Context) { c.Status(http.StatusNoContent) })
+	g.GET("/stream", func(c *Context) {
+		c.SetHeader("Content-Type", "text/event-stream")
+		c.Writer.Write([]byte("data: 1\n\n"))
+		c.Writer.(http.Flusher).Flush()
+	})
+
+	tests := []struct {
+		path, accept string
+		code         int
+		encoding     string
+		contentType  string
+		body         string
+	}{
+		{"/text", "gzip", http.StatusOK, "gzip", "text/plain; charset=utf-8", long},
+		{"/text", "gzip, deflate, br, zstd", http.StatusOK, "br", "text/plain; charset=utf-8", long},
+		{"/text", "gzip, br;q=0.5, zstd;q=0.8", http.StatusOK, "gzip", "text/plain; charset=utf-8", long},
+		{"/text", "zstd", http.StatusOK, "zstd", "text/plain; charset=utf-8", long},
+		{"/text", "", http.StatusOK, "", "text/plain; charset=utf-8", long},
+		{"/text", "gzip;q=0", http.StatusOK, "", "text/plain; charset=utf-8", long},
+		{"/sniffed", "gzip", http.StatusOK, "gzip", "text/html; charset=utf-8", "<html>" + long},
+		{"/small", "gzip", http.StatusAccepted, "", "text/plain", "small"},
+		{"/image", "gzip", http.StatusOK, "", "image/png", long},
+		{"/encoded", "gzip", http.StatusOK, "br", "", long},
+		{"/empty", "gzip", http.StatusNoContent, "", "", ""},
+		{"/stream", "gzip", http.StatusOK, "gzip", "text/event-stream", "data: 1\n\n"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.accept != "" {
+			req.Header.Set("Accept-Encoding", tt.accept)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+
+		encoding := w.Header().Get("Content-Encoding")
+		if w.Code != tt.code || encoding != tt.encoding || w.Header().Get("Content-Type") != tt.contentType {
+			t.Errorf("%s (%q): %d %q %q, want %d %q %q", tt.path, tt.accept, w.Code, encoding,
+				w.Header().Get("Content-Type"), tt.code, tt.encoding, tt.contentType)
+			continue
+		}
+		body := w.Body.String()
+		// The body of /encoded is sent as the handler wrote it.
+		if encoding != "" && tt.path != "/encoded" {
+			if w.Header().Get("Vary") != "Accept-Encoding" {
+				t.Errorf("%s: Vary = %q", tt.path, w.Header().Get("Vary"))
+			}
+			body = decompress(t, encoding, w.Body)
+		}
+		if body != tt.body {
+			t.Errorf("%s (%q): body of %d bytes, want %d", tt.path, tt.accept, len(body), len(tt.body))
+		}
+	}
+}
+
+// decompress returns the body r encoded in coding.
+func decompress(t *testing.T, coding string, r io.Reader) string {
+	t.Helper()
+	var dec io.Reader
+	switch coding {
+	case "gzip":
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dec = zr
+	case "br":
+		dec = brotli.NewReader(r)
+	case "zstd":
+		zr, err := zstd.NewReader(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer zr.Close()
+		dec = zr
+	default:
+		t.Fatalf("unexpected coding %q", coding)
+	}
+	b, err := io.ReadAll(dec)
+	if err != nil {
+		t.Fatalf("%s: %v", coding, err)
+	}
+	return string(b)
+}
+
+func TestNegotiateEncoding(t *testing.T) {
+	offers := []string{"br", "zstd", "gzip"}
+	tests := []struct {
+		header, want string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip", "gzip"},
+		{"gzip, br", "br"},
+		{"GZIP;q=0.9, zstd;q=0.5", "gzip"},
+		{"*", "br"},
+		{"*;q=0.5, br;q=0", "zstd"},
+		{"br;q=0, zstd;q=0, gzip;q=0, *", ""},
+		{"gzip;q=bogus", ""},
+	}
+	for _, tt := range tests {
+		if got := negotiateEncoding(tt.header, offers); got != tt.want {
+			t.Errorf("negotiateEncoding(%q) = %q, want %q", tt.header, got, tt.want)
+		}
+	}
+}
+
+// reverseEncoder is a toy coding reversing the bytes of each write.
+type reverseEncoder struct{ w io.Writer }
+
+func (e *reverseEncoder) Write(p []byte) (int, error) {
+	r := make([]byte, len(p))
+	for i, b := range p {
+		r[len(p)-1-i] = b
+	}
+	return e.w.Write(r)
+}
+func (e *reverseEncoder) Close() error      { return nil }
+func (e *reverseEncoder) Flush() error      { return nil }
+func (e *reverseEncoder) Reset(w io.Writer) { e.w = w }
+
+func TestCompress_RegisterEncoder(t *testing.T) {
+	RegisterEncoder("x-reverse", func(w io.Writer, _ int) (Encoder, error) { return &reverseEncoder{w}, nil })
+	defer func() {
+		encodersMu.Lock()
+		delete(encoders, "x-reverse")
+		encodersMu.Unlock()
+	}()
+
+	an := New()
+	an.GET("/", Compress(CompressConfig{Encodings: []string{"x-reverse", "gzip"}, MinLength: 1}), func(c *Context) {
+		c.SetHeader("Content-Type", "text/plain")
+		c.Writer.Write([]byte("abc"))
+	})
+
+	for accept, want := range map[string]string{"x-reverse, gzip": "cba", "br": "abc"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("Accept-Encoding", accept)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Body.String() != want {
+			t.Errorf("%q: %q %q, want %q", accept, w.Header().Get("Content-Encoding"), w.Body.String(), want)
+		}
+	}
+	if got := registeredEncodings(); len(got) != 4 || got[3] != "x-reverse" {
+		t.Errorf("registeredEncodings() = %q", got)
+	}
+}
+
+func TestCompress_Panic(t *testing.T) {
+	an := New()
+	an.Use(Recover())
+	an.GET("/panic", Compress(), func(c *Context) {
+		c.Writer.Write([]byte("partial"))
+		panic("boom")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "partial") {
+		t.Errorf("got %d %q, want a clean 500", w.Code, w.Body.String())
+	}
+}
+
+func BenchmarkCompress(b *testing.B) {
+	body := []byte(strings.Repeat("alsonow ", 512))
+	an := New()
+	an.GET("/", Compress(), func(c *Context) {
+		c.SetHeader("Content-Type", "text/plain")
+		c.Writer.Write(body)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		an.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
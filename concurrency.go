@@ -0,0 +1,57 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ConcurrencyConfig configures ConcurrencyLimit.
+type ConcurrencyConfig struct {
+	// Max is the number of requests a key may have in flight at once.
+	Max int
+	// KeyFunc identifies the client, ClientIP when nil.
+	KeyFunc func(*Context) string
+}
+
+// ConcurrencyLimit rejects requests with 429 Too Many Requests when their key
+// already has Max requests in flight. Unlike a rate limiter it caps slow
+// requests, which stops slow-request floods from a single client.
+func ConcurrencyLimit(cfg ConcurrencyConfig) HandlerFunc {
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *Context) string { return ClientIP(c.Req) }
+	}
+
+	var (
+		mu       sync.Mutex
+		inFlight = make(map[string]int)
+	)
+
+	return func(c *Context) {
+		key := cfg.KeyFunc(c)
+
+		mu.Lock()
+		if inFlight[key] >= cfg.Max {
+			mu.Unlock()
+			c.Error(http.StatusTooManyRequests, "")
+			c.Abort()
+			return
+		}
+		inFlight[key]++
+		mu.Unlock()
+
+		// Deferred so that a panicking handler still releases its slot.
+		defer func() {
+			mu.Lock()
+			if inFlight[key]--; inFlight[key] <= 0 {
+				delete(inFlight, key)
+			}
+			mu.Unlock()
+		}()
+
+		c.Next()
+	}
+}
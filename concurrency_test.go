@@ -0,0 +1,52 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+func TestConcurrencyLimit(t *testing.T) {
+	release := make(chan struct{})
+	entered := make(chan struct{})
+
+	an := New()
+	an.GET("/slow", ConcurrencyLimit(ConcurrencyConfig{Max: 1}), func(c *Context) {
+		if c.QueryParam("block") != "" {
+			entered <- struct{}{}
+			<-release
+		}
+	})
+
+	serve := func(remote, target string) int {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		req.RemoteAddr = remote
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		serve("192.0.2.1:1000", "/slow?block=1")
+	}()
+	<-entered
+
+	if code := serve("192.0.2.1:1001", "/slow"); code != http.StatusTooManyRequests {
+		t.Errorf("second request from same IP: status = %d", code)
+	}
+
+	if code := serve("192.0.2.2:1000", "/slow"); code != http.StatusOK {
+		t.Errorf("request from other IP: status = %d", code)
+	}
+
+	release <- struct{}{}
+	wg.Wait()
+}
@@ -0,0 +1,134 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ParamConstraint reports whether a path segment is a valid value for a
+// constrained parameter, such as ":id(int)" or ":slug([a-z0-9-]+)". Segments
+// it rejects do not match the route.
+type ParamConstraint func(value string) bool
+
+var (
+	constraintsMu sync.RWMutex
+	constraints   = map[string]ParamConstraint{
+		"int":   isInt,
+		"uint":  isUint,
+		"alpha": isAlpha,
+		"alnum": isAlnum,
+		"uuid":  isUUID,
+	}
+)
+
+// RegisterConstraint makes fn usable as ":param(name)" in route patterns,
+// alongside the built-in int, uint, alpha, alnum and uuid. Constraints must
+// be registered before the routes using them.
+func RegisterConstraint(name string, fn ParamConstraint) {
+	constraintsMu.Lock()
+	defer constraintsMu.Unlock()
+	constraints[name] = fn
+}
+
+// lookupConstraint returns the constraint registered under name.
+func lookupConstraint(name string) (ParamConstraint, bool) {
+	constraintsMu.RLock()
+	defer constraintsMu.RUnlock()
+	fn, ok := constraints[name]
+	return fn, ok
+}
+
+// compileConstraint returns the constraint registered under name or, when
+// there is none, one matching the whole segment against name as a regular
+// expression, as in ":slug([a-z0-9-]+)".
+func compileConstraint(name string) (ParamConstraint, error) {
+	if fn, ok := lookupConstraint(name); ok {
+		return fn, nil
+	}
+	re, err := regexp.Compile("^(?:" + name + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("invalid constraint '%s': %w", name, err)
+	}
+	return re.MatchString, nil
+}
+
+// splitParam splits the "id(int)" of a ":id(int)" segment into the
+// parameter name and its constraint.
+func splitParam(s string) (name, constraint string) {
+	name, constraint, ok := strings.Cut(s, "(")
+	if !ok {
+		return s, ""
+	}
+	if !strings.HasSuffix(constraint, ")") || constraint == ")" {
+		panic(fmt.Sprintf("invalid parameter constraint in ':%s'", s))
+	}
+	return name, constraint[:len(constraint)-1]
+}
+
+func isInt(s string) bool {
+	s = strings.TrimPrefix(s, "-")
+	return isUint(s)
+}
+
+func isUint(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlpha(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i] | 0x20; c < 'a' || c > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlnum(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; !(c >= '0' && c <= '9') && !isAlpha(s[i:i+1]) {
+			return false
+		}
+	}
+	return true
+}
+
+// isUUID accepts the canonical 8-4-4-4-12 hexadecimal form.
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		switch i {
+		case 8, 13, 18, 23:
+			if s[i] != '-' {
+				return false
+			}
+		default:
+			// Fold case for the letters only: 0x10-0x19 would fold to digits.
+			if c := s[i]; !(c >= '0' && c <= '9') && !(c|0x20 >= 'a' && c|0x20 <= 'f') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -6,9 +6,15 @@ package alsonow
 
 import (
 	"context"
+	"crypto/tls"
+	"html/template"
+	"math"
+	"net"
 	"net/http"
+	"net/netip"
 	"net/url"
 	"sync"
+	"time"
 )
 
 // HandlerFunc defines the handler used by router.
@@ -19,14 +25,42 @@ type Context struct {
 	Writer http.ResponseWriter
 	Req    *http.Request
 
-	params map[string]string
+	// resp is the framework's wrapper around the server's writer. Middleware
+	// may replace Writer, resp always stays the innermost one.
+	resp responseWriter
+
+	params []Param
+	route  *Route
+	// mount is the prefix of the mount handling the request, if any.
+	mount string
 
 	// Stores custom data for the request.
 	data map[string]any
 
-	index    int8
+	index    int
 	handlers []HandlerFunc
 	aborted  bool
+	// abortStatus is the status of the AbortWithPriority call of the
+	// highest abortPriority, written once the chain has unwound.
+	abortStatus   int
+	abortPriority int
+	// debug enables the Debugf logs and the tracing of the chain.
+	debug bool
+
+	// Middleware metrics, only used when the router has a sink.
+	metrics   MiddlewareObserver
+	abortedAt int
+	childTime time.Duration
+
+	validator     Validator
+	errorRenderer ErrorRenderer
+	templates     *template.Template
+	exemptPaths   []string
+	publisher     Publisher
+	jsonBodyLimit int64
+
+	// afterCommit holds the functions registered with AfterCommit.
+	afterCommit []func()
 
 	// This mutex protects data map
 	mu sync.RWMutex
@@ -47,7 +81,7 @@ func (c *Context) SetHeader(key, value string) {
 	c.Writer.Header().Set(key, value)
 }
 
-// Status sets the HTTP status code (does not write headers yet).
+// Status writes the response headers with the given HTTP status code.
 func (c *Context) Status(code int) {
 	c.Writer.WriteHeader(code)
 }
@@ -107,17 +141,65 @@ func (c *Context) Method() string {
 	return c.Req.Method
 }
 
-// Param returns the value of a named route parameter.
+// TLS returns the state of the TLS connection, or nil for plain HTTP.
+func (c *Context) TLS() *tls.ConnectionState {
+	return c.Req.TLS
+}
+
+// Protocol returns the protocol of the connection in ALPN notation:
+// "h3", "h2", "h2c" (HTTP/2 without TLS), "http/1.1" or "http/1.0".
+func (c *Context) Protocol() string {
+	switch c.Req.ProtoMajor {
+	case 3:
+		return "h3"
+	case 2:
+		if c.Req.TLS == nil {
+			return "h2c"
+		}
+		return "h2"
+	}
+	if c.Req.ProtoMinor == 0 {
+		return "http/1.0"
+	}
+	return "http/1.1"
+}
+
+// LocalAddr returns the server address the request was received on.
+func (c *Context) LocalAddr() net.Addr {
+	addr, _ := c.Req.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	return addr
+}
+
+// RemoteAddr returns the address of the peer as a *net.TCPAddr, or nil if it
+// cannot be parsed. This is the direct peer, see ClientIP for the client
+// address behind proxies.
+func (c *Context) RemoteAddr() net.Addr {
+	ap, err := netip.ParseAddrPort(c.Req.RemoteAddr)
+	if err != nil {
+		return nil
+	}
+	return net.TCPAddrFromAddrPort(ap)
+}
+
+// Param returns the value of a named route parameter. Routes have few
+// parameters, so they are kept in a slice reused across requests and
+// looked up by a linear scan.
 func (c *Context) Param(key string) string {
-	if c.params == nil {
-		return ""
+	for i := range c.params {
+		if c.params[i].Key == key {
+			return c.params[i].Value
+		}
 	}
-	return c.params[key]
+	return ""
 }
 
-// Params returns the Context params.
+// Params returns a copy of the route parameters.
 func (c *Context) Params() map[string]string {
-	return c.params
+	params := make(map[string]string, len(c.params))
+	for _, p := range c.params {
+		params[p.Key] = p.Value
+	}
+	return params
 }
 
 // QueryParam returns the first value for the named query parameter.
@@ -172,32 +254,70 @@ func (c *Context) Delete(key string) {
 	}
 }
 
-// Next invokes the next handler in the chain.
-func (c *Context) Next() {
-	// If already aborted or request context is done, stop processing
-	if c.aborted {
-		return
-	}
+// abortIndex is past the end of any handler chain. Abort moves the index
+// there so every Next loop on the stack, however deeply nested, stops.
+const abortIndex = math.MaxInt32 / 2
 
+// Next invokes the remaining handlers in the chain. Middleware wrapping
+// Next resume after it returns, but handlers after them are never run twice:
+// the index shared by all nested calls only moves forward.
+func (c *Context) Next() {
 	c.index++
 
-	for c.index < int8(len(c.handlers)) {
-		if c.aborted {
-			return
-		}
-
+	for c.index < len(c.handlers) {
+		// Stop when the client went away.
 		if c.Req.Context().Err() != nil {
 			return
 		}
 
-		c.handlers[c.index](c)
+		if c.debug {
+			c.traceHandler()
+		}
+		if c.metrics != nil {
+			c.runObserved(c.index)
+		} else {
+			c.handlers[c.index](c)
+		}
 		c.index++
 	}
 }
 
-// Abort stops execution of remaining handlers.
+// Abort stops execution of remaining handlers. Handlers already running,
+// such as middleware waiting on Next, still complete.
 func (c *Context) Abort() {
+	if !c.aborted {
+		c.abortedAt = c.index
+	}
 	c.aborted = true
+	c.index = abortIndex
+}
+
+// AbortWithStatus writes the status code and aborts the chain.
+func (c *Context) AbortWithStatus(code int) {
+	c.Status(code)
+	c.Abort()
+}
+
+// AbortWithPriority aborts the chain with status, unless an abort of a
+// higher priority was recorded before. When nested middleware abort in
+// turn, such as a handler answering 404 and the middleware wrapping it
+// answering 401 once Next returns, the status of the highest priority
+// wins, the first one on ties. It is written through the ErrorRenderer
+// once the chain has unwound, unless a response was written already;
+// StatusCode returns it in the meantime.
+func (c *Context) AbortWithPriority(status, priority int) {
+	if c.abortStatus == 0 || priority > c.abortPriority {
+		c.abortStatus, c.abortPriority = status, priority
+	}
+	c.Abort()
+}
+
+// writeAbortStatus writes the status of AbortWithPriority when no response
+// was written.
+func (c *Context) writeAbortStatus() {
+	if c.abortStatus != 0 && !c.Written() {
+		c.Error(c.abortStatus, "")
+	}
 }
 
 // IsAborted reports whether the handler chain has been aborted.
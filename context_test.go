@@ -0,0 +1,31 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Connection(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "[2001:db8::1]:4242"
+	c := &Context{Req: req}
+
+	if c.TLS() != nil || c.Protocol() != "http/1.1" {
+		t.Errorf("plain request: TLS = %v, Protocol = %q", c.TLS(), c.Protocol())
+	}
+	if got := c.RemoteAddr(); got == nil || got.String() != "[2001:db8::1]:4242" {
+		t.Errorf("RemoteAddr = %v", got)
+	}
+
+	req.TLS = &tls.ConnectionState{NegotiatedProtocol: "h2"}
+	req.ProtoMajor, req.ProtoMinor = 2, 0
+	if c.Protocol() != "h2" {
+		t.Errorf("Protocol = %q, want h2", c.Protocol())
+	}
+}
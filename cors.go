@@ -0,0 +1,127 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// skipAccessLogKey marks requests the Logger should not log.
+const skipAccessLogKey = "alsonow.skip_access_log"
+
+// CORSConfig configures CORS.
+type CORSConfig struct {
+	// AllowOrigins lists the allowed origins, such as
+	// "https://app.example.com", or "*" for any.
+	AllowOrigins []string
+	// AllowOriginFunc allows origins dynamically, in addition to
+	// AllowOrigins.
+	AllowOriginFunc func(origin string) bool
+	// AllowMethods defaults to GET, HEAD, POST, PUT, PATCH and DELETE.
+	AllowMethods []string
+	// AllowHeaders lists the request headers allowed in requests. The
+	// headers asked for by the preflight are allowed when empty.
+	AllowHeaders []string
+	// ExposeHeaders lists the response headers scripts may read.
+	ExposeHeaders []string
+	// AllowCredentials lets requests carry cookies and authorization.
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight result, which
+	// saves a round trip per request. Browsers cap it, Chrome at 2h.
+	// Zero omits the header, negative disables caching.
+	MaxAge time.Duration
+	// LogPreflights keeps preflights in the access log.
+	LogPreflights bool
+}
+
+// CORS answers cross-origin requests according to cfg. Preflights are
+// answered right away with 204 and the rest of the chain is skipped, so
+// register CORS before authentication and other costly middlewares. The
+// response headers are computed once, keeping preflights cheap.
+func CORS(cfg CORSConfig) HandlerFunc {
+	if len(cfg.AllowMethods) == 0 {
+		cfg.AllowMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+
+	anyOrigin := false
+	origins := make(map[string]bool, len(cfg.AllowOrigins))
+	for _, o := range cfg.AllowOrigins {
+		if o == "*" {
+			anyOrigin = true
+		}
+		origins[strings.ToLower(o)] = true
+	}
+
+	allowMethods := strings.Join(cfg.AllowMethods, ", ")
+	allowHeaders := strings.Join(cfg.AllowHeaders, ", ")
+	exposeHeaders := strings.Join(cfg.ExposeHeaders, ", ")
+	maxAge := ""
+	switch {
+	case cfg.MaxAge > 0:
+		maxAge = strconv.Itoa(int(cfg.MaxAge.Seconds()))
+	case cfg.MaxAge < 0:
+		maxAge = "0"
+	}
+
+	allowed := func(origin string) bool {
+		return anyOrigin || origins[strings.ToLower(origin)] || (cfg.AllowOriginFunc != nil && cfg.AllowOriginFunc(origin))
+	}
+
+	return func(c *Context) {
+		origin := c.Header("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+
+		h := c.Writer.Header()
+		h.Add("Vary", "Origin")
+		preflight := c.Req.Method == http.MethodOptions && c.Header("Access-Control-Request-Method") != ""
+
+		if allowed(origin) {
+			if anyOrigin && !cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if cfg.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if exposeHeaders != "" {
+					h.Set("Access-Control-Expose-Headers", exposeHeaders)
+				}
+				c.Next()
+				return
+			}
+
+			h.Add("Vary", "Access-Control-Request-Method")
+			h.Add("Vary", "Access-Control-Request-Headers")
+			h.Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+			} else if req := c.Header("Access-Control-Request-Headers"); req != "" {
+				h.Set("Access-Control-Allow-Headers", req)
+			}
+			if maxAge != "" {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+		} else if !preflight {
+			c.Next()
+			return
+		}
+
+		// A preflight of a disallowed origin gets no CORS headers, which
+		// the browser treats as a refusal.
+		if !cfg.LogPreflights {
+			c.Set(skipAccessLogKey, true)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,63 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCORS_Preflight(t *testing.T) {
+	authRan := false
+	an := New()
+	an.Use(CORS(CORSConfig{
+		AllowOrigins:     []string{"https://app.example.com"},
+		AllowCredentials: true,
+		MaxAge:           time.Hour,
+	}))
+	an.Use(func(c *Context) { authRan = true; c.Next() })
+	an.PUT("/items/:id", func(c *Context) {})
+
+	req := httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+	req.Header.Set("Access-Control-Request-Headers", "content-type")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+
+	h := w.Header()
+	if w.Code != http.StatusNoContent || authRan {
+		t.Errorf("status = %d, auth ran = %v", w.Code, authRan)
+	}
+	if h.Get("Access-Control-Allow-Origin") != "https://app.example.com" || h.Get("Access-Control-Allow-Credentials") != "true" ||
+		h.Get("Access-Control-Max-Age") != "3600" || h.Get("Access-Control-Allow-Headers") != "content-type" {
+		t.Errorf("headers = %v", h)
+	}
+
+	req = httptest.NewRequest(http.MethodPut, "/items/1", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Header().Get("Access-Control-Allow-Origin") != "" || !authRan {
+		t.Errorf("disallowed origin: %v", w.Header())
+	}
+}
+
+func BenchmarkCORS_Preflight(b *testing.B) {
+	an := New()
+	an.Use(CORS(CORSConfig{AllowOrigins: []string{"*"}, MaxAge: time.Hour}))
+	an.PUT("/items/:id", func(c *Context) {})
+
+	req := httptest.NewRequest(http.MethodOptions, "/items/1", nil)
+	req.Header.Set("Origin", "https://app.example.com")
+	req.Header.Set("Access-Control-Request-Method", "PUT")
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		an.ServeHTTP(httptest.NewRecorder(), req)
+	}
+}
@@ -0,0 +1,127 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// csrfTokenKey is the Context key of the request's CSRF token.
+const csrfTokenKey = "alsonow.csrf_token"
+
+// csrfExemptMeta is the route metadata set by Route.ExemptCSRF.
+const csrfExemptMeta = "alsonow.csrf_exempt"
+
+// CSRFConfig configures CSRF.
+type CSRFConfig struct {
+	// CookieName holds the token, "alsonow_csrf" when empty.
+	CookieName string
+	// HeaderName and FormField carry the token back on unsafe requests,
+	// "X-CSRF-Token" and "csrf_token" when empty.
+	HeaderName string
+	FormField  string
+	// SameSite of the token cookie, http.SameSiteLaxMode when zero. It
+	// should match the session cookie. SameSite=None requires Secure, which
+	// is then forced.
+	SameSite http.SameSite
+	Secure   bool
+	// ExemptAuthorization skips the check for requests carrying an
+	// Authorization header. Browsers never attach one on their own, so such
+	// requests come from API clients using token authentication. Only enable
+	// it if no CORS policy lets untrusted origins send that header.
+	ExemptAuthorization bool
+	// ErrorHandler is called when the check fails, 403 when nil.
+	ErrorHandler HandlerFunc
+}
+
+// CSRF protects cookie-authenticated forms and XHRs against cross-site
+// request forgery with the double-submit cookie pattern: the token stored in
+// a cookie must be echoed in a header or form field by unsafe requests.
+//
+// API routes using token authentication can share the same stack: mark them
+// with Route.ExemptCSRF, or set ExemptAuthorization.
+func CSRF(cfg CSRFConfig) HandlerFunc {
+	if cfg.CookieName == "" {
+		cfg.CookieName = "alsonow_csrf"
+	}
+	if cfg.HeaderName == "" {
+		cfg.HeaderName = "X-CSRF-Token"
+	}
+	if cfg.FormField == "" {
+		cfg.FormField = "csrf_token"
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+	if cfg.SameSite == http.SameSiteNoneMode {
+		cfg.Secure = true
+	}
+	if cfg.ErrorHandler == nil {
+		cfg.ErrorHandler = func(c *Context) {
+			c.Error(http.StatusForbidden, "invalid CSRF token")
+		}
+	}
+
+	return func(c *Context) {
+		token, err := c.Cookie(cfg.CookieName)
+		if err != nil || len(token) != 64 {
+			token = randomHex(32)
+			c.SetCookie(&http.Cookie{
+				Name:     cfg.CookieName,
+				Value:    token,
+				Path:     "/",
+				HttpOnly: true,
+				Secure:   cfg.Secure,
+				SameSite: cfg.SameSite,
+			})
+		}
+		c.Set(csrfTokenKey, token)
+
+		if !csrfChecked(c, &cfg) {
+			c.Next()
+			return
+		}
+
+		sent := c.Header(cfg.HeaderName)
+		if sent == "" {
+			sent = c.Req.PostFormValue(cfg.FormField)
+		}
+		if err != nil || subtle.ConstantTimeCompare([]byte(sent), []byte(token)) != 1 {
+			cfg.ErrorHandler(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// csrfChecked reports whether the request must carry a valid token.
+func csrfChecked(c *Context, cfg *CSRFConfig) bool {
+	switch c.Req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	if exempt, _ := c.RouteMeta(csrfExemptMeta); exempt == true {
+		return false
+	}
+	if cfg.ExemptAuthorization && c.Header("Authorization") != "" {
+		return false
+	}
+	return true
+}
+
+// CSRFToken returns the token to embed in forms, or "" when the CSRF
+// middleware is not in use.
+func CSRFToken(c *Context) string {
+	token, _ := c.GetString(csrfTokenKey)
+	return token
+}
+
+// ExemptCSRF disables the CSRF check for the route, for endpoints that are
+// not cookie-authenticated such as token-auth APIs or signed webhooks.
+func (r *Route) ExemptCSRF() *Route {
+	return r.SetMeta(csrfExemptMeta, true)
+}
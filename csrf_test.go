@@ -0,0 +1,64 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRF(t *testing.T) {
+	an := New()
+	an.Use(CSRF(CSRFConfig{ExemptAuthorization: true}))
+	an.GET("/form", func(c *Context) { _, _ = c.Writer.Write([]byte(CSRFToken(c))) })
+	an.POST("/form", func(c *Context) {})
+	an.POST("/hooks", func(c *Context) {}).ExemptCSRF()
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/form", nil))
+	token := w.Body.String()
+	cookie := w.Result().Cookies()[0]
+	if cookie.SameSite != http.SameSiteLaxMode || len(token) != 64 {
+		t.Fatalf("cookie = %+v, token = %q", cookie, token)
+	}
+
+	post := func(path string, header http.Header, form url.Values, withCookie bool) int {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		if withCookie {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		header http.Header
+		form   url.Values
+		cookie bool
+		want   int
+	}{
+		{"no token", "/form", nil, nil, true, http.StatusForbidden},
+		{"form token", "/form", nil, url.Values{"csrf_token": {token}}, true, http.StatusOK},
+		{"header token", "/form", http.Header{"X-Csrf-Token": {token}}, nil, true, http.StatusOK},
+		{"token without cookie", "/form", http.Header{"X-Csrf-Token": {token}}, nil, false, http.StatusForbidden},
+		{"bearer auth", "/form", http.Header{"Authorization": {"Bearer x"}}, nil, false, http.StatusOK},
+		{"exempt route", "/hooks", nil, nil, false, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if got := post(tt.path, tt.header, tt.form, tt.cookie); got != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
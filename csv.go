@@ -0,0 +1,104 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/csv"
+	"io"
+	"mime"
+)
+
+const (
+	MIMECSV  = "text/csv; charset=utf-8"
+	MIMEXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// utf8BOM makes Excel detect UTF-8 encoded CSV files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// CSVOptions controls how CSV responses are written.
+type CSVOptions struct {
+	// BOM prefixes the output with a UTF-8 byte order mark.
+	BOM bool
+	// Comma is the field delimiter, ',' when zero.
+	Comma rune
+	// UseCRLF terminates lines with \r\n as RFC 4180 requires.
+	UseCRLF bool
+}
+
+// XLSXWriter encodes rows as an XLSX workbook. alsonow does not ship an
+// implementation; plug in one backed by the library of your choice.
+type XLSXWriter interface {
+	WriteXLSX(w io.Writer, rows [][]string) error
+}
+
+// CSV writes rows as a CSV attachment named filename.
+func (c *Context) CSV(code int, filename string, rows [][]string, opts ...CSVOptions) {
+	c.CSVStream(code, filename, func(w *csv.Writer) error {
+		for _, row := range rows {
+			if err := w.Write(row); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, opts...)
+}
+
+// CSVStream writes a CSV attachment whose rows are produced by fn. Rows are
+// streamed to the client as they are written, so fn may export large data
+// sets without holding them in memory.
+func (c *Context) CSVStream(code int, filename string, fn func(w *csv.Writer) error, opts ...CSVOptions) {
+	var opt CSVOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+
+	c.SetHeader("Content-Type", MIMECSV)
+	c.setAttachment(filename)
+	c.Status(code)
+
+	if opt.BOM {
+		if _, err := c.Writer.Write(utf8BOM); err != nil {
+			return
+		}
+	}
+
+	w := csv.NewWriter(c.Writer)
+	if opt.Comma != 0 {
+		w.Comma = opt.Comma
+	}
+	w.UseCRLF = opt.UseCRLF
+
+	err := fn(w)
+	w.Flush()
+	if err == nil {
+		err = w.Error()
+	}
+	if err != nil {
+		// Headers are already sent, the best we can do is log it.
+		c.Logf("[CSV] %s %s: %v", c.Method(), c.Path(), err)
+	}
+}
+
+// XLSX writes rows as an XLSX attachment named filename using xw.
+func (c *Context) XLSX(code int, filename string, xw XLSXWriter, rows [][]string) {
+	c.SetHeader("Content-Type", MIMEXLSX)
+	c.setAttachment(filename)
+	c.Status(code)
+
+	if err := xw.WriteXLSX(c.Writer, rows); err != nil {
+		c.Logf("[XLSX] %s %s: %v", c.Method(), c.Path(), err)
+	}
+}
+
+// setAttachment sets Content-Disposition so browsers download the response
+// as filename. Non-ASCII names are encoded per RFC 2231.
+func (c *Context) setAttachment(filename string) {
+	if filename == "" {
+		c.SetHeader("Content-Disposition", "attachment")
+		return
+	}
+	c.SetHeader("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+}
@@ -0,0 +1,34 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestContext_CSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := &Context{Writer: w, Req: httptest.NewRequest(http.MethodGet, "/export", nil)}
+
+	c.CSV(http.StatusOK, "report ü.csv", [][]string{
+		{"name", "note"},
+		{"alice", `said "hi", left`},
+	}, CSVOptions{BOM: true})
+
+	if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment; filename*=utf-8''report%20%C3%BC.csv") {
+		t.Errorf("unexpected Content-Disposition %q", cd)
+	}
+
+	body := w.Body.String()
+	if !strings.HasPrefix(body, string(utf8BOM)) {
+		t.Errorf("expected BOM prefix")
+	}
+	if !strings.Contains(body, `alice,"said ""hi"", left"`) {
+		t.Errorf("unexpected quoting: %q", body)
+	}
+}
@@ -0,0 +1,39 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"time"
+)
+
+// RouteTimeouts overrides the server's ReadTimeout and WriteTimeout for the
+// routes it is applied to, e.g. so an SSE stream can outlive the global 30s
+// WriteTimeout without loosening it for the whole server. A zero duration
+// keeps the server setting and a negative one removes the deadline.
+func RouteTimeouts(read, write time.Duration) HandlerFunc {
+	return func(c *Context) {
+		if read != 0 {
+			if err := c.SetReadDeadline(deadlineAfter(read)); err != nil {
+				c.Logf("[TIMEOUT] %s %s: set read deadline: %v", c.Method(), c.Path(), err)
+			}
+		}
+		if write != 0 {
+			if err := c.SetWriteDeadline(deadlineAfter(write)); err != nil {
+				c.Logf("[TIMEOUT] %s %s: set write deadline: %v", c.Method(), c.Path(), err)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// deadlineAfter converts a duration into a deadline, the zero time (no
+// deadline) for negative durations.
+func deadlineAfter(d time.Duration) time.Time {
+	if d < 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(d)
+}
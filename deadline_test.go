@@ -0,0 +1,45 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRouteTimeouts(t *testing.T) {
+	an := New()
+	handler := func(c *Context) {
+		time.Sleep(150 * time.Millisecond)
+		_, _ = c.Writer.Write([]byte("done"))
+	}
+	an.GET("/short", handler)
+	an.GET("/stream", RouteTimeouts(0, time.Second), handler)
+
+	srv := httptest.NewUnstartedServer(an)
+	srv.Config.WriteTimeout = 50 * time.Millisecond
+	srv.Start()
+	defer srv.Close()
+
+	get := func(path string) string {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+
+	if got := get("/short"); got == "done" {
+		t.Errorf("expected the server WriteTimeout to cut /short")
+	}
+	if got := get("/stream"); got != "done" {
+		t.Errorf("/stream body = %q, want %q", got, "done")
+	}
+}
@@ -0,0 +1,88 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DebugConfig configures DebugRequests.
+type DebugConfig struct {
+	// Header carries a token made by SignDebugToken, "X-Debug-Token" when
+	// empty. Tokens are only accepted when Key is set.
+	Header string
+	Key    []byte
+	// SampleRate is the fraction of requests, between 0 and 1, logged at
+	// debug level regardless of the header.
+	SampleRate float64
+	// Sample selects further requests, e.g. those of one tenant.
+	Sample func(*Context) bool
+}
+
+// DebugRequests enables the Debugf logs of individual requests, and the
+// logging of each handler of their chain as it runs: those
+// carrying a valid signed debug token, sampled ones and those selected by
+// cfg.Sample. Production issues can then be diagnosed without enabling
+// debug logs globally. Unsigned or expired tokens are ignored, so clients
+// cannot flood the logs.
+func DebugRequests(cfg DebugConfig) HandlerFunc {
+	if cfg.Header == "" {
+		cfg.Header = "X-Debug-Token"
+	}
+
+	return func(c *Context) {
+		debug := (len(cfg.Key) > 0 && verifyDebugToken(cfg.Key, c.Header(cfg.Header), time.Now())) ||
+			(cfg.SampleRate > 0 && rand.Float64() < cfg.SampleRate) ||
+			(cfg.Sample != nil && cfg.Sample(c))
+		if debug {
+			c.debug = true
+		}
+		c.Next()
+	}
+}
+
+// SignDebugToken returns a token enabling debug logs for the requests
+// carrying it until ttl elapses, to be sent in the DebugConfig.Header.
+func SignDebugToken(key []byte, ttl time.Duration) string {
+	expires := strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return expires + "." + debugSignature(key, expires)
+}
+
+func verifyDebugToken(key []byte, token string, now time.Time) bool {
+	expires, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(debugSignature(key, expires)))
+}
+
+func debugSignature(key []byte, expires string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("alsonow-debug." + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Debug reports whether debug logs are enabled for the request.
+func (c *Context) Debug() bool {
+	return c.debug
+}
+
+// Debugf logs like Logf, with a [DEBUG] prefix, only when debug logs are
+// enabled for the request.
+func (c *Context) Debugf(format string, args ...any) {
+	if c.Debug() {
+		c.Logf("[DEBUG] "+format, args...)
+	}
+}
@@ -0,0 +1,53 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebugRequests(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	key := []byte("secret")
+	an := New()
+	an.Use(DebugRequests(DebugConfig{Key: key, Sample: func(c *Context) bool { return c.Header("X-Tenant") == "acme" }}))
+	an.GET("/", func(c *Context) { c.Debugf("cache miss for %s", c.Header("X-Name")) })
+
+	do := func(name string, header http.Header) {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header = header
+		req.Header.Set("X-Name", name)
+		an.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	do("signed", http.Header{"X-Debug-Token": {SignDebugToken(key, time.Minute)}})
+	do("forged", http.Header{"X-Debug-Token": {SignDebugToken([]byte("other"), time.Minute)}})
+	do("expired", http.Header{"X-Debug-Token": {SignDebugToken(key, -time.Minute)}})
+	do("sampled", http.Header{"X-Tenant": {"acme"}})
+	do("plain", http.Header{})
+
+	out := buf.String()
+	for _, name := range []string{"signed", "sampled"} {
+		if !strings.Contains(out, "[DEBUG] cache miss for "+name) {
+			t.Errorf("missing debug log of %s in %q", name, out)
+		}
+	}
+	if !strings.Contains(out, "[DEBUG] GET / handler 3/3 github.com/alsonow/alsonow.TestDebugRequests.func2 (") {
+		t.Errorf("missing chain trace in %q", out)
+	}
+	for _, name := range []string{"forged", "expired", "plain"} {
+		if strings.Contains(out, "for "+name) {
+			t.Errorf("debug log of %s in %q", name, out)
+		}
+	}
+}
@@ -0,0 +1,141 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// deprecatedMeta is the route metadata set by Route.Deprecated.
+const deprecatedMeta = "alsonow.deprecated"
+
+// maxDeprecatedClients bounds the clients counted per deprecated route,
+// the calls of the others being counted under "other".
+const maxDeprecatedClients = 1000
+
+// DeprecatedRoute reports the usage of a route marked with
+// Route.Deprecated.
+type DeprecatedRoute struct {
+	Method      string
+	Path        string
+	Since       time.Time
+	Sunset      time.Time
+	Replacement string
+	Calls       uint64
+	LastCall    time.Time
+	// Clients counts the calls per client, identified by the Subject of
+	// its Principal or else by its IP.
+	Clients map[string]uint64
+}
+
+// deprecation holds the deprecation of a route and counts its calls.
+type deprecation struct {
+	since, sunset time.Time
+	replacement   string
+
+	mu       sync.Mutex
+	calls    uint64
+	lastCall time.Time
+	clients  map[string]uint64
+}
+
+// Deprecated marks the route as deprecated since the given date, with
+// replacement, the URL of its successor, or "". Its responses carry the
+// Deprecation header and, when set, a Link to the replacement and the
+// Sunset date after which the route may be removed, so clients are warned;
+// the route is also deprecated in the OpenAPI document. The calls are
+// counted per client, and reported by AlsoNow.DeprecatedRoutes to see who
+// still has to migrate.
+func (r *Route) Deprecated(since time.Time, replacement string, sunset ...time.Time) *Route {
+	if v, ok := r.Meta(deprecatedMeta); ok {
+		d := v.(*deprecation)
+		d.mu.Lock()
+		d.since, d.replacement = since, replacement
+		if len(sunset) > 0 {
+			d.sunset = sunset[0]
+		}
+		d.mu.Unlock()
+		return r
+	}
+
+	d := &deprecation{since: since, replacement: replacement, clients: make(map[string]uint64)}
+	if len(sunset) > 0 {
+		d.sunset = sunset[0]
+	}
+	r.SetMeta(deprecatedMeta, d)
+
+	warn := func(c *Context) {
+		h := c.Writer.Header()
+		h.Set("Deprecation", "@"+strconv.FormatInt(d.since.Unix(), 10))
+		if !d.sunset.IsZero() {
+			h.Set("Sunset", d.sunset.UTC().Format(http.TimeFormat))
+		}
+		if d.replacement != "" {
+			h.Add("Link", "<"+d.replacement+`>; rel="successor-version"`)
+		}
+
+		c.Next()
+		// Counted once the chain ran, authentication included.
+		d.count(c)
+	}
+	r.handlers = append([]HandlerFunc{warn}, r.handlers...)
+	return r
+}
+
+func (d *deprecation) count(c *Context) {
+	client := ClientIP(c.Req)
+	if p := c.Principal(); p != nil {
+		client = p.Subject()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.calls++
+	d.lastCall = time.Now()
+	if _, ok := d.clients[client]; !ok && len(d.clients) >= maxDeprecatedClients {
+		client = "other"
+	}
+	d.clients[client]++
+}
+
+// IsDeprecated reports whether the route was marked with Deprecated.
+func (r *Route) IsDeprecated() bool {
+	_, ok := r.Meta(deprecatedMeta)
+	return ok
+}
+
+// DeprecatedRoutes returns the routes marked with Route.Deprecated and
+// their usage, in registration order.
+func (an *AlsoNow) DeprecatedRoutes() []DeprecatedRoute {
+	var routes []DeprecatedRoute
+	for _, route := range an.router().routes {
+		v, ok := route.Meta(deprecatedMeta)
+		if !ok {
+			continue
+		}
+		d := v.(*deprecation)
+
+		d.mu.Lock()
+		clients := make(map[string]uint64, len(d.clients))
+		for k, n := range d.clients {
+			clients[k] = n
+		}
+		routes = append(routes, DeprecatedRoute{
+			Method:      route.Method,
+			Path:        route.Path,
+			Since:       d.since,
+			Sunset:      d.sunset,
+			Replacement: d.replacement,
+			Calls:       d.calls,
+			LastCall:    d.lastCall,
+			Clients:     clients,
+		})
+		d.mu.Unlock()
+	}
+	return routes
+}
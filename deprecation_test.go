@@ -0,0 +1,59 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRoute_Deprecated(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	sunset := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+
+	an := New()
+	an.GET("/v1/users", func(c *Context) {
+		if c.Header("Authorization") != "" {
+			c.SetPrincipal(testPrincipal{})
+		}
+	}).Deprecated(since, "/v2/users", sunset)
+	an.GET("/v2/users", func(c *Context) {})
+
+	do := func(auth string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do("")
+	h := w.Header()
+	if h.Get("Deprecation") != "@1735689600" || h.Get("Sunset") != "Tue, 01 Jul 2025 00:00:00 GMT" ||
+		h.Get("Link") != `</v2/users>; rel="successor-version"` {
+		t.Errorf("headers = %v", h)
+	}
+	do("Bearer token")
+	do("Bearer token")
+
+	routes := an.DeprecatedRoutes()
+	if len(routes) != 1 {
+		t.Fatalf("DeprecatedRoutes() = %+v", routes)
+	}
+	r := routes[0]
+	if r.Path != "/v1/users" || r.Calls != 3 || r.Clients["192.0.2.1"] != 1 || r.Clients["tester"] != 2 || r.LastCall.IsZero() {
+		t.Errorf("DeprecatedRoutes()[0] = %+v", r)
+	}
+
+	doc := an.OpenAPI("test", "1")
+	if !doc.Paths["/v1/users"]["get"].Deprecated || doc.Paths["/v2/users"]["get"].Deprecated {
+		t.Error("OpenAPI deprecation not reported")
+	}
+}
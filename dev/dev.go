@@ -0,0 +1,166 @@
+// Package dev
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package dev tightens the local iteration loop: it reloads templates and
+// configuration when their files change, and rebuilds and restarts the
+// application when its Go files do. It polls the file system, so it needs
+// no platform support, and is not meant for production.
+//
+// Static files need nothing: Static serves them from disk on every request.
+package dev
+
+import (
+	"context"
+	"html/template"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/alsonow/alsonow"
+)
+
+// WatchOptions configures Watch.
+type WatchOptions struct {
+	// Paths are the files and directories to watch, directories being
+	// walked recursively.
+	Paths []string
+	// Exts restricts the watched files to these extensions, such as
+	// ".tmpl". All files when empty.
+	Exts []string
+	// Interval is the polling period, 500ms when zero.
+	Interval time.Duration
+}
+
+// Watch calls fn with the files created, modified or removed since the
+// previous poll, until ctx is done. Hidden directories such as .git are
+// skipped.
+func Watch(ctx context.Context, opts WatchOptions, fn func(changed []string)) {
+	watch(ctx, opts, snapshot(opts), fn)
+}
+
+// watch is Watch starting from the prev snapshot, taken by callers before
+// they load the files so that no change is missed.
+func watch(ctx context.Context, opts WatchOptions, prev map[string]time.Time, fn func(changed []string)) {
+	if opts.Interval <= 0 {
+		opts.Interval = 500 * time.Millisecond
+	}
+
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		cur := snapshot(opts)
+		var changed []string
+		for path, mod := range cur {
+			if old, ok := prev[path]; !ok || !old.Equal(mod) {
+				changed = append(changed, path)
+			}
+		}
+		for path := range prev {
+			if _, ok := cur[path]; !ok {
+				changed = append(changed, path)
+			}
+		}
+		prev = cur
+
+		if len(changed) > 0 {
+			sort.Strings(changed)
+			fn(changed)
+		}
+	}
+}
+
+// snapshot returns the modification time of every watched file.
+func snapshot(opts WatchOptions) map[string]time.Time {
+	files := make(map[string]time.Time)
+	for _, root := range opts.Paths {
+		_ = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return nil
+			}
+			if d.IsDir() {
+				if path != root && len(d.Name()) > 1 && d.Name()[0] == '.' {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if len(opts.Exts) > 0 && !slices.Contains(opts.Exts, filepath.Ext(path)) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				files[path] = info.ModTime()
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// Templates parses the templates matching pattern, such as
+// "views/*.tmpl", sets them on an and parses them again whenever a file of
+// their directory changes, until ctx is done. A template that fails to
+// parse is logged and the previous ones are kept.
+func Templates(ctx context.Context, an *alsonow.AlsoNow, pattern string, funcs template.FuncMap) error {
+	parse := func() (*template.Template, error) {
+		return template.New("").Funcs(funcs).ParseGlob(pattern)
+	}
+
+	opts := WatchOptions{Paths: []string{filepath.Dir(pattern)}}
+	prev := snapshot(opts)
+	t, err := parse()
+	if err != nil {
+		return err
+	}
+	an.WithTemplates(t)
+
+	go watch(ctx, opts, prev, func(changed []string) {
+		t, err := parse()
+		if err != nil {
+			log.Printf("[DEV] templates: %v", err)
+			return
+		}
+		an.WithTemplates(t)
+		log.Printf("[DEV] reloaded templates (%s)", changed[0])
+	})
+	return nil
+}
+
+// OnChange calls load with the content of file now and whenever it
+// changes, until ctx is done, e.g. to reload a configuration file. Errors
+// of load are logged.
+func OnChange(ctx context.Context, file string, load func(data []byte) error) error {
+	opts := WatchOptions{Paths: []string{file}}
+	prev := snapshot(opts)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	if err := load(data); err != nil {
+		return err
+	}
+
+	go watch(ctx, opts, prev, func([]string) {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			err = load(data)
+		}
+		if err != nil {
+			log.Printf("[DEV] %s: %v", file, err)
+			return
+		}
+		log.Printf("[DEV] reloaded %s", file)
+	})
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package dev
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package dev
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/alsonow/alsonow"
+)
+
+func TestTemplates_Reload(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "hello.tmpl")
+	if err := os.WriteFile(file, []byte(`{{define "hello"}}v1{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	an := alsonow.New()
+	an.GET("/", func(c *alsonow.Context) { _ = c.Fragment(http.StatusOK, "hello", nil) })
+	if err := Templates(ctx, an, filepath.Join(dir, "*.tmpl"), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	get := func() string {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Body.String()
+	}
+	if got := get(); got != "v1" {
+		t.Fatalf("body = %q", got)
+	}
+
+	// Make sure the modification time differs on coarse file systems.
+	later := time.Now().Add(time.Second)
+	if err := os.WriteFile(file, []byte(`{{define "hello"}}v2{{end}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Chtimes(file, later, later)
+
+	deadline := time.Now().Add(3 * time.Second)
+	for get() != "v2" {
+		if time.Now().After(deadline) {
+			t.Fatal("templates not reloaded")
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestWatch_Exts(t *testing.T) {
+	dir := t.TempDir()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan []string, 4)
+	go Watch(ctx, WatchOptions{Paths: []string{dir}, Exts: []string{".go"}, Interval: 20 * time.Millisecond},
+		func(changed []string) { changes <- changed })
+
+	time.Sleep(50 * time.Millisecond)
+	_ = os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("x"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644)
+
+	select {
+	case changed := <-changes:
+		if len(changed) != 1 || filepath.Base(changed[0]) != "main.go" {
+			t.Errorf("changed = %v", changed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no change reported")
+	}
+}
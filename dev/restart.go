@@ -0,0 +1,116 @@
+// Package dev
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package dev
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// RunOptions configures Run.
+type RunOptions struct {
+	// Dir is the module directory to watch and build in, "." when empty.
+	Dir string
+	// Package is the main package to build, "." when empty.
+	Package string
+	// Args are passed to the application.
+	Args []string
+	// Exts are the extensions whose changes trigger a restart, ".go" when
+	// empty.
+	Exts []string
+	// Interval is the polling period, 500ms when zero.
+	Interval time.Duration
+	// StopTimeout is how long the application has to exit after an
+	// interrupt before being killed, 5s when zero.
+	StopTimeout time.Duration
+}
+
+// Run builds and starts the application, then rebuilds and restarts it
+// whenever its Go files change, until ctx is done. A failing build is
+// logged and the running process kept. It is typically called from a
+// small command of the project:
+//
+//	// cmd/dev/main.go
+//	func main() {
+//		ctx, _ := signal.NotifyContext(context.Background(), os.Interrupt)
+//		log.Fatal(dev.Run(ctx, dev.RunOptions{Package: "./cmd/server"}))
+//	}
+func Run(ctx context.Context, opts RunOptions) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	if opts.Package == "" {
+		opts.Package = "."
+	}
+	if len(opts.Exts) == 0 {
+		opts.Exts = []string{".go"}
+	}
+	if opts.StopTimeout == 0 {
+		opts.StopTimeout = 5 * time.Second
+	}
+
+	tmp, err := os.MkdirTemp("", "alsonow-dev")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	bin := filepath.Join(tmp, "app")
+
+	var proc *exec.Cmd
+	restart := func() {
+		build := exec.CommandContext(ctx, "go", "build", "-o", bin, opts.Package)
+		build.Dir, build.Stdout, build.Stderr = opts.Dir, os.Stderr, os.Stderr
+		if err := build.Run(); err != nil {
+			log.Printf("[DEV] build failed: %v", err)
+			return
+		}
+
+		stop(proc, opts.StopTimeout)
+		proc = exec.Command(bin, opts.Args...)
+		proc.Dir, proc.Stdin, proc.Stdout, proc.Stderr = opts.Dir, os.Stdin, os.Stdout, os.Stderr
+		if err := proc.Start(); err != nil {
+			log.Printf("[DEV] start: %v", err)
+			proc = nil
+			return
+		}
+		log.Printf("[DEV] started %s (pid %d)", opts.Package, proc.Process.Pid)
+	}
+
+	restart()
+	Watch(ctx, WatchOptions{Paths: []string{opts.Dir}, Exts: opts.Exts, Interval: opts.Interval}, func(changed []string) {
+		log.Printf("[DEV] %s changed, restarting", changed[0])
+		restart()
+	})
+	stop(proc, opts.StopTimeout)
+	return ctx.Err()
+}
+
+// stop interrupts the process, so it can shut down gracefully, and kills
+// it after timeout.
+func stop(proc *exec.Cmd, timeout time.Duration) {
+	if proc == nil || proc.Process == nil {
+		return
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = proc.Wait()
+		close(done)
+	}()
+
+	if err := proc.Process.Signal(os.Interrupt); err != nil {
+		_ = proc.Process.Kill()
+	}
+	select {
+	case <-done:
+	case <-time.After(timeout):
+		_ = proc.Process.Kill()
+		<-done
+	}
+}
@@ -0,0 +1,156 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// RunDevTLS serves HTTPS on addr, ":8443" when empty, with a certificate
+// generated in memory for localhost, 127.0.0.1, ::1 and the host of addr,
+// so secure cookies, HTTP/2 and other HTTPS-only features can be tried
+// locally without any setup. It returns like Run.
+//
+// When the local CA of mkcert is installed, in $CAROOT or the default
+// directory of mkcert, the certificate is signed by it and trusted by the
+// browsers of the machine. It is self-signed otherwise, and browsers warn
+// about it. RunDevTLS is meant for development only.
+func (an *AlsoNow) RunDevTLS(addr string) error {
+	if addr == "" {
+		addr = ":8443"
+	}
+
+	hosts := []string{"localhost", "127.0.0.1", "::1"}
+	if host, _, err := net.SplitHostPort(addr); err == nil && host != "" {
+		hosts = append(hosts, host)
+	}
+
+	ca, err := loadMkcertCA()
+	if err != nil {
+		return fmt.Errorf("mkcert CA error: %w", err)
+	}
+	cert, err := DevCertificate(ca, hosts...)
+	if err != nil {
+		return fmt.Errorf("TLS certificate error: %w", err)
+	}
+	if ca == nil {
+		log.Printf("Serving a self-signed certificate, install the mkcert CA to have browsers trust it")
+	}
+
+	return an.RunTLSWithCertificate(addr, func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return cert, nil
+	})
+}
+
+// DevCertificate generates a certificate for hosts, names or IP addresses,
+// valid for a year and signed by ca, or self-signed when ca is nil.
+func DevCertificate(ca *tls.Certificate, hosts ...string) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{Organization: []string{"alsonow development certificate"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, h := range hosts {
+		if ip := net.ParseIP(h); ip != nil {
+			tmpl.IPAddresses = append(tmpl.IPAddresses, ip)
+		} else {
+			tmpl.DNSNames = append(tmpl.DNSNames, h)
+		}
+	}
+
+	parent, signer := tmpl, any(key)
+	if ca != nil {
+		if parent = ca.Leaf; parent == nil {
+			if parent, err = x509.ParseCertificate(ca.Certificate[0]); err != nil {
+				return nil, err
+			}
+		}
+		signer = ca.PrivateKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, &key.PublicKey, signer)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// loadMkcertCA loads the root CA of mkcert, or returns nil when it is not
+// installed.
+func loadMkcertCA() (*tls.Certificate, error) {
+	dir := mkcertCARoot()
+	if dir == "" {
+		return nil, nil
+	}
+	certFile, keyFile := filepath.Join(dir, "rootCA.pem"), filepath.Join(dir, "rootCA-key.pem")
+	if _, err := os.Stat(keyFile); err != nil {
+		return nil, nil
+	}
+
+	ca, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if ca.Leaf, err = x509.ParseCertificate(ca.Certificate[0]); err != nil {
+		return nil, err
+	}
+	return &ca, nil
+}
+
+// mkcertCARoot returns the directory of the mkcert CA, as mkcert -CAROOT.
+func mkcertCARoot() string {
+	if dir := os.Getenv("CAROOT"); dir != "" {
+		return dir
+	}
+
+	var dir string
+	switch {
+	case runtime.GOOS == "windows":
+		dir = os.Getenv("LocalAppData")
+	case runtime.GOOS == "darwin":
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, "Library", "Application Support")
+		}
+	case os.Getenv("XDG_DATA_HOME") != "":
+		dir = os.Getenv("XDG_DATA_HOME")
+	default:
+		if home, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(home, ".local", "share")
+		}
+	}
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "mkcert")
+}
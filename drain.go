@@ -0,0 +1,65 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultStreamDrainTimeout bounds the drain of streaming responses.
+const defaultStreamDrainTimeout = 5 * time.Second
+
+// WithStreamDrainTimeout sets how long streaming responses, those flushed
+// before completing such as server-sent events, may keep their connection
+// once shutdown starts, 5 seconds by default. Their write deadline is
+// brought forward and their request context cancelled when it elapses,
+// while other requests keep the whole drain window to finish, so one stuck
+// stream cannot hold the shutdown for the whole shutdown timeout. Zero
+// disables the limit.
+func (an *AlsoNow) WithStreamDrainTimeout(d time.Duration) *AlsoNow {
+	an.streamDrainTimeout = d
+	return an
+}
+
+// streamRegistry tracks the responses being streamed.
+type streamRegistry struct {
+	mu      sync.Mutex
+	writers map[*responseWriter]struct{}
+}
+
+// add registers w, on its first flush.
+func (s *streamRegistry) add(w *responseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.writers == nil {
+		s.writers = make(map[*responseWriter]struct{})
+	}
+	s.writers[w] = struct{}{}
+}
+
+// remove unregisters w once its request completes, before it is reused.
+func (s *streamRegistry) remove(w *responseWriter) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.writers, w)
+}
+
+// drain sets the read and write deadlines of the connections of every
+// stream to d from now. Reaching the read deadline cancels the request
+// context, which ends idle streams waiting for events, and reaching the
+// write deadline fails the writes of streams stuck on a slow client.
+func (s *streamRegistry) drain(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := time.Now().Add(d)
+	for w := range s.writers {
+		rc := http.NewResponseController(w.ResponseWriter)
+		_ = rc.SetWriteDeadline(deadline)
+		_ = rc.SetReadDeadline(deadline)
+	}
+}
@@ -0,0 +1,70 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestAlsoNow_StreamDrainTimeout(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	an := New().WithStreamDrainTimeout(100 * time.Millisecond)
+	an.GET("/events", func(c *Context) {
+		c.StartSSE()
+		<-c.Context().Done()
+	})
+	an.GET("/slow", func(c *Context) {
+		time.Sleep(500 * time.Millisecond)
+		_, _ = c.Writer.Write([]byte("done"))
+	})
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+	base := "http://" + ln.Addr().String()
+
+	stream, err := http.Get(base + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+	go func() { _, _ = io.Copy(io.Discard, bufio.NewReader(stream.Body)) }()
+
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(base + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		slow <- string(b)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	start := time.Now()
+	an.Stop()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("RunListener = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("shutdown waited for the stream")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("shutdown took %v", elapsed)
+	}
+	if got := <-slow; got != "done" {
+		t.Errorf("slow request = %q, want it to finish", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorIDHeader carries the reference of a 500 response.
+const ErrorIDHeader = "X-Error-ID"
+
+// HTTPError is an error response, such as the 404, 405, 413, 415 and 500
+// generated by the framework.
+type HTTPError struct {
+	Status int
+	// Message is shown to the client, the status text when empty.
+	Message string
+	// ErrorID references the log entry of a 500.
+	ErrorID string
+}
+
+func (e *HTTPError) Error() string {
+	if e.Message != "" {
+		return strconv.Itoa(e.Status) + " " + e.Message
+	}
+	return strconv.Itoa(e.Status) + " " + http.StatusText(e.Status)
+}
+
+// ErrorRenderer writes the response of an HTTPError.
+type ErrorRenderer func(c *Context, e *HTTPError)
+
+// WithErrorRenderer sets how the errors generated by the framework and by
+// Context.Error are written, RenderError by default.
+func (an *AlsoNow) WithErrorRenderer(r ErrorRenderer) *AlsoNow {
+	an.router().errorRenderer = r
+	return an
+}
+
+// Error writes an error response with status through the ErrorRenderer.
+// message is shown to the client, the status text when empty.
+func (c *Context) Error(status int, message string) {
+	c.renderError(&HTTPError{Status: status, Message: message})
+}
+
+func (c *Context) renderError(e *HTTPError) {
+	if e.ErrorID != "" {
+		c.SetHeader(ErrorIDHeader, e.ErrorID)
+	}
+	if c.errorRenderer != nil {
+		c.errorRenderer(c, e)
+		return
+	}
+	RenderError(c, e)
+}
+
+// RenderError is the default ErrorRenderer. It negotiates the format with
+// the Accept header: problem+json for API clients, a minimal page for
+// browsers and plain text otherwise.
+func RenderError(c *Context, e *HTTPError) {
+	title := http.StatusText(e.Status)
+	detail := e.Message
+	if detail == title {
+		detail = ""
+	}
+
+	switch negotiateError(c.Header("Accept")) {
+	case "json":
+		p := &Problem{Status: e.Status, Title: title, Detail: detail}
+		if e.ErrorID != "" {
+			p.Extensions = map[string]any{"error_id": e.ErrorID}
+		}
+		c.Problem(p)
+	case "html":
+		c.SetHeader("Content-Type", MIMEHTML)
+		c.SetHeader("X-Content-Type-Options", "nosniff")
+		c.Status(e.Status)
+		_ = errorPage.Execute(c.Writer, map[string]any{"Status": e.Status, "Title": title, "Detail": detail, "ErrorID": e.ErrorID})
+	default:
+		msg := title
+		if detail != "" {
+			msg += ": " + detail
+		}
+		if e.ErrorID != "" {
+			msg += " (error ID " + e.ErrorID + ")"
+		}
+		http.Error(c.Writer, msg, e.Status)
+	}
+}
+
+var errorPage = template.Must(template.New("error").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Status}} {{.Title}}</title></head>
+<body><h1>{{.Status}} {{.Title}}</h1>{{with .Detail}}<p>{{.}}</p>{{end}}{{with .ErrorID}}<p>Error ID: <code>{{.}}</code></p>{{end}}</body></html>
+`))
+
+// negotiateError returns "json", "html" or "" for the first JSON or HTML
+// media type accepted.
+func negotiateError(accept string) string {
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err == nil && v == 0 {
+				continue
+			}
+		}
+		mediaType = strings.TrimSpace(mediaType)
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			return "json"
+		case mediaType == "text/html" || mediaType == "application/xhtml+xml":
+			return "html"
+		}
+	}
+	return ""
+}
+
+// InternalServerError logs err under a new short error ID and answers 500
+// with that ID in the X-Error-ID header and the body, so a user report can
+// be matched with the log entry without the error details being exposed.
+// It returns the ID.
+func (c *Context) InternalServerError(err error) string {
+	id := randomHex(4)
+	c.Logf("[ERROR] %s %s: %v error_id=%s", c.Method(), c.Path(), err, id)
+	c.writeServerError(id)
+	return id
+}
+
+// writeServerError answers 500 referencing the error id.
+func (c *Context) writeServerError(id string) {
+	c.renderError(&HTTPError{Status: http.StatusInternalServerError, ErrorID: id})
+}
@@ -0,0 +1,73 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRenderError_Negotiation(t *testing.T) {
+	an := New()
+	an.GET("/users", func(c *Context) {})
+
+	tests := []struct {
+		accept, contentType, body string
+	}{
+		{"application/json", MIMEProblem, `"status":404`},
+		{"text/html,application/xhtml+xml,*/*;q=0.8", MIMEHTML, "<h1>404 Not Found</h1>"},
+		{"application/json;q=0, text/html", MIMEHTML, "<h1>404 Not Found</h1>"},
+		{"*/*", "text/plain; charset=utf-8", "Not Found"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/missing", nil)
+		req.Header.Set("Accept", tt.accept)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != tt.contentType || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("Accept %q: %d %q %q", tt.accept, w.Code, w.Header().Get("Content-Type"), w.Body.String())
+		}
+	}
+}
+
+func TestRouter_MethodNotAllowed(t *testing.T) {
+	var rendered *HTTPError
+	an := New().WithErrorRenderer(func(c *Context, e *HTTPError) {
+		rendered = e
+		c.Status(e.Status)
+	})
+	an.GET("/users/:id", func(c *Context) {})
+	an.DELETE("/users/:id", func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/users/1", nil))
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "DELETE, GET" {
+		t.Errorf("status = %d, Allow = %q", w.Code, w.Header().Get("Allow"))
+	}
+	if rendered == nil || rendered.Status != http.StatusMethodNotAllowed {
+		t.Errorf("custom renderer got %v", rendered)
+	}
+}
+
+func TestInternalServerError_JSON(t *testing.T) {
+	an := New()
+	an.GET("/", func(c *Context) { panic("secret detail") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+
+	id := w.Header().Get(ErrorIDHeader)
+	if w.Code != http.StatusInternalServerError || id == "" || !strings.Contains(w.Body.String(), `"error_id":"`+id+`"`) {
+		t.Errorf("%d %q", w.Code, w.Body.String())
+	}
+	if strings.Contains(w.Body.String(), "secret") {
+		t.Error("panic value leaked to the client")
+	}
+}
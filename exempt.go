@@ -0,0 +1,167 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultExemptPaths are always exempted: ACME HTTP-01 challenges must get
+// through whatever the state of the site for certificates to be issued.
+var defaultExemptPaths = []string{"/.well-known/acme-challenge/*"}
+
+// WithExemptPaths adds paths let through by Maintenance, CanonicalHost and
+// HTTPSRedirect, such as health checks, configured once for all of them:
+//
+//	an.WithExemptPaths("/healthz", "/readyz")
+//
+// Patterns are exact paths, or prefixes when ending with "/*". ACME
+// challenges under /.well-known/acme-challenge/ are always exempted.
+func (an *AlsoNow) WithExemptPaths(patterns ...string) *AlsoNow {
+	r := an.router()
+	for _, p := range patterns {
+		r.exemptPaths = append(r.exemptPaths, normalizePath(p))
+	}
+	return an
+}
+
+// Exempt reports whether the request path is exempted with WithExemptPaths,
+// for middleware other than the built-in ones to honour the same list.
+func (c *Context) Exempt() bool {
+	return isExempt(c.exemptPaths, c.Req.URL.Path)
+}
+
+// isExempt reports whether path matches one of the exempted patterns.
+func isExempt(patterns []string, path string) bool {
+	path = normalizePath(path)
+	for _, p := range patterns {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(path, prefix) || path == strings.TrimSuffix(prefix, "/") {
+				return true
+			}
+		} else if path == p {
+			return true
+		}
+	}
+	return false
+}
+
+// MaintenanceConfig configures Maintenance.
+type MaintenanceConfig struct {
+	// Enabled reports whether the site is in maintenance. It is called for
+	// every request, so maintenance can be toggled at runtime, e.g. with the
+	// Load method of an atomic.Bool. The site always is when nil.
+	Enabled func() bool
+	// RetryAfter is sent in the Retry-After header when positive.
+	RetryAfter time.Duration
+	// Message is shown to clients, the status text when empty.
+	Message string
+}
+
+// Maintenance answers 503 Service Unavailable to the requests other than
+// the exempted ones while the site is in maintenance.
+func Maintenance(cfg MaintenanceConfig) HandlerFunc {
+	retryAfter := ""
+	if cfg.RetryAfter > 0 {
+		retryAfter = strconv.Itoa(int(cfg.RetryAfter.Round(time.Second) / time.Second))
+	}
+
+	return func(c *Context) {
+		if (cfg.Enabled != nil && !cfg.Enabled()) || c.Exempt() {
+			c.Next()
+			return
+		}
+		if retryAfter != "" {
+			c.SetHeader("Retry-After", retryAfter)
+		}
+		c.Error(http.StatusServiceUnavailable, cfg.Message)
+		c.Abort()
+	}
+}
+
+// CanonicalHost redirects the requests for other hosts, such as "www." or
+// alternative domains, to host, keeping their scheme, path and query.
+// Exempted paths are served on any host.
+func CanonicalHost(host string) HandlerFunc {
+	host = strings.ToLower(host)
+
+	return func(c *Context) {
+		if canonicalHost(c.Host()) == canonicalHost(host) || c.Exempt() {
+			c.Next()
+			return
+		}
+		redirect(c, forwardedScheme(c)+"://"+host+c.Req.URL.RequestURI())
+	}
+}
+
+// HTTPSRedirect redirects plain HTTP requests, other than the exempted ones,
+// to HTTPS on the same host. Behind a proxy terminating TLS, the requests
+// carrying "X-Forwarded-Proto: https" are not redirected.
+func HTTPSRedirect() HandlerFunc {
+	return func(c *Context) {
+		if forwardedScheme(c) == "https" || c.Exempt() {
+			c.Next()
+			return
+		}
+		host := c.Host()
+		if h, port, err := net.SplitHostPort(host); err == nil && port == "80" {
+			host = h
+		}
+		redirect(c, "https://"+host+c.Req.URL.RequestURI())
+	}
+}
+
+// httpsRedirectHandler serves the plain HTTP listener of WithHTTPRedirect:
+// exempted paths are served by an, the other requests are redirected to
+// HTTPS on the port of httpsAddr.
+func (an *AlsoNow) httpsRedirectHandler(httpsAddr string) http.Handler {
+	_, port, _ := net.SplitHostPort(httpsAddr)
+	if port == "443" {
+		port = ""
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if isExempt(an.router().exemptPaths, req.URL.Path) {
+			an.ServeHTTP(w, req)
+			return
+		}
+		host := req.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		if port != "" {
+			host = net.JoinHostPort(host, port)
+		}
+		http.Redirect(w, req, "https://"+host+req.URL.RequestURI(), permanentRedirectCode(req.Method))
+	})
+}
+
+// forwardedScheme returns the scheme of the request as sent by the client,
+// "https" when a proxy terminating TLS sets X-Forwarded-Proto.
+func forwardedScheme(c *Context) string {
+	if strings.EqualFold(c.Header("X-Forwarded-Proto"), "https") {
+		return "https"
+	}
+	return c.Scheme()
+}
+
+// redirect permanently redirects the request to target and aborts the chain.
+func redirect(c *Context, target string) {
+	http.Redirect(c.Writer, c.Req, target, permanentRedirectCode(c.Method()))
+	c.Abort()
+}
+
+// permanentRedirectCode returns 301 for GET and HEAD, 308 otherwise so the
+// method and body are kept.
+func permanentRedirectCode(method string) int {
+	if method == http.MethodGet || method == http.MethodHead {
+		return http.StatusMovedPermanently
+	}
+	return http.StatusPermanentRedirect
+}
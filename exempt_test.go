@@ -0,0 +1,64 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestExemptPaths(t *testing.T) {
+	var maintenance atomic.Bool
+	maintenance.Store(true)
+
+	an := New().WithExemptPaths("/healthz")
+	an.Use(HTTPSRedirect())
+	an.Use(CanonicalHost("example.com"))
+	an.Use(Maintenance(MaintenanceConfig{Enabled: maintenance.Load, RetryAfter: time.Minute}))
+	for _, p := range []string{"/", "/healthz", "/.well-known/acme-challenge/:token"} {
+		an.GET(p, func(c *Context) { c.Status(http.StatusOK) })
+	}
+
+	tests := []struct {
+		name, url, proto string
+		code             int
+		location         string
+	}{
+		{"https redirect", "http://www.example.com/?a=1", "", http.StatusMovedPermanently, "https://www.example.com/?a=1"},
+		{"canonical host", "http://www.example.com/?a=1", "https", http.StatusMovedPermanently, "https://example.com/?a=1"},
+		{"maintenance", "http://example.com/", "https", http.StatusServiceUnavailable, ""},
+		{"health check", "http://www.example.com/healthz", "", http.StatusOK, ""},
+		{"acme challenge", "http://www.example.com/.well-known/acme-challenge/abc", "", http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+		if tt.proto != "" {
+			req.Header.Set("X-Forwarded-Proto", tt.proto)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: status = %d, location = %q", tt.name, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Header().Get("Retry-After") != "60" {
+		t.Errorf("Retry-After = %q", w.Header().Get("Retry-After"))
+	}
+
+	maintenance.Store(false)
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("status after maintenance = %d", w.Code)
+	}
+}
@@ -0,0 +1,290 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// Content types of feeds.
+const (
+	MIMERSS  = "application/rss+xml; charset=utf-8"
+	MIMEAtom = "application/atom+xml; charset=utf-8"
+)
+
+// FeedFormat selects the format Context.Feed renders.
+type FeedFormat int
+
+const (
+	// FeedRSS renders RSS 2.0.
+	// https://www.rssboard.org/rss-specification
+	FeedRSS FeedFormat = iota
+	// FeedAtom renders Atom.
+	// https://datatracker.ietf.org/doc/html/rfc4287
+	FeedAtom
+)
+
+// Feed is a feed of items, rendered as RSS or Atom. Build it with NewFeed
+// and the chainable helpers.
+type Feed struct {
+	Format      FeedFormat
+	Title       string
+	Link        string
+	Description string
+	// ID identifies the feed in Atom, Link when empty.
+	ID       string
+	Author   string
+	Language string
+	// Updated is the time of the most recent item when zero.
+	Updated time.Time
+	Items   []*FeedItem
+}
+
+// FeedItem is an entry of a Feed.
+type FeedItem struct {
+	Title string
+	Link  string
+	// ID identifies the item, Link when empty.
+	ID string
+	// Summary is a plain text excerpt, Content the full HTML content.
+	Summary    string
+	Content    string
+	Author     string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+// NewFeed returns an RSS feed.
+func NewFeed(title, link, description string) *Feed {
+	return &Feed{Title: title, Link: link, Description: description}
+}
+
+// Atom makes the feed render as Atom.
+func (f *Feed) Atom() *Feed {
+	f.Format = FeedAtom
+	return f
+}
+
+// Add appends items to the feed.
+func (f *Feed) Add(items ...*FeedItem) *Feed {
+	f.Items = append(f.Items, items...)
+	return f
+}
+
+// Feed writes feed as RSS or Atom, according to its Format, with the given
+// status code.
+func (c *Context) Feed(code int, feed *Feed) {
+	var (
+		v     any
+		ctype string
+	)
+	if feed.Format == FeedAtom {
+		v, ctype = feed.atom(), MIMEAtom
+	} else {
+		v, ctype = feed.rss(), MIMERSS
+	}
+
+	body, err := xml.Marshal(v)
+	if err != nil {
+		c.InternalServerError(err)
+		return
+	}
+
+	c.SetHeader("Content-Type", ctype)
+	c.Status(code)
+	_, _ = c.Writer.Write([]byte(xml.Header))
+	_, _ = c.Writer.Write(body)
+}
+
+// updated returns the Updated time of the feed, or that of its most recent
+// item.
+func (f *Feed) updated() time.Time {
+	t := f.Updated
+	for _, item := range f.Items {
+		if u := item.updated(); u.After(t) {
+			t = u
+		}
+	}
+	return t
+}
+
+func (item *FeedItem) updated() time.Time {
+	if item.Updated.IsZero() {
+		return item.Published
+	}
+	return item.Updated
+}
+
+func (item *FeedItem) id() string {
+	if item.ID == "" {
+		return item.Link
+	}
+	return item.ID
+}
+
+type rssDoc struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Content string     `xml:"xmlns:content,attr,omitempty"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title,omitempty"`
+	Link        string   `xml:"link,omitempty"`
+	Description string   `xml:"description,omitempty"`
+	Content     *cdata   `xml:"content:encoded,omitempty"`
+	Author      string   `xml:"author,omitempty"`
+	Categories  []string `xml:"category,omitempty"`
+	GUID        *rssGUID `xml:"guid,omitempty"`
+	PubDate     string   `xml:"pubDate,omitempty"`
+}
+
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+}
+
+type cdata struct {
+	Value string `xml:",cdata"`
+}
+
+func (f *Feed) rss() rssDoc {
+	doc := rssDoc{Version: "2.0", Channel: rssChannel{
+		Title:       f.Title,
+		Link:        f.Link,
+		Description: f.Description,
+		Language:    f.Language,
+	}}
+	if t := f.updated(); !t.IsZero() {
+		doc.Channel.LastBuildDate = t.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, item := range f.Items {
+		ri := rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Summary,
+			Author:      item.Author,
+			Categories:  item.Categories,
+		}
+		if item.Content != "" {
+			ri.Content = &cdata{item.Content}
+			doc.Content = "http://purl.org/rss/1.0/modules/content/"
+		}
+		if id := item.id(); id != "" {
+			ri.GUID = &rssGUID{Value: id, IsPermaLink: item.ID == ""}
+		}
+		if !item.Published.IsZero() {
+			ri.PubDate = item.Published.UTC().Format(time.RFC1123Z)
+		}
+		doc.Channel.Items = append(doc.Channel.Items, ri)
+	}
+	return doc
+}
+
+type atomDoc struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang     string      `xml:"xml:lang,attr,omitempty"`
+	ID       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Link     *atomLink   `xml:"link,omitempty"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type  string `xml:"type,attr,omitempty"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published,omitempty"`
+	Link       *atomLink      `xml:"link,omitempty"`
+	Author     *atomAuthor    `xml:"author,omitempty"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category,omitempty"`
+}
+
+func (f *Feed) atom() atomDoc {
+	doc := atomDoc{
+		Lang:     f.Language,
+		ID:       f.ID,
+		Title:    f.Title,
+		Subtitle: f.Description,
+		Updated:  atomTime(f.updated()),
+	}
+	if doc.ID == "" {
+		doc.ID = f.Link
+	}
+	if f.Link != "" {
+		doc.Link = &atomLink{Href: f.Link}
+	}
+	if f.Author != "" {
+		doc.Author = &atomAuthor{Name: f.Author}
+	}
+
+	for _, item := range f.Items {
+		e := atomEntry{ID: item.id(), Title: item.Title, Updated: atomTime(item.updated())}
+		if !item.Published.IsZero() {
+			e.Published = atomTime(item.Published)
+		}
+		if item.Link != "" {
+			e.Link = &atomLink{Href: item.Link}
+		}
+		if item.Author != "" {
+			e.Author = &atomAuthor{Name: item.Author}
+		}
+		if item.Summary != "" {
+			e.Summary = &atomText{Value: item.Summary}
+		}
+		if item.Content != "" {
+			e.Content = &atomText{Type: "html", Value: item.Content}
+		}
+		for _, term := range item.Categories {
+			e.Categories = append(e.Categories, atomCategory{Term: term})
+		}
+		doc.Entries = append(doc.Entries, e)
+	}
+	return doc
+}
+
+// atomTime formats t as an RFC 3339 date, required in Atom even when
+// unknown.
+func atomTime(t time.Time) string {
+	if t.IsZero() {
+		t = time.Unix(0, 0)
+	}
+	return t.UTC().Format(time.RFC3339)
+}
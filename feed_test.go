@@ -0,0 +1,60 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestContext_Feed(t *testing.T) {
+	published := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	feed := func() *Feed {
+		return NewFeed("Blog", "https://example.com/", "Notes").Add(&FeedItem{
+			Title:      "Hello",
+			Link:       "https://example.com/posts/hello",
+			Summary:    "First post",
+			Content:    "<p>Hi & welcome</p>",
+			Categories: []string{"news"},
+			Published:  published,
+		})
+	}
+
+	an := New()
+	an.GET("/rss", func(c *Context) { c.Feed(http.StatusOK, feed()) })
+	an.GET("/atom", func(c *Context) { c.Feed(http.StatusOK, feed().Atom()) })
+
+	tests := []struct {
+		path, ctype string
+		want        []string
+	}{
+		{"/rss", MIMERSS, []string{
+			`<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"><channel><title>Blog</title>`,
+			`<lastBuildDate>Sat, 01 Mar 2025 12:00:00 +0000</lastBuildDate>`,
+			`<content:encoded><![CDATA[<p>Hi & welcome</p>]]></content:encoded>`,
+			`<category>news</category><guid isPermaLink="true">https://example.com/posts/hello</guid>`,
+		}},
+		{"/atom", MIMEAtom, []string{
+			`<feed xmlns="http://www.w3.org/2005/Atom"><id>https://example.com/</id><title>Blog</title><subtitle>Notes</subtitle><updated>2025-03-01T12:00:00Z</updated>`,
+			`<entry><id>https://example.com/posts/hello</id><title>Hello</title><updated>2025-03-01T12:00:00Z</updated>`,
+			`<content type="html">&lt;p&gt;Hi &amp; welcome&lt;/p&gt;</content><category term="news"></category>`,
+		}},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Header().Get("Content-Type") != tt.ctype {
+			t.Errorf("%s: Content-Type %q", tt.path, w.Header().Get("Content-Type"))
+		}
+		for _, s := range tt.want {
+			if !strings.Contains(w.Body.String(), s) {
+				t.Errorf("%s: body lacks %s\n%s", tt.path, s, w.Body.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,208 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MIMEHAL is the media type of HAL documents.
+// https://datatracker.ietf.org/doc/html/draft-kelly-json-hal
+const MIMEHAL = "application/hal+json"
+
+// HALLink is a single HAL link object.
+type HALLink struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+	Title     string `json:"title,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+// HALResource is a HAL resource: the state of v plus its _links and _embedded
+// sections. Build it with NewHALResource and the chainable helpers.
+type HALResource struct {
+	state    any
+	links    map[string][]HALLink
+	embedded map[string][]*HALResource
+	many     map[string]bool
+}
+
+// NewHALResource returns a HAL resource whose properties are taken from state.
+// state must marshal to a JSON object, or be nil.
+func NewHALResource(state any) *HALResource {
+	return &HALResource{state: state}
+}
+
+// Link adds a link for the given relation. Adding several links to the same
+// relation renders them as an array.
+func (r *HALResource) Link(rel string, link HALLink) *HALResource {
+	if r.links == nil {
+		r.links = make(map[string][]HALLink)
+	}
+	r.links[rel] = append(r.links[rel], link)
+	return r
+}
+
+// Self is shorthand for Link("self", HALLink{Href: href}).
+func (r *HALResource) Self(href string) *HALResource {
+	return r.Link("self", HALLink{Href: href})
+}
+
+// LinkRoute adds a link built from a route pattern such as "/users/:id",
+// filling parameters from key/value pairs. The link is not added when a
+// parameter is missing.
+func (r *HALResource) LinkRoute(rel, pattern string, pairs ...string) error {
+	href, err := expandPattern(pattern, pairs...)
+	if err != nil {
+		return err
+	}
+	r.Link(rel, HALLink{Href: href})
+	return nil
+}
+
+// LinkNamed adds a link to the route named name with Route.Name, built by
+// AlsoNow.URL, so handlers do not repeat the patterns of the routes:
+//
+//	an.GET("/orders/:id", showOrder).Name("order")
+//	err := res.LinkNamed(an, "self", "order", "id", "123")
+func (r *HALResource) LinkNamed(an *AlsoNow, rel, name string, pairs ...string) error {
+	href, err := an.URL(name, pairs...)
+	if err != nil {
+		return err
+	}
+	r.Link(rel, HALLink{Href: href})
+	return nil
+}
+
+// Embed embeds a single resource under rel.
+func (r *HALResource) Embed(rel string, res *HALResource) *HALResource {
+	if r.embedded == nil {
+		r.embedded = make(map[string][]*HALResource)
+	}
+	r.embedded[rel] = []*HALResource{res}
+	return r
+}
+
+// EmbedMany embeds a collection of resources under rel. The collection is
+// always rendered as an array, even when it has a single element.
+func (r *HALResource) EmbedMany(rel string, res ...*HALResource) *HALResource {
+	if r.embedded == nil {
+		r.embedded = make(map[string][]*HALResource)
+	}
+	if r.many == nil {
+		r.many = make(map[string]bool)
+	}
+	r.embedded[rel] = append(r.embedded[rel], res...)
+	r.many[rel] = true
+	return r
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r *HALResource) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any)
+
+	if r.state != nil {
+		raw, err := json.Marshal(r.state)
+		if err != nil {
+			return nil, err
+		}
+		if string(raw) != "null" {
+			if err := json.Unmarshal(raw, &out); err != nil {
+				return nil, fmt.Errorf("hal: state must be a JSON object: %w", err)
+			}
+		}
+	}
+
+	if len(r.links) > 0 {
+		links := make(map[string]any, len(r.links))
+		for rel, l := range r.links {
+			if len(l) == 1 {
+				links[rel] = l[0]
+			} else {
+				links[rel] = l
+			}
+		}
+		out["_links"] = links
+	}
+
+	if len(r.embedded) > 0 {
+		embedded := make(map[string]any, len(r.embedded))
+		for rel, res := range r.embedded {
+			if len(res) == 1 && !r.many[rel] {
+				embedded[rel] = res[0]
+			} else {
+				embedded[rel] = res
+			}
+		}
+		out["_embedded"] = embedded
+	}
+
+	return json.Marshal(out)
+}
+
+// HAL writes res as a HAL document with the given status code.
+func (c *Context) HAL(code int, res *HALResource) {
+	body, err := json.Marshal(res)
+	if err != nil {
+		c.InternalServerError(err)
+		return
+	}
+
+	c.SetHeader("Content-Type", MIMEHAL)
+	c.Status(code)
+	_, _ = c.Writer.Write(body)
+}
+
+// expandPattern builds a concrete path from a route pattern by substituting
+// each :name or *name segment with the value paired with name. Catch-all
+// values may span several segments.
+func expandPattern(pattern string, pairs ...string) (string, error) {
+	if len(pairs)%2 != 0 {
+		return "", fmt.Errorf("odd number of parameter pairs for %q", pattern)
+	}
+
+	values := make(map[string]string, len(pairs)/2)
+	for i := 0; i < len(pairs); i += 2 {
+		values[pairs[i]] = pairs[i+1]
+	}
+
+	pattern, err := TranslatePattern(pattern)
+	if err != nil {
+		return "", err
+	}
+	pattern = normalizePath(pattern)
+	if pattern == "/" {
+		return pattern, nil
+	}
+
+	segments := strings.Split(pattern[1:], "/")
+	for i, segment := range segments {
+		if segment[0] != ':' && segment[0] != '*' {
+			continue
+		}
+		name := catchAllName(segment)
+		if segment[0] == ':' {
+			name, _ = splitParam(name)
+		}
+		v, ok := values[name]
+		if !ok {
+			return "", fmt.Errorf("missing value for parameter %q in %q", name, pattern)
+		}
+		if segment[0] == ':' {
+			segments[i] = url.PathEscape(v)
+			continue
+		}
+		parts := strings.Split(strings.TrimPrefix(v, "/"), "/")
+		for j, part := range parts {
+			parts[j] = url.PathEscape(part)
+		}
+		segments[i] = strings.Join(parts, "/")
+	}
+
+	return "/" + strings.Join(segments, "/"), nil
+}
@@ -0,0 +1,73 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestHAL_MarshalJSON(t *testing.T) {
+	order := NewHALResource(map[string]any{"total": 30})
+	if err := order.LinkRoute("self", "/orders/:id", "id", "123"); err != nil {
+		t.Fatal(err)
+	}
+	res := NewHALResource(struct {
+		Count int `json:"count"`
+	}{Count: 1}).
+		Self("/orders").
+		EmbedMany("orders", order)
+
+	raw, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var got struct {
+		Count    int                `json:"count"`
+		Links    map[string]HALLink `json:"_links"`
+		Embedded map[string][]struct {
+			Links map[string]HALLink `json:"_links"`
+		} `json:"_embedded"`
+	}
+	if err := json.Unmarshal(raw, &got); err != nil {
+		t.Fatalf("invalid document %s: %v", raw, err)
+	}
+
+	if got.Count != 1 || got.Links["self"].Href != "/orders" {
+		t.Errorf("unexpected document: %s", raw)
+	}
+	if href := got.Embedded["orders"][0].Links["self"].Href; href != "/orders/123" {
+		t.Errorf("embedded self = %q, want %q", href, "/orders/123")
+	}
+}
+
+func TestHAL_expandPattern(t *testing.T) {
+	if got, _ := expandPattern("/users/:id/posts/:post", "id", "a b", "post", "7"); got != "/users/a%20b/posts/7" {
+		t.Errorf("unexpected path %q", got)
+	}
+	if _, err := expandPattern("/users/:id"); err == nil {
+		t.Errorf("expected error for missing parameter")
+	}
+}
+
+func TestHAL_LinkRouteErrors(t *testing.T) {
+	an := New()
+	an.GET("/orders/:id", func(c *Context) {}).Name("order")
+
+	res := NewHALResource(nil)
+	if err := res.LinkRoute("self", "/orders/:id"); err == nil {
+		t.Error("LinkRoute without the id did not fail")
+	}
+	if err := res.LinkNamed(an, "self", "missing"); err == nil {
+		t.Error("LinkNamed of an unknown route did not fail")
+	}
+	if err := res.LinkNamed(an, "self", "order", "id", "7"); err != nil {
+		t.Fatal(err)
+	}
+	if len(res.links["self"]) != 1 || res.links["self"][0].Href != "/orders/7" {
+		t.Errorf("links = %v", res.links)
+	}
+}
@@ -0,0 +1,105 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// hostRoutes holds the routes of a Host pattern.
+type hostRoutes struct {
+	// pattern is the pattern as registered, such as "api.example.com" or
+	// ":tenant.example.com".
+	pattern string
+	// param names the parameter capturing the first label of the host for
+	// ":name." patterns.
+	param   string
+	matcher MatcherBackend
+}
+
+// Host returns a group whose routes only match requests for the host
+// pattern, so one server can serve api., admin. and www. variants:
+//
+//	api := an.Host("api.example.com")
+//	api.GET("/users/:id", showUser)
+//
+// A first label of "*" matches any single label, ":name" also captures it
+// as a parameter:
+//
+//	tenants := an.Host(":tenant.example.com")
+//	tenants.GET("/", home) // c.Param("tenant")
+//
+// Host routes take precedence over the routes of any host, which still
+// serve the requests the host has no route for. Ports are ignored and
+// hosts compare case-insensitively. Exact patterns win over wildcards.
+func (r *routerImpl) Host(pattern string, m ...HandlerFunc) *Group {
+	return &Group{
+		prefix:      "/",
+		middlewares: m,
+		router:      r,
+		host:        r.hostRoutes(pattern),
+	}
+}
+
+// hostRoutes returns the routes of pattern, creating them on first use.
+func (r *routerImpl) hostRoutes(pattern string) *hostRoutes {
+	key, param := strings.TrimSuffix(strings.TrimSpace(pattern), "."), ""
+	if key == "" {
+		panic("alsonow: empty host pattern")
+	}
+	if label, suffix, ok := strings.Cut(key, "."); ok && (label == "*" || strings.HasPrefix(label, ":")) {
+		key, param = "*."+strings.ToLower(suffix), strings.TrimPrefix(label, ":")
+		if label == ":" {
+			panic(fmt.Sprintf("alsonow: empty parameter name in host pattern %q", pattern))
+		}
+	} else {
+		key = strings.ToLower(key)
+	}
+	if strings.ContainsAny(strings.TrimPrefix(key, "*."), "*:") {
+		panic(fmt.Sprintf("alsonow: invalid host pattern %q, only the first label can be a wildcard", pattern))
+	}
+
+	if h, ok := r.hosts[key]; ok {
+		if h.param != param {
+			panic(fmt.Sprintf("alsonow: host pattern %q conflicts with existing %q", pattern, h.pattern))
+		}
+		return h
+	}
+	if r.hosts == nil {
+		r.hosts = make(map[string]*hostRoutes)
+	}
+	h := &hostRoutes{pattern: pattern, param: param, matcher: r.newMatcher()}
+	r.hosts[key] = h
+	return h
+}
+
+// matchHost returns the routes of the pattern matching the Host header
+// host, and the first label of host for wildcard patterns.
+func (r *routerImpl) matchHost(host string) (*hostRoutes, string) {
+	if len(r.hosts) == 0 {
+		return nil, ""
+	}
+	host = canonicalHost(host)
+	if h, ok := r.hosts[host]; ok {
+		return h, ""
+	}
+	if label, suffix, ok := strings.Cut(host, "."); ok && label != "" {
+		if h, ok := r.hosts["*."+suffix]; ok {
+			return h, label
+		}
+	}
+	return nil, ""
+}
+
+// canonicalHost lowercases host and strips its port and trailing dot.
+func canonicalHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(host, ".")
+}
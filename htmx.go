@@ -0,0 +1,110 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+)
+
+// MIMEHTML is the content type of HTML responses.
+const MIMEHTML = "text/html; charset=utf-8"
+
+// hxTriggerKey is the Context key of the events sent with HX-Trigger.
+const hxTriggerKey = "alsonow.hx_trigger"
+
+// errNoTemplates is returned by Fragment when WithTemplates was not called.
+var errNoTemplates = errors.New("alsonow: no templates, see WithTemplates")
+
+// WithTemplates sets the templates rendered by Context.Fragment. It may be
+// called again while serving to reload them.
+//
+//	an.WithTemplates(template.Must(template.ParseFS(views, "views/*.tmpl")))
+func (an *AlsoNow) WithTemplates(t *template.Template) *AlsoNow {
+	an.router().templates.Store(t)
+	return an
+}
+
+// Fragment renders the template name, a file or a {{define}} block of the
+// templates, with the status code. It writes only that template, without
+// any layout, as htmx swaps expect. The template is executed before
+// anything is sent, so a failing one gives the client a 500.
+func (c *Context) Fragment(code int, name string, data any) error {
+	if c.templates == nil {
+		c.InternalServerError(errNoTemplates)
+		return errNoTemplates
+	}
+
+	var buf bytes.Buffer
+	if err := c.templates.ExecuteTemplate(&buf, name, data); err != nil {
+		c.InternalServerError(fmt.Errorf("render template %s: %w", name, err))
+		return err
+	}
+
+	c.SetHeader("Content-Type", MIMEHTML)
+	c.Status(code)
+	_, err := c.Writer.Write(buf.Bytes())
+	return err
+}
+
+// IsHTMX reports whether the request was made by htmx, so handlers can
+// answer with a fragment instead of the full page.
+func (c *Context) IsHTMX() bool {
+	return c.Header("HX-Request") == "true"
+}
+
+// HXTarget returns the id of the element targeted by the htmx request.
+func (c *Context) HXTarget() string {
+	return c.Header("HX-Target")
+}
+
+// HXTrigger makes htmx trigger event on the client once the response is
+// swapped in, with detail as the event detail when not nil. It may be
+// called several times; it must be called before the body is written.
+func (c *Context) HXTrigger(event string, detail any) {
+	events, _ := c.Get(hxTriggerKey)
+	m, _ := events.(map[string]any)
+	if m == nil {
+		m = make(map[string]any)
+		c.Set(hxTriggerKey, m)
+	}
+	m[event] = detail
+
+	b, err := json.Marshal(m)
+	if err != nil {
+		c.Logf("[HTMX] trigger %s: %v", event, err)
+		return
+	}
+	c.SetHeader("HX-Trigger", string(b))
+}
+
+// HXRedirect makes htmx load url as a full page.
+func (c *Context) HXRedirect(url string) {
+	c.SetHeader("HX-Redirect", url)
+}
+
+// HXRefresh makes htmx reload the whole page.
+func (c *Context) HXRefresh() {
+	c.SetHeader("HX-Refresh", "true")
+}
+
+// HXPushURL pushes url onto the browser history.
+func (c *Context) HXPushURL(url string) {
+	c.SetHeader("HX-Push-Url", url)
+}
+
+// HXRetarget swaps the response into the elements matching selector
+// instead of the request's target.
+func (c *Context) HXRetarget(selector string) {
+	c.SetHeader("HX-Retarget", selector)
+}
+
+// HXReswap overrides how the response is swapped, e.g. "outerHTML".
+func (c *Context) HXReswap(swap string) {
+	c.SetHeader("HX-Reswap", swap)
+}
@@ -0,0 +1,58 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_Fragment(t *testing.T) {
+	tmpl := template.Must(template.New("page").Parse(
+		`<html>{{template "row" .}}</html>{{define "row"}}<tr>{{.}}</tr>{{end}}`))
+
+	an := New().WithTemplates(tmpl)
+	an.POST("/rows", func(c *Context) {
+		if !c.IsHTMX() {
+			_ = c.Fragment(http.StatusOK, "page", "full")
+			return
+		}
+		c.HXTrigger("rowAdded", nil)
+		c.HXTrigger("flash", map[string]string{"msg": "saved"})
+		_ = c.Fragment(http.StatusCreated, "row", "<b>")
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/rows", nil)
+	req.Header.Set("HX-Request", "true")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated || w.Body.String() != "<tr>&lt;b&gt;</tr>" {
+		t.Errorf("fragment: %d %q", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("HX-Trigger"); got != `{"flash":{"msg":"saved"},"rowAdded":null}` {
+		t.Errorf("HX-Trigger = %s", got)
+	}
+
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/rows", nil))
+	if w.Body.String() != "<html><tr>full</tr></html>" || w.Header().Get("Content-Type") != MIMEHTML {
+		t.Errorf("page: %q", w.Body.String())
+	}
+}
+
+func TestContext_FragmentMissing(t *testing.T) {
+	an := New().WithTemplates(template.Must(template.New("a").Parse("a")))
+	var err error
+	an.GET("/", func(c *Context) { err = c.Fragment(http.StatusOK, "nope", nil) })
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if err == nil || w.Code != http.StatusInternalServerError {
+		t.Errorf("err = %v, status = %d", err, w.Code)
+	}
+}
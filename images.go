@@ -0,0 +1,335 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif" // register decoder
+	"image/jpeg"
+	"image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/alsonow/alsonow/cache"
+)
+
+// Image fit modes.
+const (
+	FitContain = "contain" // scale to fit inside the box, keeping aspect ratio
+	FitCrop    = "crop"    // scale to cover the box and crop the overflow
+	FitFill    = "fill"    // stretch to the box
+)
+
+// ImageTransform describes the transformation requested through the query
+// string: w, h, fit, fmt and q.
+type ImageTransform struct {
+	Width   int
+	Height  int
+	Fit     string
+	Format  string
+	Quality int
+}
+
+// ImageProcessor applies t to the encoded image read from src and writes the
+// result to dst, returning the content type of the output.
+type ImageProcessor interface {
+	Process(dst io.Writer, src io.Reader, t ImageTransform) (contentType string, err error)
+}
+
+// ImageOptions configures Images.
+type ImageOptions struct {
+	// Processor transforms images, StdImageProcessor when nil.
+	Processor ImageProcessor
+	// SignKey, when set, requires every request to carry an "s" parameter
+	// produced by SignImageURL with the same key.
+	SignKey []byte
+	// CacheSize is the number of transformed images kept in memory, 128 when zero.
+	// A negative value disables caching.
+	CacheSize int
+	// MaxWidth and MaxHeight bound the requested dimensions, 4096 when zero.
+	MaxWidth  int
+	MaxHeight int
+	// MaxPixels bounds the width × height of the source images, which are
+	// decoded whole in memory, 40 million when zero. Larger sources, such
+	// as a small PNG declaring huge dimensions, are refused with 422
+	// before being decoded.
+	MaxPixels int
+}
+
+var (
+	errImageBadParam  = errors.New("invalid image parameter")
+	errImageBadFormat = errors.New("unsupported image format")
+)
+
+// Images registers a GET route under prefix serving images from source,
+// resized and cropped on the fly according to the query string. Images may
+// live in subdirectories of source.
+func (an *AlsoNow) Images(prefix string, source fs.FS, opts ImageOptions) {
+	an.GET(strings.TrimSuffix(normalizePath(prefix), "/")+"/*image", imageHandler(source, opts))
+}
+
+// SignImageURL returns path with the query for t and its signature appended.
+func SignImageURL(key []byte, path string, t ImageTransform) string {
+	q := t.values()
+	q.Set("s", signImage(key, path, q))
+	return path + "?" + q.Encode()
+}
+
+func imageHandler(source fs.FS, opts ImageOptions) HandlerFunc {
+	if opts.Processor == nil {
+		opts.Processor = StdImageProcessor{}
+	}
+	if opts.CacheSize == 0 {
+		opts.CacheSize = 128
+	}
+	if opts.MaxWidth == 0 {
+		opts.MaxWidth = 4096
+	}
+	if opts.MaxHeight == 0 {
+		opts.MaxHeight = 4096
+	}
+	if opts.MaxPixels == 0 {
+		opts.MaxPixels = 40_000_000
+	}
+
+	var images *cache.Memory[string, *cachedImage]
+	if opts.CacheSize > 0 {
+		images = cache.NewMemory(cache.Options[string, *cachedImage]{MaxEntries: opts.CacheSize})
+	}
+
+	return func(c *Context) {
+		name := c.Param("image")
+		query := c.QueryAll()
+
+		if opts.SignKey != nil {
+			sig := query.Get("s")
+			query.Del("s")
+			if !hmac.Equal([]byte(sig), []byte(signImage(opts.SignKey, c.Path(), query))) {
+				c.Error(http.StatusForbidden, "")
+				return
+			}
+		}
+
+		t, err := parseImageTransform(query)
+		if err != nil || t.Width > opts.MaxWidth || t.Height > opts.MaxHeight {
+			c.Error(http.StatusBadRequest, "")
+			return
+		}
+
+		key := name + "?" + t.values().Encode()
+		if images != nil {
+			if img, ok := images.Get(key); ok {
+				writeImage(c, img)
+				return
+			}
+		}
+
+		f, err := source.Open(name)
+		if err != nil {
+			c.Error(http.StatusNotFound, "")
+			return
+		}
+		defer f.Close()
+
+		// The header is checked before anything is decoded, then replayed
+		// to the processor.
+		var head bytes.Buffer
+		cfg, _, err := image.DecodeConfig(io.TeeReader(f, &head))
+		if errors.Is(err, image.ErrFormat) {
+			c.Error(http.StatusUnsupportedMediaType, "")
+			return
+		}
+		if err != nil || cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width > opts.MaxPixels/cfg.Height {
+			c.Error(http.StatusUnprocessableEntity, "")
+			return
+		}
+
+		var buf bytes.Buffer
+		ct, err := opts.Processor.Process(&buf, io.MultiReader(&head, f), t)
+		if errors.Is(err, errImageBadFormat) || errors.Is(err, image.ErrFormat) {
+			c.Error(http.StatusUnsupportedMediaType, "")
+			return
+		}
+		if err != nil {
+			c.InternalServerError(err)
+			return
+		}
+
+		img := &cachedImage{contentType: ct, data: buf.Bytes()}
+		if images != nil {
+			images.Set(key, img)
+		}
+		writeImage(c, img)
+	}
+}
+
+type cachedImage struct {
+	contentType string
+	data        []byte
+}
+
+func writeImage(c *Context, img *cachedImage) {
+	c.SetHeader("Content-Type", img.contentType)
+	c.SetHeader("Content-Length", strconv.Itoa(len(img.data)))
+	c.SetHeader("Cache-Control", "public, max-age=86400")
+	c.Status(http.StatusOK)
+	_, _ = c.Writer.Write(img.data)
+}
+
+func parseImageTransform(q url.Values) (ImageTransform, error) {
+	var t ImageTransform
+	atoi := func(key string) (int, error) {
+		v := q.Get(key)
+		if v == "" {
+			return 0, nil
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, errImageBadParam
+		}
+		return n, nil
+	}
+
+	var err error
+	if t.Width, err = atoi("w"); err != nil {
+		return t, err
+	}
+	if t.Height, err = atoi("h"); err != nil {
+		return t, err
+	}
+	if t.Quality, err = atoi("q"); err != nil || t.Quality > 100 {
+		return t, errImageBadParam
+	}
+
+	t.Fit = q.Get("fit")
+	switch t.Fit {
+	case "", FitContain, FitCrop, FitFill:
+	default:
+		return t, errImageBadParam
+	}
+
+	t.Format = q.Get("fmt")
+	switch t.Format {
+	case "", "jpeg", "png":
+	default:
+		return t, errImageBadParam
+	}
+	return t, nil
+}
+
+func (t ImageTransform) values() url.Values {
+	q := url.Values{}
+	if t.Width > 0 {
+		q.Set("w", strconv.Itoa(t.Width))
+	}
+	if t.Height > 0 {
+		q.Set("h", strconv.Itoa(t.Height))
+	}
+	if t.Fit != "" {
+		q.Set("fit", t.Fit)
+	}
+	if t.Format != "" {
+		q.Set("fmt", t.Format)
+	}
+	if t.Quality > 0 {
+		q.Set("q", strconv.Itoa(t.Quality))
+	}
+	return q
+}
+
+func signImage(key []byte, path string, q url.Values) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path + "?" + q.Encode()))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// StdImageProcessor is the default ImageProcessor. It decodes JPEG, PNG and
+// GIF with the standard library and resamples with nearest-neighbour, which
+// is fast but not the best quality; plug in a dedicated backend for that.
+// GIF sources are re-encoded as PNG, keeping their first frame only.
+type StdImageProcessor struct{}
+
+// Process implements ImageProcessor.
+func (StdImageProcessor) Process(dst io.Writer, src io.Reader, t ImageTransform) (string, error) {
+	img, format, err := image.Decode(src)
+	if err != nil {
+		return "", err
+	}
+
+	img = transformImage(img, t)
+
+	if t.Format != "" {
+		format = t.Format
+	}
+	switch format {
+	case "jpeg":
+		q := t.Quality
+		if q == 0 {
+			q = jpeg.DefaultQuality
+		}
+		return "image/jpeg", jpeg.Encode(dst, img, &jpeg.Options{Quality: q})
+	case "png", "gif":
+		return "image/png", png.Encode(dst, img)
+	}
+	return "", fmt.Errorf("%w: %s", errImageBadFormat, format)
+}
+
+func transformImage(img image.Image, t ImageTransform) image.Image {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	w, h := t.Width, t.Height
+
+	switch {
+	case sw == 0 || sh == 0 || w == 0 && h == 0:
+		return img
+	case w == 0:
+		w = max(1, sw*h/sh)
+	case h == 0:
+		h = max(1, sh*w/sw)
+	}
+
+	src := b
+	switch t.Fit {
+	case FitFill:
+	case FitCrop:
+		// Crop the source to the target aspect ratio, centred.
+		if sw*h > sh*w {
+			cw := sh * w / h
+			src.Min.X += (sw - cw) / 2
+			src.Max.X = src.Min.X + cw
+		} else {
+			ch := sw * h / w
+			src.Min.Y += (sh - ch) / 2
+			src.Max.Y = src.Min.Y + ch
+		}
+	default:
+		// Shrink the box to the source aspect ratio.
+		if sw*h > sh*w {
+			h = max(1, sh*w/sw)
+		} else {
+			w = max(1, sw*h/sh)
+		}
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := src.Min.Y + y*src.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := src.Min.X + x*src.Dx()/w
+			dst.Set(x, y, img.At(sx, sy))
+		}
+	}
+	return dst
+}
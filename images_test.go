@@ -0,0 +1,107 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestAlsoNow_Images(t *testing.T) {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)))
+	source := fstest.MapFS{"logo.png": {Data: buf.Bytes()}}
+	key := []byte("secret")
+
+	an := New()
+	an.Images("/img", source, ImageOptions{SignKey: key})
+
+	tests := []struct {
+		url    string
+		code   int
+		dx, dy int
+	}{
+		{url: SignImageURL(key, "/img/logo.png", ImageTransform{Width: 10}), code: http.StatusOK, dx: 10, dy: 5},
+		{url: SignImageURL(key, "/img/logo.png", ImageTransform{Width: 10, Height: 10, Fit: FitCrop}), code: http.StatusOK, dx: 10, dy: 10},
+		{url: "/img/logo.png?w=10", code: http.StatusForbidden},
+		{url: SignImageURL(key, "/img/missing.png", ImageTransform{}), code: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+
+			if w.Code != tt.code {
+				t.Fatalf("status = %d, want %d", w.Code, tt.code)
+			}
+			if tt.code != http.StatusOK {
+				return
+			}
+			img, err := png.Decode(w.Body)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if b := img.Bounds(); b.Dx() != tt.dx || b.Dy() != tt.dy {
+				t.Errorf("size = %dx%d, want %dx%d", b.Dx(), b.Dy(), tt.dx, tt.dy)
+			}
+		})
+	}
+}
+
+// pngWithSize returns a 1×1 PNG whose header declares w×h pixels.
+func pngWithSize(w, h uint32) []byte {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	data := buf.Bytes()
+	binary.BigEndian.PutUint32(data[16:], w)
+	binary.BigEndian.PutUint32(data[20:], h)
+	binary.BigEndian.PutUint32(data[29:], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestAlsoNow_ImagesSourceLimits(t *testing.T) {
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 40, 20)))
+	source := fstest.MapFS{
+		"logo.png":  {Data: buf.Bytes()},
+		"bomb.png":  {Data: pngWithSize(100_000, 100_000)},
+		"empty.png": {Data: pngWithSize(0, 0)},
+		"text.png":  {Data: []byte("not an image")},
+	}
+
+	an := New()
+	an.Images("/img", source, ImageOptions{})
+	an.Images("/small", source, ImageOptions{MaxPixels: 500})
+
+	tests := []struct {
+		url  string
+		code int
+	}{
+		{"/img/logo.png?w=10", http.StatusOK},
+		{"/img/bomb.png?w=10", http.StatusUnprocessableEntity},
+		{"/img/empty.png?w=10", http.StatusUnprocessableEntity},
+		{"/img/text.png?w=10", http.StatusUnsupportedMediaType},
+		{"/small/logo.png?w=10", http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+		if w.Code != tt.code {
+			t.Errorf("%s: status %d, want %d", tt.url, w.Code, tt.code)
+		}
+	}
+
+	if img := transformImage(image.NewRGBA(image.Rect(0, 0, 0, 0)), ImageTransform{Width: 10}); img.Bounds().Dx() != 0 {
+		t.Errorf("empty image transformed to %v", img.Bounds())
+	}
+}
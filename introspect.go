@@ -0,0 +1,170 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/alsonow/alsonow/cache"
+)
+
+// tokenInfoKey is the Context key of the introspected *TokenInfo.
+const tokenInfoKey = "alsonow.token_info"
+
+// TokenInfo is an RFC 7662 introspection response.
+type TokenInfo struct {
+	Active    bool   `json:"active"`
+	Scope     string `json:"scope,omitempty"`
+	ClientID  string `json:"client_id,omitempty"`
+	Username  string `json:"username,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+	Exp       int64  `json:"exp,omitempty"`
+	Iat       int64  `json:"iat,omitempty"`
+	Nbf       int64  `json:"nbf,omitempty"`
+	Sub       string `json:"sub,omitempty"`
+	Iss       string `json:"iss,omitempty"`
+	Jti       string `json:"jti,omitempty"`
+}
+
+// Subject implements Principal.
+func (t *TokenInfo) Subject() string {
+	return t.Sub
+}
+
+// Scopes returns the space separated scopes of the token.
+func (t *TokenInfo) Scopes() []string {
+	return strings.Fields(t.Scope)
+}
+
+// HasScope implements Principal.
+func (t *TokenInfo) HasScope(scope string) bool {
+	for _, s := range t.Scopes() {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IntrospectionConfig configures Introspect.
+type IntrospectionConfig struct {
+	// Endpoint is the introspection URL of the authorization server.
+	Endpoint string
+	// ClientID and ClientSecret authenticate the resource server with
+	// HTTP Basic authentication.
+	ClientID     string
+	ClientSecret string
+	// Client sends the requests, one with a 10s timeout when nil.
+	Client *http.Client
+	// CacheTTL is how long an active token is trusted before being checked
+	// again, never past its expiry. One minute when zero, negative disables
+	// caching.
+	CacheTTL time.Duration
+}
+
+// Introspect authenticates requests with a bearer token validated by the
+// authorization server's introspection endpoint (RFC 7662). Active tokens
+// are cached so the server is not queried on every request. The token is
+// set as the request's Principal, so routes may demand scopes with
+// Route.RequireScopes.
+func Introspect(cfg IntrospectionConfig) HandlerFunc {
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.CacheTTL == 0 {
+		cfg.CacheTTL = time.Minute
+	}
+	// Results are keyed by the token hash, so the tokens themselves are not
+	// kept in memory.
+	tokens := cache.NewMemory(cache.Options[[32]byte, *TokenInfo]{MaxEntries: 100000})
+
+	return func(c *Context) {
+		token, ok := strings.CutPrefix(c.Header("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			bearerChallenge(c, http.StatusUnauthorized, `realm="api"`)
+			return
+		}
+
+		key := sha256.Sum256([]byte(token))
+		info, ok := tokens.Get(key)
+		if !ok {
+			var err error
+			if info, err = introspect(c.Context(), &cfg, token); err != nil {
+				c.Logf("[INTROSPECT] %v", err)
+				c.Error(http.StatusServiceUnavailable, "")
+				c.Abort()
+				return
+			}
+			if info.Active && cfg.CacheTTL > 0 {
+				ttl := cfg.CacheTTL
+				if info.Exp != 0 {
+					ttl = min(ttl, time.Until(time.Unix(info.Exp, 0)))
+				}
+				if ttl > 0 {
+					tokens.SetTTL(key, info, ttl)
+				}
+			}
+		}
+
+		if !info.Active || (info.Exp != 0 && time.Now().Unix() >= info.Exp) {
+			bearerChallenge(c, http.StatusUnauthorized, `error="invalid_token"`)
+			return
+		}
+
+		c.Set(tokenInfoKey, info)
+		c.SetPrincipal(info)
+		c.Next()
+	}
+}
+
+// IntrospectedToken returns the token validated by Introspect, or nil.
+func IntrospectedToken(c *Context) *TokenInfo {
+	v, _ := c.Get(tokenInfoKey)
+	info, _ := v.(*TokenInfo)
+	return info
+}
+
+// bearerChallenge aborts with status and an RFC 6750 WWW-Authenticate header.
+func bearerChallenge(c *Context, status int, params string) {
+	c.SetHeader("WWW-Authenticate", "Bearer "+params)
+	c.Error(status, "")
+	c.Abort()
+}
+
+func introspect(ctx context.Context, cfg *IntrospectionConfig, token string) (*TokenInfo, error) {
+	form := url.Values{"token": {token}, "token_type_hint": {"access_token"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cfg.Endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.ClientID != "" {
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("introspection endpoint returned %s", resp.Status)
+	}
+
+	info := &TokenInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
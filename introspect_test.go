@@ -0,0 +1,64 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestIntrospect(t *testing.T) {
+	var calls atomic.Int32
+	as := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if user, pass, _ := r.BasicAuth(); user != "api" || pass != "s3cret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		info := TokenInfo{}
+		if r.PostFormValue("token") == "good" {
+			info = TokenInfo{Active: true, Scope: "orders:read profile", Sub: "u1", Exp: time.Now().Add(time.Hour).Unix()}
+		}
+		_ = json.NewEncoder(w).Encode(info)
+	}))
+	defer as.Close()
+
+	an := New()
+	an.Use(Introspect(IntrospectionConfig{Endpoint: as.URL, ClientID: "api", ClientSecret: "s3cret"}))
+	an.GET("/orders", func(c *Context) { _, _ = c.Writer.Write([]byte(IntrospectedToken(c).Sub)) }).RequireScopes("orders:read")
+	an.DELETE("/orders", func(c *Context) {}).RequireScopes("orders:write")
+
+	do := func(method, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/orders", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodGet, "good"); w.Code != http.StatusOK || w.Body.String() != "u1" {
+		t.Errorf("good token: %d %q", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodGet, "good"); w.Code != http.StatusOK || calls.Load() != 1 {
+		t.Errorf("cached token: status %d, %d introspection calls", w.Code, calls.Load())
+	}
+	w := do(http.MethodDelete, "good")
+	if w.Code != http.StatusForbidden || !strings.Contains(w.Body.String(), "orders:write") {
+		t.Errorf("missing scope: %d %q", w.Code, w.Body.String())
+	}
+	if w := do(http.MethodGet, "revoked"); w.Code != http.StatusUnauthorized {
+		t.Errorf("inactive token: %d", w.Code)
+	}
+	if w := do(http.MethodGet, ""); w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("no token: %d", w.Code)
+	}
+}
@@ -0,0 +1,325 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MIMEJSONAPI is the media type defined by the JSON:API specification.
+// https://jsonapi.org/format/
+const MIMEJSONAPI = "application/vnd.api+json"
+
+// JSONAPIDocument is a top-level JSON:API document.
+type JSONAPIDocument struct {
+	Data     any                `json:"data"`
+	Included []*JSONAPIResource `json:"included,omitempty"`
+	Links    *JSONAPILinks      `json:"links,omitempty"`
+	Meta     map[string]any     `json:"meta,omitempty"`
+}
+
+// JSONAPIResource is a single resource object.
+type JSONAPIResource struct {
+	Type          string                          `json:"type"`
+	ID            string                          `json:"id"`
+	Attributes    map[string]any                  `json:"attributes,omitempty"`
+	Relationships map[string]*JSONAPIRelationship `json:"relationships,omitempty"`
+	Links         *JSONAPILinks                   `json:"links,omitempty"`
+}
+
+// JSONAPIIdentifier identifies a resource inside a relationship.
+type JSONAPIIdentifier struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// JSONAPIRelationship holds the linkage of a to-one or to-many relationship.
+type JSONAPIRelationship struct {
+	Data  any           `json:"data"`
+	Links *JSONAPILinks `json:"links,omitempty"`
+}
+
+// JSONAPILinks holds the self and pagination links of a document or resource.
+type JSONAPILinks struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// MarshalJSONAPI builds a JSON:API document from a tagged struct, a pointer
+// to one, or a slice of them. Fields are described with the jsonapi tag:
+//
+//	type User struct {
+//		ID    int      `jsonapi:"primary,users"`
+//		Name  string   `jsonapi:"attr,name"`
+//		Email string   `jsonapi:"attr,email,omitempty"`
+//		Posts []*Post  `jsonapi:"relation,posts"`
+//	}
+//
+// Related resources are added to the included section once per type and id.
+func MarshalJSONAPI(v any) (*JSONAPIDocument, error) {
+	s := &jsonapiSerializer{seen: make(map[JSONAPIIdentifier]bool)}
+	doc := &JSONAPIDocument{}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return doc, nil
+		}
+		rv = rv.Elem()
+	}
+
+	// Primary data is marked as seen first: it must not be repeated in the
+	// included section, nor walked again when related resources point back
+	// at it.
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			id, err := jsonapiIdentify(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			s.seen[id] = true
+		}
+		data := make([]*JSONAPIResource, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			res, err := s.resource(rv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, res)
+		}
+		doc.Data = data
+	case reflect.Struct:
+		id, err := jsonapiIdentify(rv)
+		if err != nil {
+			return nil, err
+		}
+		s.seen[id] = true
+		res, err := s.resource(rv)
+		if err != nil {
+			return nil, err
+		}
+		doc.Data = res
+	default:
+		return nil, fmt.Errorf("jsonapi: unsupported type %s", rv.Type())
+	}
+
+	doc.Included = s.included
+	return doc, nil
+}
+
+// JSONAPIPageLinks builds page[number]/page[size] pagination links based on
+// the request URL u. Pages are numbered from 1.
+func JSONAPIPageLinks(u *url.URL, page, size, total int) *JSONAPILinks {
+	if size <= 0 {
+		size = 1
+	}
+	if page < 1 {
+		page = 1
+	}
+	last := int(math.Ceil(float64(total) / float64(size)))
+	if last < 1 {
+		last = 1
+	}
+
+	link := func(n int) string {
+		cp := *u
+		q := cp.Query()
+		q.Set("page[number]", strconv.Itoa(n))
+		q.Set("page[size]", strconv.Itoa(size))
+		cp.RawQuery = q.Encode()
+		return cp.String()
+	}
+
+	links := &JSONAPILinks{
+		Self:  link(page),
+		First: link(1),
+		Last:  link(last),
+	}
+	if page > 1 {
+		links.Prev = link(page - 1)
+	}
+	if page < last {
+		links.Next = link(page + 1)
+	}
+	return links
+}
+
+// JSONAPI serializes v as a JSON:API document and writes it with the given
+// status code. v may be a *JSONAPIDocument or anything MarshalJSONAPI accepts.
+func (c *Context) JSONAPI(code int, v any) {
+	doc, ok := v.(*JSONAPIDocument)
+	if !ok {
+		var err error
+		if doc, err = MarshalJSONAPI(v); err != nil {
+			c.InternalServerError(err)
+			return
+		}
+	}
+
+	body, err := json.Marshal(doc)
+	if err != nil {
+		c.InternalServerError(err)
+		return
+	}
+
+	c.SetHeader("Content-Type", MIMEJSONAPI)
+	c.Status(code)
+	_, _ = c.Writer.Write(body)
+}
+
+type jsonapiSerializer struct {
+	included []*JSONAPIResource
+	seen     map[JSONAPIIdentifier]bool
+}
+
+var errJSONAPINoPrimary = errors.New("jsonapi: missing primary field")
+
+func (s *jsonapiSerializer) resource(rv reflect.Value) (*JSONAPIResource, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return nil, fmt.Errorf("jsonapi: nil resource")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("jsonapi: unsupported type %s", rv.Type())
+	}
+
+	res := &JSONAPIResource{}
+	hasPrimary := false
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("jsonapi")
+		if !ok || !field.IsExported() {
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("jsonapi: bad tag %q on field %s", tag, field.Name)
+		}
+		fv := rv.Field(i)
+
+		switch parts[0] {
+		case "primary":
+			res.Type = parts[1]
+			res.ID = fmt.Sprint(fv.Interface())
+			hasPrimary = true
+		case "attr":
+			if len(parts) > 2 && parts[2] == "omitempty" && fv.IsZero() {
+				continue
+			}
+			if res.Attributes == nil {
+				res.Attributes = make(map[string]any)
+			}
+			res.Attributes[parts[1]] = fv.Interface()
+		case "relation":
+			rel, err := s.relationship(fv)
+			if err != nil {
+				return nil, err
+			}
+			if res.Relationships == nil {
+				res.Relationships = make(map[string]*JSONAPIRelationship)
+			}
+			res.Relationships[parts[1]] = rel
+		default:
+			return nil, fmt.Errorf("jsonapi: unknown tag kind %q on field %s", parts[0], field.Name)
+		}
+	}
+
+	if !hasPrimary {
+		return nil, errJSONAPINoPrimary
+	}
+	return res, nil
+}
+
+func (s *jsonapiSerializer) relationship(fv reflect.Value) (*JSONAPIRelationship, error) {
+	switch fv.Kind() {
+	case reflect.Slice, reflect.Array:
+		ids := make([]JSONAPIIdentifier, 0, fv.Len())
+		for i := 0; i < fv.Len(); i++ {
+			id, err := s.include(fv.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			ids = append(ids, id)
+		}
+		return &JSONAPIRelationship{Data: ids}, nil
+	case reflect.Pointer, reflect.Interface:
+		if fv.IsNil() {
+			return &JSONAPIRelationship{Data: nil}, nil
+		}
+		fallthrough
+	case reflect.Struct:
+		id, err := s.include(fv)
+		if err != nil {
+			return nil, err
+		}
+		return &JSONAPIRelationship{Data: id}, nil
+	}
+	return nil, fmt.Errorf("jsonapi: unsupported relation type %s", fv.Type())
+}
+
+// include walks the relationships of rv once per type and id. The
+// identifier is marked as seen before the walk so that resources pointing
+// back at each other do not recurse forever.
+func (s *jsonapiSerializer) include(rv reflect.Value) (JSONAPIIdentifier, error) {
+	id, err := jsonapiIdentify(rv)
+	if err != nil {
+		return JSONAPIIdentifier{}, err
+	}
+	if s.seen[id] {
+		return id, nil
+	}
+	s.seen[id] = true
+
+	res, err := s.resource(rv)
+	if err != nil {
+		return JSONAPIIdentifier{}, err
+	}
+	s.included = append(s.included, res)
+	return id, nil
+}
+
+// jsonapiIdentify returns the type and id of the resource rv without
+// walking its relationships.
+func jsonapiIdentify(rv reflect.Value) (JSONAPIIdentifier, error) {
+	for rv.Kind() == reflect.Pointer || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return JSONAPIIdentifier{}, fmt.Errorf("jsonapi: nil resource")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return JSONAPIIdentifier{}, fmt.Errorf("jsonapi: unsupported type %s", rv.Type())
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		tag, ok := field.Tag.Lookup("jsonapi")
+		if !ok || !field.IsExported() {
+			continue
+		}
+		if kind, typ, ok := strings.Cut(tag, ","); ok && kind == "primary" {
+			typ, _, _ = strings.Cut(typ, ",")
+			return JSONAPIIdentifier{Type: typ, ID: fmt.Sprint(rv.Field(i).Interface())}, nil
+		}
+	}
+	return JSONAPIIdentifier{}, errJSONAPINoPrimary
+}
@@ -0,0 +1,123 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+)
+
+type jsonapiAuthor struct {
+	ID   int    `jsonapi:"primary,people"`
+	Name string `jsonapi:"attr,name"`
+}
+
+type jsonapiArticle struct {
+	ID     string         `jsonapi:"primary,articles"`
+	Title  string         `jsonapi:"attr,title"`
+	Body   string         `jsonapi:"attr,body,omitempty"`
+	Author *jsonapiAuthor `jsonapi:"relation,author"`
+}
+
+func TestJSONAPI_Marshal(t *testing.T) {
+	author := &jsonapiAuthor{ID: 9, Name: "Dan"}
+	articles := []*jsonapiArticle{
+		{ID: "1", Title: "first", Author: author},
+		{ID: "2", Title: "second", Author: author},
+	}
+
+	doc, err := MarshalJSONAPI(articles)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data := doc.Data.([]*JSONAPIResource)
+	if len(data) != 2 || data[0].Type != "articles" || data[0].ID != "1" {
+		t.Fatalf("unexpected data: %+v", data)
+	}
+	if _, ok := data[0].Attributes["body"]; ok {
+		t.Errorf("expected empty body to be omitted")
+	}
+	if id := data[1].Relationships["author"].Data.(JSONAPIIdentifier); id.ID != "9" || id.Type != "people" {
+		t.Errorf("unexpected relationship: %+v", id)
+	}
+	if len(doc.Included) != 1 {
+		t.Errorf("expected author to be included once, got %d", len(doc.Included))
+	}
+}
+
+func TestJSONAPI_Context(t *testing.T) {
+	w := httptest.NewRecorder()
+	c := &Context{Writer: w, Req: httptest.NewRequest(http.MethodGet, "/articles", nil)}
+	c.JSONAPI(http.StatusOK, &jsonapiArticle{ID: "1", Title: "first"})
+
+	if ct := w.Header().Get("Content-Type"); ct != MIMEJSONAPI {
+		t.Errorf("Content-Type = %q, want %q", ct, MIMEJSONAPI)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("invalid json: %v", err)
+	}
+	if got["data"].(map[string]any)["id"] != "1" {
+		t.Errorf("unexpected body: %s", w.Body.String())
+	}
+}
+
+func TestJSONAPI_PageLinks(t *testing.T) {
+	u, _ := url.Parse("http://example.com/articles?sort=title")
+	links := JSONAPIPageLinks(u, 2, 10, 25)
+
+	if links.Prev == "" || links.Next == "" {
+		t.Fatalf("expected prev and next links: %+v", links)
+	}
+	last, _ := url.Parse(links.Last)
+	if last.Query().Get("page[number]") != "3" || last.Query().Get("sort") != "title" {
+		t.Errorf("unexpected last link: %s", links.Last)
+	}
+}
+
+type jsonapiUser struct {
+	ID    int            `jsonapi:"primary,users"`
+	Posts []*jsonapiPost `jsonapi:"relation,posts"`
+}
+
+type jsonapiPost struct {
+	ID     int          `jsonapi:"primary,posts"`
+	Author *jsonapiUser `jsonapi:"relation,author"`
+}
+
+func TestJSONAPI_CyclicRelations(t *testing.T) {
+	user := &jsonapiUser{ID: 1}
+	user.Posts = []*jsonapiPost{{ID: 10, Author: user}, {ID: 11, Author: user}}
+	other := &jsonapiUser{ID: 2}
+	user.Posts[1].Author = other
+	other.Posts = []*jsonapiPost{user.Posts[1]}
+
+	doc, err := MarshalJSONAPI(user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []JSONAPIIdentifier
+	for _, res := range doc.Included {
+		got = append(got, JSONAPIIdentifier{Type: res.Type, ID: res.ID})
+	}
+	want := []JSONAPIIdentifier{{"posts", "10"}, {"posts", "11"}, {"users", "2"}}
+	if len(got) != len(want) {
+		t.Fatalf("included = %v, want %v", got, want)
+	}
+	for _, id := range want {
+		if !slices.Contains(got, id) {
+			t.Errorf("included = %v, missing %v", got, id)
+		}
+	}
+	if id := doc.Included[0].Relationships["author"].Data.(JSONAPIIdentifier); id != (JSONAPIIdentifier{"users", "1"}) {
+		t.Errorf("author of post 10 = %v", id)
+	}
+}
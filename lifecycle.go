@@ -0,0 +1,75 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+)
+
+// OnStart adds a hook run once the server listens, before it serves
+// requests, by Run and the other Run methods, such as connecting to the
+// database or warming caches. The hooks run in the order they were added;
+// the first error stops the server from starting and is returned by Run,
+// after the OnShutdown hooks ran to release what the hooks before it
+// started.
+func (an *AlsoNow) OnStart(hook func(ctx context.Context) error) *AlsoNow {
+	an.startHooks = append(an.startHooks, hook)
+	return an
+}
+
+// OnShutdown adds a hook run during graceful shutdown, once the requests in
+// flight have completed, such as closing database pools or flushing
+// queues. The hooks run in the reverse order they were added, like
+// deferred calls, all of them even when some fail; ctx is done when the
+// shutdown timeout, counted afresh once the requests are drained, elapses.
+// Their errors are returned by Run. They also run when the server fails
+// to start, and must cope with components that were not started.
+func (an *AlsoNow) OnShutdown(hook func(ctx context.Context) error) *AlsoNow {
+	an.shutdownHooks = append(an.shutdownHooks, hook)
+	return an
+}
+
+// runStartHooks runs the OnStart hooks.
+func (an *AlsoNow) runStartHooks(ctx context.Context) error {
+	for _, hook := range an.startHooks {
+		if err := hook(ctx); err != nil {
+			return fmt.Errorf("start hook: %w", err)
+		}
+	}
+	return nil
+}
+
+// start runs the OnStart hooks once the server listens on ln. When one
+// fails, ln is closed and the OnShutdown hooks run.
+func (an *AlsoNow) start(ctx context.Context, ln net.Listener) error {
+	if err := an.runStartHooks(ctx); err != nil {
+		_ = ln.Close()
+		return errors.Join(err, an.stopHooks())
+	}
+	return nil
+}
+
+// stopHooks runs the OnShutdown hooks with a shutdown timeout of their own,
+// so that a drain which used up its budget leaves them time to release
+// resources.
+func (an *AlsoNow) stopHooks() error {
+	ctx, cancel := context.WithTimeout(context.Background(), an.shutdownTimeout)
+	defer cancel()
+	return an.runShutdownHooks(ctx)
+}
+
+// runShutdownHooks runs the OnShutdown hooks and joins their errors.
+func (an *AlsoNow) runShutdownHooks(ctx context.Context) error {
+	var errs []error
+	for i := len(an.shutdownHooks) - 1; i >= 0; i-- {
+		if err := an.shutdownHooks[i](ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown hook: %w", err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,155 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestAlsoNow_LifecycleHooks(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []string
+	errFlush := errors.New("flush failed")
+	an := New()
+	an.OnStart(func(ctx context.Context) error { events = append(events, "start db"); return nil })
+	an.OnStart(func(ctx context.Context) error { events = append(events, "start queue"); return nil })
+	an.OnShutdown(func(ctx context.Context) error { events = append(events, "close db"); return nil })
+	an.OnShutdown(func(ctx context.Context) error { events = append(events, "flush queue"); return errFlush })
+	an.GET("/", func(c *Context) { events = append(events, "request") })
+
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+	resp, err := http.Get("http://" + ln.Addr().String() + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	an.Stop()
+	if err := <-done; !errors.Is(err, errFlush) {
+		t.Errorf("RunListener = %v, want the shutdown hook error", err)
+	}
+	want := []string{"start db", "start queue", "request", "flush queue", "close db"}
+	if !reflect.DeepEqual(events, want) {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestAlsoNow_OnStartError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errDB := errors.New("database unreachable")
+	stopped := false
+	an := New()
+	an.OnStart(func(ctx context.Context) error { return nil })
+	an.OnStart(func(ctx context.Context) error { return errDB })
+	an.OnShutdown(func(ctx context.Context) error { stopped = true; return nil })
+	if err := an.RunListener(ln); !errors.Is(err, errDB) {
+		t.Errorf("RunListener = %v, want the start hook error", err)
+	}
+	if _, err := ln.Accept(); err == nil {
+		t.Error("listener should be closed")
+	}
+	if !stopped {
+		t.Error("shutdown hooks not run after a failed start")
+	}
+}
+
+func TestAlsoNow_ListenErrorSkipsStartHooks(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	started := false
+	an := New()
+	an.OnStart(func(ctx context.Context) error { started = true; return nil })
+	if err := an.RunContext(context.Background(), busy.Addr().String()); err == nil {
+		t.Fatal("RunContext listened on a busy address")
+	}
+	if started {
+		t.Error("start hooks ran although the server could not listen")
+	}
+}
+
+func TestAlsoNow_ForcedShutdownHookBudget(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entered, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	var hookErr error
+	an := New().WithShutdownTimeout(50 * time.Millisecond).WithSignals()
+	an.GET("/slow", func(c *Context) {
+		close(entered)
+		<-release
+	})
+	an.OnShutdown(func(ctx context.Context) error {
+		hookErr = ctx.Err()
+		return nil
+	})
+	done := make(chan error, 1)
+	go func() { done <- an.RunListener(ln) }()
+
+	go func() { _, _ = http.Get("http://" + ln.Addr().String() + "/slow") }()
+	<-entered
+	an.Stop()
+	if err := <-done; err == nil {
+		t.Error("RunListener = nil, want the forced shutdown error")
+	}
+	if hookErr != nil {
+		t.Errorf("shutdown hook ctx = %v after a forced shutdown", hookErr)
+	}
+}
+
+func TestAlsoNow_Shutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	closed := false
+	an := New().WithShutdownTimeout(time.Second).WithSignals()
+	an.OnShutdown(func(ctx context.Context) error {
+		if deadline, ok := ctx.Deadline(); !ok || time.Until(deadline) > time.Second {
+			t.Errorf("shutdown deadline = %v, %v", deadline, ok)
+		}
+		closed = true
+		return nil
+	})
+	go func() { _ = an.RunListener(ln) }()
+	for !an.serving.Load() {
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := an.Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown = %v", err)
+	}
+	if !closed {
+		t.Error("Shutdown returned before the shutdown hooks ran")
+	}
+
+	if err := New().Shutdown(ctx); err != nil {
+		t.Errorf("Shutdown of a server never started = %v", err)
+	}
+}
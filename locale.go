@@ -0,0 +1,157 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// localeParam is the parameter holding the locale of the Localized routes.
+const localeParam = "locale"
+
+// Localized returns a group whose routes are served under a static prefix
+// per locale, for internationalized websites:
+//
+//	site := an.Localized([]string{"en", "de"})
+//	site.GET("/about", about) // "/en/about" and "/de/about", c.Locale()
+//
+// GET and HEAD requests for the bare path, "/about", are redirected to the
+// locale preferred by their Accept-Language header, the first of locales
+// when it names none of them. Routes registered at the bare path, such as a
+// landing page at "/", take precedence over the redirect whatever the order
+// of registration.
+//
+// The routes are registered once per locale, so they live next to root
+// level parameter routes such as "/:slug". Their pattern, as listed in the
+// OpenAPI document or expanded by AlsoNow.URL, has a ":locale" parameter.
+func (r *routerImpl) Localized(locales []string, m ...HandlerFunc) *Group {
+	if len(locales) == 0 {
+		panic("alsonow: Localized needs at least one locale")
+	}
+	quoted := make([]string, len(locales))
+	for i, l := range locales {
+		quoted[i] = regexp.QuoteMeta(l)
+	}
+
+	return &Group{
+		prefix:      "/:" + localeParam + "(" + strings.Join(quoted, "|") + ")",
+		middlewares: m,
+		router:      r,
+		locales:     locales,
+	}
+}
+
+// Locale returns the locale of the request, matched by a Localized route,
+// or "".
+func (c *Context) Locale() string {
+	return c.Param(localeParam)
+}
+
+// localized returns the Localized group g belongs to, or nil.
+func (g *Group) localized() *Group {
+	for current := g; current != nil; current = current.parent {
+		if current.locales != nil {
+			return current
+		}
+	}
+	return nil
+}
+
+// addLocalized registers the route at fullPath, below the prefix of lg,
+// under the static prefix of every locale of lg. The returned route is not
+// matched itself: its variants stand for it, see resolveLocalized.
+func (g *Group) addLocalized(lg *Group, method, fullPath string, middlewares, h []HandlerFunc) *Route {
+	route := g.router.newRoute(g.host, method, fullPath, middlewares, h)
+	bare := strings.TrimPrefix(fullPath, lg.prefix)
+	matcher := g.router.matcherOf(g.host)
+	for _, locale := range lg.locales {
+		variant := g.router.newRoute(g.host, method, "/"+locale+bare, nil, nil)
+		variant.localized, variant.locale = route, locale
+		matcher.Add(variant)
+	}
+	g.router.register(route)
+	return route
+}
+
+// resolveLocalized returns the Localized route the variant route stands
+// for, appending the locale to params, or route itself.
+func resolveLocalized(route *Route, params *[]Param) *Route {
+	if route.localized == nil {
+		return route
+	}
+	appendParam(params, localeParam, route.locale)
+	return route.localized
+}
+
+// addLocaleRedirect registers the bare path of the localized route at
+// fullPath, redirecting to the preferred locale. A route already registered
+// at the bare path is kept and no redirect is added.
+func (g *Group) addLocaleRedirect(method, fullPath string) {
+	if method != http.MethodGet && method != http.MethodHead {
+		return
+	}
+	lg := g.localized()
+	bare := strings.TrimPrefix(fullPath, lg.prefix)
+	if bare == "" {
+		bare = "/"
+	}
+	if g.router.lookupRoute(g.host, method, bare) != nil {
+		return
+	}
+
+	locales := lg.locales
+	redirect := func(c *Context) {
+		target := "/" + preferredLocale(c.Header("Accept-Language"), locales) + strings.TrimSuffix(c.Req.URL.EscapedPath(), "/")
+		if c.Req.URL.RawQuery != "" {
+			target += "?" + c.Req.URL.RawQuery
+		}
+		c.SetHeader("Vary", "Accept-Language")
+		http.Redirect(c.Writer, c.Req, target, http.StatusFound)
+	}
+	route := g.router.addRoute(g.host, method, bare, append([]HandlerFunc(nil), g.router.middlewares...), []HandlerFunc{redirect})
+	route.localeRedirect = true
+}
+
+// preferredLocale returns the locale of locales the Accept-Language header
+// prefers, matching "de-CH" with "de" as well, or the first one.
+func preferredLocale(header string, locales []string) string {
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		if name != "" && q > 0 {
+			tags = append(tags, tag{name, q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	for _, t := range tags {
+		base, _, _ := strings.Cut(t.name, "-")
+		for _, l := range locales {
+			if strings.EqualFold(l, t.name) {
+				return l
+			}
+		}
+		for _, l := range locales {
+			if strings.EqualFold(l, base) {
+				return l
+			}
+		}
+	}
+	return locales[0]
+}
@@ -0,0 +1,118 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRouter_Localized(t *testing.T) {
+	an := New()
+	site := an.Localized([]string{"en", "de", "pt-BR"})
+	site.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte("home " + c.Locale())) })
+	site.Group("/docs").GET("/:page", func(c *Context) { _, _ = c.Writer.Write([]byte(c.Locale() + " " + c.Param("page"))) })
+	site.POST("/contact", func(c *Context) {})
+
+	tests := []struct {
+		path, lang string
+		code       int
+		want       string
+	}{
+		{"/en", "", http.StatusOK, "home en"},
+		{"/de/docs/intro", "", http.StatusOK, "de intro"},
+		{"/pt-BR/docs/intro", "", http.StatusOK, "pt-BR intro"},
+		{"/fr/docs/intro", "", http.StatusNotFound, ""},
+		{"/docs/intro?x=1", "de-CH,de;q=0.9,en;q=0.8", http.StatusFound, "/de/docs/intro?x=1"},
+		{"/docs/intro", "fr, en;q=0.5", http.StatusFound, "/en/docs/intro"},
+		{"/docs/intro", "pt-br", http.StatusFound, "/pt-BR/docs/intro"},
+		{"/", "ja", http.StatusFound, "/en"},
+		{"/", "en;q=0.1, de", http.StatusFound, "/de"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept-Language", tt.lang)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+
+		got := w.Body.String()
+		if w.Code == http.StatusFound {
+			got = w.Header().Get("Location")
+		}
+		if w.Code != tt.code || (tt.want != "" && got != tt.want) {
+			t.Errorf("%s %q: %d %q, want %d %q", tt.path, tt.lang, w.Code, got, tt.code, tt.want)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/contact", nil))
+	if w.Code == http.StatusFound {
+		t.Error("POST to the bare path should not be redirected")
+	}
+}
+
+func TestRouter_LocalizedKeepsBarePathRoutes(t *testing.T) {
+	an := New()
+	an.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte("landing")) })
+	site := an.Localized([]string{"en", "de"})
+	site.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte("home " + c.Locale())) })
+	site.GET("/pricing", func(c *Context) { _, _ = c.Writer.Write([]byte("pricing " + c.Locale())) })
+	an.GET("/pricing", func(c *Context) { _, _ = c.Writer.Write([]byte("plans")) })
+
+	for path, want := range map[string]string{"/": "landing", "/de": "home de", "/pricing": "plans", "/en/pricing": "pricing en"} {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Errorf("GET %s: %d %q, want %q", path, w.Code, w.Body.String(), want)
+		}
+	}
+
+	for _, path := range []string{"/", "/pricing"} {
+		n := 0
+		for _, route := range an.router().routes {
+			if route.Method == http.MethodGet && route.Path == path {
+				n++
+			}
+		}
+		if n != 1 {
+			t.Errorf("GET %s registered %d times", path, n)
+		}
+	}
+}
+
+func TestRouter_LocalizedNextToRootParams(t *testing.T) {
+	an := New()
+	an.GET("/:slug", func(c *Context) { _, _ = c.Writer.Write([]byte("page " + c.Param("slug"))) })
+	site := an.Localized([]string{"en", "de"})
+	site.GET("/about", func(c *Context) {
+		_, _ = c.Writer.Write([]byte("about " + c.Locale() + " " + c.Route().Path))
+	}).Name("about")
+
+	for path, want := range map[string]string{
+		"/en/about": "about en /:locale(en|de)/about",
+		"/de/about": "about de /:locale(en|de)/about",
+		"/pricing":  "page pricing",
+	} {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != want {
+			t.Errorf("GET %s: %d %q, want %q", path, w.Code, w.Body.String(), want)
+		}
+	}
+
+	if u, err := an.URL("about", "locale", "de"); err != nil || u != "/de/about" {
+		t.Errorf("URL = %q, %v", u, err)
+	}
+	var paths []string
+	for _, info := range an.Routes() {
+		if info.Name == "about" {
+			paths = append(paths, info.Path)
+		}
+	}
+	if len(paths) != 2 || paths[0] != "/de/about" || paths[1] != "/en/about" {
+		t.Errorf("listed paths = %v", paths)
+	}
+}
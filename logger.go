@@ -5,28 +5,82 @@
 package alsonow
 
 import (
-	"log"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
+// logFieldsKey is the Context key of the fields added with LogField.
+const logFieldsKey = "alsonow.log_fields"
+
+type logField struct {
+	key   string
+	value any
+}
+
 func Logger() HandlerFunc {
 	return func(c *Context) {
 		start := time.Now()
 
 		c.Next()
 
+		if skip, _ := c.Get(skipAccessLogKey); skip == true {
+			return
+		}
+
 		duration := time.Since(start)
 
 		clientIP := ClientIP(c.Req)
 		userAgent := c.Req.UserAgent()
 
-		log.Printf("[ACCESS] %s | %v | %s | %s %s | %s",
+		c.Logf("[ACCESS] %s | %v | %s | %s %s | %s%s",
 			time.Now().Format("2006/01/02 15:04:05"),
 			duration,
 			clientIP,
 			c.Method(),
 			c.Path(),
 			userAgent,
+			formatLogFields(c),
 		)
 	}
 }
+
+// LogField attaches a key=value field to the access log entry of the
+// request, written by Logger once the request completes:
+//
+//	c.LogField("user_id", user.ID)
+//
+// Setting a key again replaces its value.
+func (c *Context) LogField(key string, value any) {
+	v, _ := c.Get(logFieldsKey)
+	fields, _ := v.([]logField)
+	for i := range fields {
+		if fields[i].key == key {
+			fields[i].value = value
+			return
+		}
+	}
+	c.Set(logFieldsKey, append(fields, logField{key: key, value: value}))
+}
+
+// formatLogFields returns " | k=v k=v" for the fields of the request, with
+// values quoted when they contain spaces or quotes, or "".
+func formatLogFields(c *Context) string {
+	v, _ := c.Get(logFieldsKey)
+	fields, _ := v.([]logField)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(" |")
+	for _, f := range fields {
+		s := fmt.Sprint(f.value)
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = strconv.Quote(s)
+		}
+		b.WriteString(" " + f.key + "=" + s)
+	}
+	return b.String()
+}
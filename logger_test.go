@@ -0,0 +1,33 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogger_Fields(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	an := New().WithLogger()
+	an.GET("/", func(c *Context) {
+		c.LogField("user_id", 42)
+		c.LogField("plan", "pro")
+		c.LogField("note", `two words`)
+		c.LogField("plan", "free")
+	})
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if line := buf.String(); !strings.Contains(line, `| user_id=42 plan=free note="two words"`) {
+		t.Errorf("access log = %q", line)
+	}
+}
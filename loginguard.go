@@ -0,0 +1,264 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LoginAttempts are the recent failed authentications of a key.
+type LoginAttempts struct {
+	Failures    int
+	LastFailure time.Time
+}
+
+// LoginAttemptStore keeps the failed authentications per key. Fail and
+// Forgive must be atomic so concurrent attempts are all counted.
+type LoginAttemptStore interface {
+	// Get returns the attempts of key, the zero value when there are none.
+	Get(ctx context.Context, key string) (LoginAttempts, error)
+	// Fail records a failure. Failures older than resetAfter are forgotten
+	// first.
+	Fail(ctx context.Context, key string, resetAfter time.Duration) (LoginAttempts, error)
+	// Forgive takes back one failure recorded by Fail, if any.
+	Forgive(ctx context.Context, key string) error
+	// Reset forgets the failures of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LoginGuardConfig configures a LoginGuard.
+type LoginGuardConfig struct {
+	// Store keeps the attempts, a MemoryLoginAttemptStore when nil.
+	Store LoginAttemptStore
+	// KeyFunc identifies the attempt, the client IP and the "username" form
+	// field when nil. Applications posting JSON must provide their own.
+	KeyFunc func(*Context) string
+	// BaseDelay is the wait after the first failure, doubled after each
+	// following one up to MaxDelay. Defaults to 1s and 5m.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// MaxFailures locks the key out for LockoutDuration, 10 and 15m by default.
+	MaxFailures     int
+	LockoutDuration time.Duration
+	// ResetAfter forgets failures older than this, 24h when zero.
+	ResetAfter time.Duration
+}
+
+// LoginGuardStats are the counters of a LoginGuard.
+type LoginGuardStats struct {
+	Successes uint64
+	Failures  uint64
+	// Blocked counts the attempts refused during a backoff or a lockout.
+	Blocked uint64
+	// Lockouts counts the keys that reached MaxFailures.
+	Lockouts uint64
+}
+
+// LoginGuard slows down password guessing. Each failed authentication of a
+// key makes it wait exponentially longer before its next attempt, and too
+// many failures lock it out for a while.
+type LoginGuard struct {
+	cfg LoginGuardConfig
+
+	successes atomic.Uint64
+	failures  atomic.Uint64
+	blocked   atomic.Uint64
+	lockouts  atomic.Uint64
+}
+
+// NewLoginGuard returns a LoginGuard using cfg.
+func NewLoginGuard(cfg LoginGuardConfig) *LoginGuard {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryLoginAttemptStore()
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *Context) string {
+			return ClientIP(c.Req) + "|" + c.Req.PostFormValue("username")
+		}
+	}
+	if cfg.BaseDelay == 0 {
+		cfg.BaseDelay = time.Second
+	}
+	if cfg.MaxDelay == 0 {
+		cfg.MaxDelay = 5 * time.Minute
+	}
+	if cfg.MaxFailures == 0 {
+		cfg.MaxFailures = 10
+	}
+	if cfg.LockoutDuration == 0 {
+		cfg.LockoutDuration = 15 * time.Minute
+	}
+	if cfg.ResetAfter == 0 {
+		cfg.ResetAfter = 24 * time.Hour
+	}
+	return &LoginGuard{cfg: cfg}
+}
+
+// Middleware guards a login handler. Attempts made too early are refused
+// with 429 Too Many Requests and a Retry-After header. Each attempt is
+// recorded as a failure before the handler runs, so parallel attempts
+// cannot all slip through the same backoff: only the first one since the
+// last failure runs, the others are refused. Once the handler has run, a
+// 401 or 403 response keeps the failure, a 2xx one resets the key and any
+// other status takes the failure back.
+func (g *LoginGuard) Middleware() HandlerFunc {
+	return func(c *Context) {
+		key := g.cfg.KeyFunc(c)
+		ctx := context.WithoutCancel(c.Context())
+
+		attempts, getErr := g.cfg.Store.Get(ctx, key)
+		if getErr != nil {
+			c.Logf("[LOGIN] load attempts: %v", getErr)
+		}
+		if g.refuse(c, g.blockedUntil(attempts)) {
+			return
+		}
+
+		reserved, err := g.cfg.Store.Fail(ctx, key, g.cfg.ResetAfter)
+		if err != nil {
+			c.Logf("[LOGIN] record attempt: %v", err)
+		} else if getErr == nil && reserved.Failures > 1 && reserved.Failures != attempts.Failures+1 {
+			// Another attempt started since attempts were loaded: this one
+			// waits for its outcome.
+			g.forgive(c, ctx, key)
+			g.refuse(c, g.blockedUntil(LoginAttempts{Failures: reserved.Failures - 1, LastFailure: reserved.LastFailure}))
+			return
+		}
+
+		c.Next()
+
+		switch status := c.StatusCode(); {
+		case status == http.StatusUnauthorized || status == http.StatusForbidden:
+			g.failures.Add(1)
+			if err == nil && reserved.Failures == g.cfg.MaxFailures {
+				g.lockouts.Add(1)
+			}
+		case status >= 200 && status < 300:
+			g.successes.Add(1)
+			if err := g.cfg.Store.Reset(ctx, key); err != nil {
+				c.Logf("[LOGIN] reset attempts: %v", err)
+			}
+		case err == nil:
+			g.forgive(c, ctx, key)
+		}
+	}
+}
+
+// refuse answers 429 when until is in the future.
+func (g *LoginGuard) refuse(c *Context, until time.Time) bool {
+	wait := time.Until(until)
+	if wait <= 0 {
+		return false
+	}
+	g.blocked.Add(1)
+	c.SetHeader("Retry-After", strconv.Itoa(int(wait.Seconds())+1))
+	c.Error(http.StatusTooManyRequests, "")
+	c.Abort()
+	return true
+}
+
+// forgive takes back the failure recorded for an attempt that did not fail.
+func (g *LoginGuard) forgive(c *Context, ctx context.Context, key string) {
+	if err := g.cfg.Store.Forgive(ctx, key); err != nil {
+		c.Logf("[LOGIN] take back attempt: %v", err)
+	}
+}
+
+// Stats returns the counters of the guard.
+func (g *LoginGuard) Stats() LoginGuardStats {
+	return LoginGuardStats{
+		Successes: g.successes.Load(),
+		Failures:  g.failures.Load(),
+		Blocked:   g.blocked.Load(),
+		Lockouts:  g.lockouts.Load(),
+	}
+}
+
+// blockedUntil returns when the next attempt is allowed.
+func (g *LoginGuard) blockedUntil(a LoginAttempts) time.Time {
+	if a.Failures == 0 || time.Since(a.LastFailure) > g.cfg.ResetAfter {
+		return time.Time{}
+	}
+	if a.Failures >= g.cfg.MaxFailures {
+		return a.LastFailure.Add(g.cfg.LockoutDuration)
+	}
+
+	delay := g.cfg.BaseDelay
+	for i := 1; i < a.Failures && delay < g.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return a.LastFailure.Add(min(delay, g.cfg.MaxDelay))
+}
+
+// MemoryLoginAttemptStore keeps login attempts in memory.
+type MemoryLoginAttemptStore struct {
+	mu        sync.Mutex
+	attempts  map[string]LoginAttempts
+	lastSweep time.Time
+}
+
+// NewMemoryLoginAttemptStore returns an empty store.
+func NewMemoryLoginAttemptStore() *MemoryLoginAttemptStore {
+	return &MemoryLoginAttemptStore{attempts: make(map[string]LoginAttempts), lastSweep: time.Now()}
+}
+
+func (s *MemoryLoginAttemptStore) Get(_ context.Context, key string) (LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.attempts[key], nil
+}
+
+func (s *MemoryLoginAttemptStore) Fail(_ context.Context, key string, resetAfter time.Duration) (LoginAttempts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// Forgotten keys are swept at most once per resetAfter.
+	if now.Sub(s.lastSweep) > resetAfter {
+		for k, a := range s.attempts {
+			if now.Sub(a.LastFailure) > resetAfter {
+				delete(s.attempts, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	a := s.attempts[key]
+	if now.Sub(a.LastFailure) > resetAfter {
+		a.Failures = 0
+	}
+	a.Failures++
+	a.LastFailure = now
+	s.attempts[key] = a
+	return a, nil
+}
+
+func (s *MemoryLoginAttemptStore) Forgive(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.attempts[key]
+	switch {
+	case !ok:
+	case a.Failures <= 1:
+		delete(s.attempts, key)
+	default:
+		a.Failures--
+		s.attempts[key] = a
+	}
+	return nil
+}
+
+func (s *MemoryLoginAttemptStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.attempts, key)
+	return nil
+}
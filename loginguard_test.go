@@ -0,0 +1,147 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoginGuard(t *testing.T) {
+	store := NewMemoryLoginAttemptStore()
+	guard := NewLoginGuard(LoginGuardConfig{Store: store, MaxFailures: 3})
+
+	an := New()
+	an.POST("/login", guard.Middleware(), func(c *Context) {
+		if c.Req.PostFormValue("password") != "secret" {
+			c.Status(http.StatusUnauthorized)
+		}
+	})
+
+	login := func(user, password string) *httptest.ResponseRecorder {
+		form := url.Values{"username": {user}, "password": {password}}
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := login("bob", "guess"); w.Code != http.StatusUnauthorized {
+		t.Fatalf("first failure: status = %d", w.Code)
+	}
+	w := login("bob", "secret")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Fatalf("attempt during backoff: status = %d, Retry-After = %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := login("alice", "secret"); w.Code != http.StatusOK {
+		t.Errorf("other user: status = %d", w.Code)
+	}
+
+	// Reach the lockout by pretending the backoff elapsed.
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, _ = store.Fail(ctx, "192.0.2.1|bob", time.Hour)
+	}
+	a, _ := store.Get(ctx, "192.0.2.1|bob")
+	if until := guard.blockedUntil(a); time.Until(until) < 14*time.Minute {
+		t.Errorf("locked until %v, want about 15 minutes", until)
+	}
+
+	stats := guard.Stats()
+	if stats.Failures != 1 || stats.Blocked != 1 || stats.Successes != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
+
+func TestLoginGuard_Backoff(t *testing.T) {
+	guard := NewLoginGuard(LoginGuardConfig{BaseDelay: time.Second, MaxDelay: 10 * time.Second})
+	now := time.Now()
+
+	for _, tt := range []struct {
+		failures int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+	} {
+		got := guard.blockedUntil(LoginAttempts{Failures: tt.failures, LastFailure: now}).Sub(now)
+		if got != tt.want {
+			t.Errorf("%d failures: delay %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestLoginGuard_ParallelAttempts(t *testing.T) {
+	guard := NewLoginGuard(LoginGuardConfig{MaxFailures: 3})
+	release := make(chan struct{})
+	var ran atomic.Int32
+
+	an := New()
+	an.POST("/login", guard.Middleware(), func(c *Context) {
+		ran.Add(1)
+		<-release
+		c.Status(http.StatusUnauthorized)
+	})
+
+	const attempts = 50
+	var wg sync.WaitGroup
+	codes := make(chan int, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			form := url.Values{"username": {"bob"}, "password": {"guess"}}
+			req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			w := httptest.NewRecorder()
+			an.ServeHTTP(w, req)
+			codes <- w.Code
+		}()
+	}
+	// Every attempt but the one in the handler is refused.
+	for i := 0; i < attempts-1; i++ {
+		if code := <-codes; code != http.StatusTooManyRequests {
+			t.Errorf("parallel attempt: status = %d", code)
+		}
+	}
+	close(release)
+	wg.Wait()
+	if code := <-codes; code != http.StatusUnauthorized {
+		t.Errorf("guessing attempt: status = %d", code)
+	}
+	if n := ran.Load(); n != 1 {
+		t.Errorf("handler ran %d times", n)
+	}
+
+	a, _ := guard.cfg.Store.Get(context.Background(), "192.0.2.1|bob")
+	if a.Failures != 1 {
+		t.Errorf("failures = %d, want 1", a.Failures)
+	}
+}
+
+func TestLoginGuard_OtherStatusForgiven(t *testing.T) {
+	guard := NewLoginGuard(LoginGuardConfig{})
+	an := New()
+	an.POST("/login", guard.Middleware(), func(c *Context) { c.Error(http.StatusBadRequest, "") })
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", w.Code)
+	}
+	if a, _ := guard.cfg.Store.Get(context.Background(), "192.0.2.1|"); a.Failures != 0 {
+		t.Errorf("failures = %d, want 0", a.Failures)
+	}
+}
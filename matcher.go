@@ -0,0 +1,52 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+// Param is a route parameter.
+type Param struct {
+	Key, Value string
+}
+
+// MatcherBackend stores the routes of a host and matches request paths
+// against them. The radix tree of NewRadixMatcher is the default; other
+// matchers, based on regular expressions or priorities, can be swapped in
+// with WithMatcher for benchmarking or special needs, ServeHTTP and the
+// Context plumbing staying the same.
+//
+// Matchers are written to during registration only, and must support
+// concurrent Match calls afterwards.
+type MatcherBackend interface {
+	// Add registers route under its Method and Path, a normalized pattern
+	// made of static, ":name", ":name(constraint)" and "*name" segments, a
+	// bare "*" catch-all being named "*". It panics when the pattern
+	// conflicts with the registered ones.
+	Add(route *Route)
+	// Match returns the route of method matching the normalized path,
+	// appending its parameters to params, or nil. params is nil when only
+	// the route matters, as when computing the Allow header of a 405.
+	Match(method, path string, params *[]Param) *Route
+	// Routes returns the registered routes sorted by method, then in an
+	// order of the matcher's choosing.
+	Routes() []*Route
+}
+
+// FoldMatcher is implemented by the matchers supporting WithFixedPath.
+type FoldMatcher interface {
+	// MatchFold is Match comparing static segments case-insensitively. It
+	// also returns the escaped path with the case of the route.
+	MatchFold(method, path string, params *[]Param) (*Route, string)
+}
+
+// WithMatcher sets the constructor of the matchers holding the routes, one
+// per host. It must be called before any route is registered.
+func (an *AlsoNow) WithMatcher(newMatcher func() MatcherBackend) *AlsoNow {
+	r := an.router()
+	if len(r.routes) > 0 {
+		panic("alsonow: WithMatcher must be called before registering routes")
+	}
+	r.newMatcher = newMatcher
+	r.matcher = newMatcher()
+	return an
+}
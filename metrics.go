@@ -0,0 +1,185 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MiddlewareObserver receives the execution of every handler of a chain.
+// d is the time spent in the handler itself, excluding the handlers it ran
+// through Next. aborted reports whether the handler aborted the chain and
+// panicked whether it panicked.
+type MiddlewareObserver interface {
+	ObserveMiddleware(name string, d time.Duration, aborted, panicked bool)
+}
+
+// WithMiddlewareMetrics reports the execution of every handler and
+// middleware to o, which makes latency hotspots in the chain visible.
+func (an *AlsoNow) WithMiddlewareMetrics(o MiddlewareObserver) *AlsoNow {
+	an.router().metrics = o
+	return an
+}
+
+// runObserved runs the handler at index i and reports it to c.metrics.
+func (c *Context) runObserved(i int) {
+	h := c.handlers[i]
+	parentChild := c.childTime
+	c.childTime = 0
+	start := time.Now()
+	completed := false
+
+	// Deferred so panics are counted; the panic keeps propagating to Recover.
+	defer func() {
+		total := time.Since(start)
+		self := total - c.childTime
+		c.childTime = parentChild + total
+		c.metrics.ObserveMiddleware(cachedHandlerName(h), self, c.aborted && c.abortedAt == i, !completed)
+	}()
+
+	h(c)
+	completed = true
+}
+
+// MiddlewareStat holds the cumulative figures of one middleware.
+type MiddlewareStat struct {
+	Calls  uint64
+	Total  time.Duration
+	Aborts uint64
+	Panics uint64
+}
+
+// MiddlewareMetrics is an in-memory MiddlewareObserver. It is also an
+// http.Handler exposing the figures in the Prometheus text format.
+type MiddlewareMetrics struct {
+	mu    sync.Mutex
+	stats map[string]*MiddlewareStat
+}
+
+// NewMiddlewareMetrics returns an empty MiddlewareMetrics.
+func NewMiddlewareMetrics() *MiddlewareMetrics {
+	return &MiddlewareMetrics{stats: make(map[string]*MiddlewareStat)}
+}
+
+// ObserveMiddleware implements MiddlewareObserver.
+func (m *MiddlewareMetrics) ObserveMiddleware(name string, d time.Duration, aborted, panicked bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	s, ok := m.stats[name]
+	if !ok {
+		s = &MiddlewareStat{}
+		m.stats[name] = s
+	}
+	s.Calls++
+	s.Total += d
+	if aborted {
+		s.Aborts++
+	}
+	if panicked {
+		s.Panics++
+	}
+}
+
+// Snapshot returns a copy of the figures keyed by middleware name.
+func (m *MiddlewareMetrics) Snapshot() map[string]MiddlewareStat {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	out := make(map[string]MiddlewareStat, len(m.stats))
+	for name, s := range m.stats {
+		out[name] = *s
+	}
+	return out
+}
+
+// ServeHTTP writes the figures in the Prometheus text exposition format.
+func (m *MiddlewareMetrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	snap := m.Snapshot()
+	names := make([]string, 0, len(snap))
+	for name := range snap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	series := []struct {
+		metric, help, kind string
+		value              func(MiddlewareStat) string
+	}{
+		{"alsonow_middleware_calls_total", "Number of middleware executions.", "counter",
+			func(s MiddlewareStat) string { return fmt.Sprint(s.Calls) }},
+		{"alsonow_middleware_duration_seconds_total", "Time spent in the middleware itself.", "counter",
+			func(s MiddlewareStat) string { return fmt.Sprint(s.Total.Seconds()) }},
+		{"alsonow_middleware_aborts_total", "Number of chains aborted by the middleware.", "counter",
+			func(s MiddlewareStat) string { return fmt.Sprint(s.Aborts) }},
+		{"alsonow_middleware_panics_total", "Number of panics raised in the middleware.", "counter",
+			func(s MiddlewareStat) string { return fmt.Sprint(s.Panics) }},
+	}
+	for _, s := range series {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s %s\n", s.metric, s.help, s.metric, s.kind)
+		for _, name := range names {
+			fmt.Fprintf(&b, "%s{middleware=%q} %s\n", s.metric, name, s.value(snap[name]))
+		}
+	}
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
+
+// UnmatchedRouteLabel is the RouteLabel of requests no route matched.
+const UnmatchedRouteLabel = "unmatched"
+
+// maxRouteLabel bounds the length of route labels.
+const maxRouteLabel = 100
+
+// RouteLabel returns a metrics label for the route of the request: its
+// pattern, such as "/users/:id", or the mount prefix followed by "/*".
+// Requests no route matched, including 404 and 405 from scanners, share
+// UnmatchedRouteLabel, and long patterns are truncated, which keeps the
+// number of label values bounded by the number of routes.
+func RouteLabel(c *Context) string {
+	var label string
+	switch {
+	case c.route != nil:
+		label = c.route.Path
+	case c.mount != "":
+		label = strings.TrimSuffix(c.mount, "/") + "/*"
+	default:
+		return UnmatchedRouteLabel
+	}
+
+	if len(label) > maxRouteLabel {
+		label = strings.ToValidUTF8(label[:maxRouteLabel], "") + "..."
+	}
+	return label
+}
+
+var (
+	handlerNames sync.Map // code pointer -> name
+
+	// closureSuffix matches the ".func1.2" suffix of anonymous functions.
+	closureSuffix = regexp.MustCompile(`(\.func\d+)+(\.\d+)*$`)
+)
+
+// cachedHandlerName returns the name of the function that created h,
+// without the suffix of anonymous functions: Logger() is "...alsonow.Logger".
+func cachedHandlerName(h HandlerFunc) string {
+	pc := reflect.ValueOf(h).Pointer()
+	if name, ok := handlerNames.Load(pc); ok {
+		return name.(string)
+	}
+
+	name := closureSuffix.ReplaceAllString(handlerName(h), "")
+	handlerNames.Store(pc, name)
+	return name
+}
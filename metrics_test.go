@@ -0,0 +1,77 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func slowMiddleware() HandlerFunc {
+	return func(c *Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Next()
+	}
+}
+
+func denyMiddleware() HandlerFunc {
+	return func(c *Context) {
+		c.AbortWithStatus(http.StatusForbidden)
+	}
+}
+
+func TestMiddlewareMetrics(t *testing.T) {
+	m := NewMiddlewareMetrics()
+	an := New().WithMiddlewareMetrics(m)
+	an.GET("/ok", slowMiddleware(), func(c *Context) {})
+	an.GET("/denied", denyMiddleware(), func(c *Context) {})
+
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ok", nil))
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/denied", nil))
+
+	snap := m.Snapshot()
+	slow := snap["github.com/alsonow/alsonow.slowMiddleware"]
+	if slow.Calls != 1 || slow.Total < 20*time.Millisecond {
+		t.Errorf("slowMiddleware stat = %+v", slow)
+	}
+	// Recover wraps the slow middleware but its own time excludes it.
+	if rec := snap["github.com/alsonow/alsonow.Recover"]; rec.Calls != 2 || rec.Total >= 20*time.Millisecond {
+		t.Errorf("Recover stat = %+v", rec)
+	}
+	if deny := snap["github.com/alsonow/alsonow.denyMiddleware"]; deny.Aborts != 1 {
+		t.Errorf("denyMiddleware stat = %+v", deny)
+	}
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if !strings.Contains(w.Body.String(), `alsonow_middleware_aborts_total{middleware="github.com/alsonow/alsonow.denyMiddleware"} 1`) {
+		t.Errorf("unexpected exposition:\n%s", w.Body.String())
+	}
+}
+
+func TestRouteLabel(t *testing.T) {
+	var labels []string
+	an := New()
+	an.Use(func(c *Context) {
+		c.Next()
+		labels = append(labels, RouteLabel(c))
+	})
+	an.GET("/users/:id", func(c *Context) {})
+	an.GET("/"+strings.Repeat("a", 200), func(c *Context) {})
+	an.Mount("/debug", http.NotFoundHandler())
+
+	for _, path := range []string{"/users/1", "/wp-admin.php", "/debug/pprof/heap", "/" + strings.Repeat("a", 200)} {
+		an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users/1", nil))
+
+	want := []string{"/users/:id", UnmatchedRouteLabel, "/debug/*", "/" + strings.Repeat("a", 99) + "...", UnmatchedRouteLabel}
+	if strings.Join(labels, ",") != strings.Join(want, ",") {
+		t.Errorf("labels = %v", labels)
+	}
+}
@@ -0,0 +1,48 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+)
+
+// WithClientAuth makes RunTLS, RunTLSWithCertificate and RunAutoTLS ask the
+// clients for a certificate, verified against the CAs of pool, for mutual
+// TLS between internal services:
+//
+//	an.WithClientAuth(tls.RequireAndVerifyClientCert, pool)
+//
+// With tls.VerifyClientCertIfGiven, clients without a certificate are still
+// served, and middleware can authorize them with Context.ClientCert.
+func (an *AlsoNow) WithClientAuth(auth tls.ClientAuthType, pool *x509.CertPool) *AlsoNow {
+	an.clientAuth = auth
+	an.clientCAs = pool
+	return an
+}
+
+// applyClientAuth sets the client authentication of WithClientAuth on config.
+func (an *AlsoNow) applyClientAuth(config *tls.Config) {
+	if an.clientAuth == tls.NoClientCert {
+		return
+	}
+	config.ClientAuth = an.clientAuth
+	config.ClientCAs = an.clientCAs
+}
+
+// ClientCert returns the certificate the client authenticated with, once
+// verified against the CAs of WithClientAuth, or nil. Certificates sent
+// without verification, with tls.RequestClientCert, are not returned, so
+// their subject can be trusted:
+//
+//	if cert := c.ClientCert(); cert == nil || cert.Subject.CommonName != "billing" {
+//		c.AbortWithStatus(http.StatusForbidden)
+//	}
+func (c *Context) ClientCert() *x509.Certificate {
+	if c.Req.TLS == nil || len(c.Req.TLS.VerifiedChains) == 0 || len(c.Req.TLS.VerifiedChains[0]) == 0 {
+		return nil
+	}
+	return c.Req.TLS.VerifiedChains[0][0]
+}
@@ -0,0 +1,10 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build !race
+
+package alsonow
+
+const raceEnabled = false
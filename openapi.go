@@ -0,0 +1,129 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"strings"
+)
+
+// OpenAPIDocument is a skeleton OpenAPI 3.1 document generated from the
+// registered routes. It lists the operations, their path parameters and
+// their security requirements; fill in schemas and descriptions before
+// publishing it.
+type OpenAPIDocument struct {
+	OpenAPI    string                                  `json:"openapi"`
+	Info       OpenAPIInfo                             `json:"info"`
+	Paths      map[string]map[string]*OpenAPIOperation `json:"paths"`
+	Components *OpenAPIComponents                      `json:"components,omitempty"`
+}
+
+// OpenAPIInfo is the info object of an OpenAPIDocument.
+type OpenAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// OpenAPIOperation describes a route.
+type OpenAPIOperation struct {
+	Parameters []OpenAPIParameter         `json:"parameters,omitempty"`
+	Security   []map[string][]string      `json:"security,omitempty"`
+	Responses  map[string]OpenAPIResponse `json:"responses"`
+	Deprecated bool                       `json:"deprecated,omitempty"`
+}
+
+// OpenAPIParameter is a path parameter of an operation.
+type OpenAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required"`
+	Schema   map[string]any `json:"schema"`
+}
+
+// OpenAPIResponse is a response of an operation.
+type OpenAPIResponse struct {
+	Description string `json:"description"`
+}
+
+// OpenAPIComponents declares the security schemes referenced by operations.
+type OpenAPIComponents struct {
+	SecuritySchemes map[string]map[string]any `json:"securitySchemes,omitempty"`
+}
+
+// openAPISecurityScheme is the scheme name used for Route.RequireScopes.
+const openAPISecurityScheme = "oauth2"
+
+// OpenAPI generates the document of the routes registered so far. Routes
+// with RequireScopes get a security requirement listing their scopes, and
+// those marked with Route.Deprecated are deprecated.
+func (an *AlsoNow) OpenAPI(title, version string) *OpenAPIDocument {
+	doc := &OpenAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    OpenAPIInfo{Title: title, Version: version},
+		Paths:   make(map[string]map[string]*OpenAPIOperation),
+	}
+
+	for _, route := range an.router().routes {
+		path, params := openAPIPath(route.Path)
+		op := &OpenAPIOperation{
+			Parameters: params,
+			Responses:  map[string]OpenAPIResponse{"default": {Description: "Response"}},
+			Deprecated: route.IsDeprecated(),
+		}
+
+		if scopes := route.Scopes(); len(scopes) > 0 {
+			op.Security = []map[string][]string{{openAPISecurityScheme: scopes}}
+			op.Responses["401"] = OpenAPIResponse{Description: http.StatusText(http.StatusUnauthorized)}
+			op.Responses["403"] = OpenAPIResponse{Description: http.StatusText(http.StatusForbidden)}
+			if doc.Components == nil {
+				doc.Components = &OpenAPIComponents{SecuritySchemes: map[string]map[string]any{
+					openAPISecurityScheme: {"type": "oauth2", "flows": map[string]any{}},
+				}}
+			}
+		}
+
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*OpenAPIOperation)
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+	return doc
+}
+
+// openAPIPath converts "/users/:id(int)/*rest" to "/users/{id}/{rest}".
+func openAPIPath(pattern string) (string, []OpenAPIParameter) {
+	var params []OpenAPIParameter
+	segments := strings.Split(pattern, "/")
+	for i, seg := range segments {
+		if seg == "" || (seg[0] != ':' && seg[0] != '*') {
+			continue
+		}
+		name, constraint := seg[1:], ""
+		if seg[0] == ':' {
+			name, constraint = splitParam(name)
+		}
+		schema := map[string]any{"type": "string"}
+		switch constraint {
+		case "int", "uint":
+			schema = map[string]any{"type": "integer"}
+		case "uuid":
+			schema["format"] = "uuid"
+		case "", "alpha", "alnum":
+		default:
+			if _, ok := lookupConstraint(constraint); !ok {
+				schema["pattern"] = "^(?:" + constraint + ")$"
+			}
+		}
+
+		segments[i] = "{" + name + "}"
+		params = append(params, OpenAPIParameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   schema,
+		})
+	}
+	return strings.Join(segments, "/"), params
+}
@@ -0,0 +1,133 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// pageMetaKey is the Context key of the PageMeta of the request.
+const pageMetaKey = "alsonow.page_meta"
+
+// PageMeta is the metadata of a page rendered into its <head>: title,
+// description, canonical URL, OpenGraph and Twitter card tags, so server
+// rendered pages manage their SEO tags consistently.
+type PageMeta struct {
+	Title       string
+	Description string
+	// Canonical is the absolute URL of the page, see Context.CanonicalURL.
+	Canonical string
+	// Image is the absolute URL of the preview image of the page.
+	Image string
+	// Type is the OpenGraph type, "website" when empty.
+	Type     string
+	SiteName string
+	// Locale is the OpenGraph locale, such as "en_US".
+	Locale string
+	// TwitterSite is the @username of the site.
+	TwitterSite string
+	// NoIndex asks search engines not to index the page.
+	NoIndex bool
+}
+
+// SiteMeta sets the defaults of the PageMeta of every request, such as the
+// SiteName and TwitterSite, which handlers complete.
+func SiteMeta(defaults PageMeta) HandlerFunc {
+	return func(c *Context) {
+		m := defaults
+		c.Set(pageMetaKey, &m)
+		c.Next()
+	}
+}
+
+// PageMeta returns the metadata of the page, to be filled by the handler and
+// passed to the templates:
+//
+//	meta := c.PageMeta()
+//	meta.Title = post.Title
+//	meta.Canonical, _ = c.CanonicalURL("post.show", "slug", post.Slug)
+//	c.Fragment(http.StatusOK, "post.tmpl", map[string]any{"Meta": meta, "Post": post})
+//
+// and in the <head> of the template: {{.Meta.Tags}}.
+func (c *Context) PageMeta() *PageMeta {
+	if v, ok := c.Get(pageMetaKey); ok {
+		return v.(*PageMeta)
+	}
+	m := &PageMeta{}
+	c.Set(pageMetaKey, m)
+	return m
+}
+
+// errNoRoute is returned by CanonicalURL for requests no route matched.
+var errNoRoute = errors.New("alsonow: request matched no route")
+
+// CanonicalURL returns the absolute URL of the route named name, its
+// parameters substituted with pairs as in AlsoNow.URL, on the scheme and
+// host of the request.
+func (c *Context) CanonicalURL(name string, pairs ...string) (string, error) {
+	if c.route == nil || c.route.router == nil {
+		return "", errNoRoute
+	}
+	route, ok := c.route.router.names[name]
+	if !ok {
+		return "", fmt.Errorf("alsonow: no route named %q", name)
+	}
+	path, err := expandPattern(route.Path, pairs...)
+	if err != nil {
+		return "", err
+	}
+	return forwardedScheme(c) + "://" + c.Host() + path, nil
+}
+
+// Tags returns the <title>, <meta> and <link> tags of m, escaped, for the
+// <head> of a template.
+func (m *PageMeta) Tags() template.HTML {
+	var b strings.Builder
+	meta := func(attr, key, value string) {
+		if value != "" {
+			b.WriteString(`<meta ` + attr + `="` + key + `" content="` + template.HTMLEscapeString(value) + "\">\n")
+		}
+	}
+	name := func(key, value string) { meta("name", key, value) }
+	property := func(key, value string) { meta("property", key, value) }
+
+	if m.Title != "" {
+		b.WriteString("<title>" + template.HTMLEscapeString(m.Title) + "</title>\n")
+	}
+	name("description", m.Description)
+	if m.Canonical != "" {
+		b.WriteString(`<link rel="canonical" href="` + template.HTMLEscapeString(m.Canonical) + "\">\n")
+	}
+	if m.NoIndex {
+		name("robots", "noindex")
+	}
+
+	ogType := m.Type
+	if ogType == "" {
+		ogType = "website"
+	}
+	property("og:type", ogType)
+	property("og:title", m.Title)
+	property("og:description", m.Description)
+	property("og:url", m.Canonical)
+	property("og:image", m.Image)
+	property("og:site_name", m.SiteName)
+	property("og:locale", m.Locale)
+
+	card := "summary"
+	if m.Image != "" {
+		card = "summary_large_image"
+	}
+	name("twitter:card", card)
+	name("twitter:site", m.TwitterSite)
+	name("twitter:title", m.Title)
+	name("twitter:description", m.Description)
+	name("twitter:image", m.Image)
+
+	return template.HTML(b.String())
+}
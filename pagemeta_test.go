@@ -0,0 +1,50 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_PageMeta(t *testing.T) {
+	an := New().WithTemplates(template.Must(template.New("page").Parse(`<head>{{.Tags}}</head>`)))
+	an.Use(SiteMeta(PageMeta{SiteName: "Example", TwitterSite: "@example"}))
+	an.GET("/posts/:slug", func(c *Context) {
+		meta := c.PageMeta()
+		meta.Title = `Tips & "tricks"`
+		meta.Description = "A post"
+		meta.Image = "https://cdn.example.com/tips.png"
+		meta.Type = "article"
+		meta.Canonical, _ = c.CanonicalURL("post.show", "slug", c.Param("slug"))
+		_ = c.Fragment(http.StatusOK, "page", meta)
+	}).Name("post.show")
+
+	req := httptest.NewRequest(http.MethodGet, "http://example.com/posts/tips", nil)
+	req.Header.Set("X-Forwarded-Proto", "https")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+
+	want := "<head><title>Tips &amp; &#34;tricks&#34;</title>\n" +
+		`<meta name="description" content="A post">` + "\n" +
+		`<link rel="canonical" href="https://example.com/posts/tips">` + "\n" +
+		`<meta property="og:type" content="article">` + "\n" +
+		`<meta property="og:title" content="Tips &amp; &#34;tricks&#34;">` + "\n" +
+		`<meta property="og:description" content="A post">` + "\n" +
+		`<meta property="og:url" content="https://example.com/posts/tips">` + "\n" +
+		`<meta property="og:image" content="https://cdn.example.com/tips.png">` + "\n" +
+		`<meta property="og:site_name" content="Example">` + "\n" +
+		`<meta name="twitter:card" content="summary_large_image">` + "\n" +
+		`<meta name="twitter:site" content="@example">` + "\n" +
+		`<meta name="twitter:title" content="Tips &amp; &#34;tricks&#34;">` + "\n" +
+		`<meta name="twitter:description" content="A post">` + "\n" +
+		`<meta name="twitter:image" content="https://cdn.example.com/tips.png">` + "\n" +
+		"</head>"
+	if w.Body.String() != want {
+		t.Errorf("head =\n%s\nwant\n%s", w.Body.String(), want)
+	}
+}
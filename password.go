@@ -0,0 +1,145 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrInvalidPasswordHash is returned when a stored hash cannot be parsed.
+var ErrInvalidPasswordHash = errors.New("invalid password hash")
+
+// Password hashing algorithms supported by PasswordHasher.
+const (
+	Argon2id = "argon2id"
+	Bcrypt   = "bcrypt"
+)
+
+// PasswordHasher hashes passwords with argon2id or bcrypt. Argon2id hashes
+// use the PHC string format "$argon2id$v=19$m=...,t=...,p=...$salt$key".
+type PasswordHasher struct {
+	// Algorithm of new hashes, Argon2id or Bcrypt.
+	Algorithm string
+	// Argon2id parameters: Memory in KiB, Time iterations and Threads.
+	Memory  uint32
+	Time    uint32
+	Threads uint8
+	// BcryptCost is the bcrypt work factor.
+	BcryptCost int
+}
+
+// DefaultPasswordHasher follows the second recommended argon2id
+// configuration of RFC 9106: 64 MiB of memory, 3 passes and 4 lanes.
+var DefaultPasswordHasher = &PasswordHasher{
+	Algorithm:  Argon2id,
+	Memory:     64 * 1024,
+	Time:       3,
+	Threads:    4,
+	BcryptCost: 12,
+}
+
+// HashPassword hashes password with DefaultPasswordHasher.
+func HashPassword(password string) (string, error) {
+	return DefaultPasswordHasher.Hash(password)
+}
+
+// VerifyPassword reports whether password matches hash.
+func VerifyPassword(hash, password string) (bool, error) {
+	return DefaultPasswordHasher.Verify(hash, password)
+}
+
+// Hash returns the encoded hash of password with a random salt.
+func (h *PasswordHasher) Hash(password string) (string, error) {
+	if h.Algorithm == Bcrypt {
+		b, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		return string(b), err
+	}
+
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, 32)
+
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	), nil
+}
+
+// Verify reports whether password matches hash in constant time. Hashes of
+// either algorithm are accepted whatever the configured one.
+func (h *PasswordHasher) Verify(hash, password string) (bool, error) {
+	if isBcryptHash(hash) {
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+		return err == nil, err
+	}
+
+	p, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return false, err
+	}
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+// NeedsRehash reports whether hash was made with another algorithm or
+// other parameters than h. Call it after a successful Verify and store a
+// new hash of the password when it returns true.
+func (h *PasswordHasher) NeedsRehash(hash string) bool {
+	if isBcryptHash(hash) {
+		if h.Algorithm != Bcrypt {
+			return true
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		return err != nil || cost != h.BcryptCost
+	}
+
+	if h.Algorithm == Bcrypt {
+		return true
+	}
+	p, _, _, err := parseArgon2id(hash)
+	return err != nil || p.Memory != h.Memory || p.Time != h.Time || p.Threads != h.Threads
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+func parseArgon2id(hash string) (p PasswordHasher, salt, key []byte, err error) {
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 || parts[1] != Argon2id {
+		return p, nil, nil, ErrInvalidPasswordHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, ErrInvalidPasswordHash
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Threads); err != nil {
+		return p, nil, nil, ErrInvalidPasswordHash
+	}
+
+	if salt, err = base64.RawStdEncoding.DecodeString(parts[4]); err != nil {
+		return p, nil, nil, ErrInvalidPasswordHash
+	}
+	if key, err = base64.RawStdEncoding.DecodeString(parts[5]); err != nil || len(key) == 0 {
+		return p, nil, nil, ErrInvalidPasswordHash
+	}
+	p.Algorithm = Argon2id
+	return p, salt, key, nil
+}
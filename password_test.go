@@ -0,0 +1,47 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import "testing"
+
+func TestPasswordHasher(t *testing.T) {
+	// Cheap parameters to keep the test fast.
+	argon := &PasswordHasher{Algorithm: Argon2id, Memory: 1024, Time: 1, Threads: 1}
+	bc := &PasswordHasher{Algorithm: Bcrypt, BcryptCost: 4}
+
+	for _, h := range []*PasswordHasher{argon, bc} {
+		hash, err := h.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("%s: %v", h.Algorithm, err)
+		}
+		if ok, err := h.Verify(hash, "correct horse"); !ok || err != nil {
+			t.Errorf("%s: Verify(right) = %v, %v", h.Algorithm, ok, err)
+		}
+		if ok, err := h.Verify(hash, "battery staple"); ok || err != nil {
+			t.Errorf("%s: Verify(wrong) = %v, %v", h.Algorithm, ok, err)
+		}
+		if h.NeedsRehash(hash) {
+			t.Errorf("%s: fresh hash needs rehash", h.Algorithm)
+		}
+	}
+
+	old, _ := bc.Hash("pw")
+	if !argon.NeedsRehash(old) {
+		t.Error("bcrypt hash should be upgraded to argon2id")
+	}
+	if ok, _ := argon.Verify(old, "pw"); !ok {
+		t.Error("argon2id hasher cannot verify bcrypt hash")
+	}
+
+	stronger := &PasswordHasher{Algorithm: Argon2id, Memory: 2048, Time: 1, Threads: 1}
+	weak, _ := argon.Hash("pw")
+	if !stronger.NeedsRehash(weak) {
+		t.Error("hash with weaker parameters should need rehash")
+	}
+
+	if _, err := argon.Verify("$argon2id$v=19$garbage", "pw"); err != ErrInvalidPasswordHash {
+		t.Errorf("Verify(garbage) error = %v", err)
+	}
+}
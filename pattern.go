@@ -0,0 +1,64 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TranslatePattern rewrites a route pattern written in the brace syntax of
+// chi and gorilla/mux into the native one:
+//
+//	/users/{user_id}           -> /users/:user_id
+//	/posts/{id:[0-9]+}         -> /posts/:id([0-9]+)
+//	/files/{path:.*}           -> /files/*path
+//
+// The Gin and Echo syntax, ":id" and "*path", is the native one and is kept
+// as is, as are chi and Echo bare "*" catch-alls, read with c.Param("*").
+// Routes accept both syntaxes, TranslatePattern being applied on
+// registration, so code migrating from chi or gorilla keeps its patterns.
+// Braces must span whole segments, "/{name}.json" is rejected.
+func TranslatePattern(pattern string) (string, error) {
+	if !strings.Contains(pattern, "{") {
+		return pattern, nil
+	}
+
+	segments := strings.Split(pattern, "/")
+	for i, segment := range segments {
+		// Native parameters are kept, braces being regexp quantifiers there,
+		// as in ":year([0-9]{4})".
+		if strings.HasPrefix(segment, ":") || !strings.ContainsAny(segment, "{}") {
+			continue
+		}
+		if !strings.HasPrefix(segment, "{") || !strings.HasSuffix(segment, "}") {
+			return "", fmt.Errorf("parameter '%s' must span the whole segment", segment)
+		}
+
+		name, expr, _ := strings.Cut(segment[1:len(segment)-1], ":")
+		name = strings.TrimSpace(name)
+		if name == "" || strings.ContainsAny(name, "{}()") {
+			return "", fmt.Errorf("invalid parameter name in '%s'", segment)
+		}
+		switch {
+		case expr == "":
+			segments[i] = ":" + name
+		case expr == ".*" && i == len(segments)-1:
+			segments[i] = "*" + name
+		default:
+			segments[i] = ":" + name + "(" + expr + ")"
+		}
+	}
+	return strings.Join(segments, "/"), nil
+}
+
+// catchAllName returns the parameter name of a "*name" segment, "*" for a
+// bare "*".
+func catchAllName(segment string) string {
+	if segment == "*" {
+		return "*"
+	}
+	return segment[1:]
+}
@@ -0,0 +1,72 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+)
+
+const MIMEPDF = "application/pdf"
+
+// DocumentRenderer renders a document as PDF. Backends (headless browsers,
+// template engines, ...) implement it so handlers stay independent of them.
+type DocumentRenderer interface {
+	RenderPDF(ctx context.Context, w io.Writer) error
+}
+
+// PDF streams the document rendered by doc as an attachment named filename.
+// If rendering fails before any byte is written a 500 is sent instead.
+func (c *Context) PDF(code int, filename string, doc DocumentRenderer) {
+	c.renderPDF(code, "attachment", filename, doc)
+}
+
+// PDFInline is like PDF but asks the browser to display the document.
+func (c *Context) PDFInline(code int, filename string, doc DocumentRenderer) {
+	c.renderPDF(code, "inline", filename, doc)
+}
+
+func (c *Context) renderPDF(code int, disposition, filename string, doc DocumentRenderer) {
+	w := &deferredStatusWriter{c: c, code: code}
+	h := c.Writer.Header()
+	h.Set("Content-Type", MIMEPDF)
+	if filename != "" {
+		disposition = mime.FormatMediaType(disposition, map[string]string{"filename": filename})
+	}
+	h.Set("Content-Disposition", disposition)
+
+	if err := doc.RenderPDF(c.Context(), w); err != nil {
+		if !w.wrote {
+			h.Del("Content-Type")
+			h.Del("Content-Disposition")
+			c.InternalServerError(fmt.Errorf("render PDF: %w", err))
+			return
+		}
+		c.Logf("[PDF] %s %s: %v", c.Method(), c.Path(), err)
+		return
+	}
+
+	if !w.wrote {
+		c.Status(code)
+	}
+}
+
+// deferredStatusWriter writes the status code on the first Write so that
+// failures happening before any output can still change the status.
+type deferredStatusWriter struct {
+	c     *Context
+	code  int
+	wrote bool
+}
+
+func (w *deferredStatusWriter) Write(p []byte) (int, error) {
+	if !w.wrote {
+		w.wrote = true
+		w.c.Status(w.code)
+	}
+	return w.c.Writer.Write(p)
+}
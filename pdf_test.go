@@ -0,0 +1,127 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type pdfFunc func(ctx context.Context, w io.Writer) error
+
+func (f pdfFunc) RenderPDF(ctx context.Context, w io.Writer) error { return f(ctx, w) }
+
+// samplePDF is a minimal one-page document whose content stream contains
+// escaped parentheses and backslashes.
+const samplePDF = "%PDF-1.4\n" +
+	"1 0 obj<</Type/Catalog/Pages 2 0 R>>endobj\n" +
+	"2 0 obj<</Type/Pages/Kids[3 0 R]/Count 1>>endobj\n" +
+	"3 0 obj<</Type/Page/Parent 2 0 R/MediaBox[0 0 200 200]/Contents 4 0 R>>endobj\n" +
+	"4 0 obj<</Length 34>>stream\nBT /F1 12 Tf (a \\(b\\) c:\\\\d) Tj ET\nendstream endobj\n" +
+	"xref\n0 5\n" +
+	"trailer<</Size 5/Root 1 0 R>>\nstartxref\n0\n%%EOF\n"
+
+type failingWriter struct {
+	http.ResponseWriter
+	err error
+}
+
+func (w *failingWriter) Write([]byte) (int, error) { return 0, w.err }
+
+func TestContext_PDF(t *testing.T) {
+	an := New()
+	an.GET("/report", func(c *Context) {
+		c.PDF(http.StatusOK, `Q1 "final" \ report.pdf`, pdfFunc(func(_ context.Context, w io.Writer) error {
+			for _, chunk := range []string{samplePDF[:9], samplePDF[9:]} {
+				if _, err := io.WriteString(w, chunk); err != nil {
+					return err
+				}
+			}
+			return nil
+		}))
+	})
+	an.GET("/inline", func(c *Context) {
+		c.PDFInline(http.StatusOK, "", pdfFunc(func(_ context.Context, w io.Writer) error {
+			_, err := io.WriteString(w, samplePDF)
+			return err
+		}))
+	})
+
+	rec := httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/report", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != MIMEPDF {
+		t.Fatalf("got %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	body := rec.Body.Bytes()
+	if !bytes.HasPrefix(body, []byte("%PDF-")) || !bytes.HasSuffix(body, []byte("%%EOF\n")) {
+		t.Fatalf("body is not a complete PDF: %q", body)
+	}
+	if !bytes.Contains(body, []byte(`(a \(b\) c:\\d)`)) || !bytes.Contains(body, []byte("trailer<<")) {
+		t.Fatalf("body was altered: %q", body)
+	}
+	disposition, params, err := mime.ParseMediaType(rec.Header().Get("Content-Disposition"))
+	if err != nil || disposition != "attachment" || params["filename"] != `Q1 "final" \ report.pdf` {
+		t.Fatalf("Content-Disposition = %q (%v)", rec.Header().Get("Content-Disposition"), err)
+	}
+
+	rec = httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/inline", nil))
+	if got := rec.Header().Get("Content-Disposition"); got != "inline" || rec.Body.String() != samplePDF {
+		t.Fatalf("inline: Content-Disposition = %q, body %q", got, rec.Body.String())
+	}
+}
+
+func TestContext_PDFErrors(t *testing.T) {
+	renderErr := errors.New("renderer crashed")
+	clientErr := errors.New("connection reset")
+	var got error
+
+	an := New()
+	an.GET("/early", func(c *Context) {
+		c.PDF(http.StatusCreated, "a.pdf", pdfFunc(func(context.Context, io.Writer) error {
+			return renderErr
+		}))
+	})
+	an.GET("/late", func(c *Context) {
+		c.PDF(http.StatusCreated, "a.pdf", pdfFunc(func(_ context.Context, w io.Writer) error {
+			_, _ = io.WriteString(w, samplePDF[:9])
+			return renderErr
+		}))
+	})
+	an.GET("/client", func(c *Context) {
+		c.Writer = &failingWriter{ResponseWriter: c.Writer, err: clientErr}
+		c.PDF(http.StatusOK, "a.pdf", pdfFunc(func(_ context.Context, w io.Writer) error {
+			_, got = io.WriteString(w, samplePDF)
+			return got
+		}))
+	})
+
+	rec := httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/early", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("early failure: got %d", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct == MIMEPDF || rec.Header().Get("Content-Disposition") != "" {
+		t.Fatalf("early failure kept PDF headers: %v", rec.Header())
+	}
+
+	rec = httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/late", nil))
+	if rec.Code != http.StatusCreated || rec.Body.String() != samplePDF[:9] {
+		t.Fatalf("late failure: got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/client", nil))
+	if !errors.Is(got, clientErr) {
+		t.Fatalf("renderer saw %v, want %v", got, clientErr)
+	}
+}
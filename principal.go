@@ -0,0 +1,85 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"strings"
+)
+
+// principalKey is the Context key of the authenticated Principal.
+const principalKey = "alsonow.principal"
+
+// scopesMeta is the route metadata set by Route.RequireScopes.
+const scopesMeta = "alsonow.scopes"
+
+// Principal is the authenticated caller of a request. Authentication
+// middleware store it with SetPrincipal so authorization checks such as
+// Route.RequireScopes work with any of them.
+type Principal interface {
+	Subject() string
+	HasScope(scope string) bool
+}
+
+// SetPrincipal records the authenticated caller of the request.
+func (c *Context) SetPrincipal(p Principal) {
+	c.Set(principalKey, p)
+}
+
+// Principal returns the authenticated caller, or nil.
+func (c *Context) Principal() Principal {
+	v, _ := c.Get(principalKey)
+	p, _ := v.(Principal)
+	return p
+}
+
+// RequireScopes only lets requests through when the Principal set by the
+// authentication middleware was granted every scope, answering 401 or 403
+// problem details otherwise. The check runs right before the route's last
+// handler, after the middlewares of the route. The scopes are listed in the
+// generated OpenAPI document.
+func (r *Route) RequireScopes(scopes ...string) *Route {
+	r.SetMeta(scopesMeta, scopes)
+
+	check := func(c *Context) {
+		p := c.Principal()
+		if p == nil {
+			c.Problem(&Problem{Status: http.StatusUnauthorized, Detail: "authentication required"})
+			c.Abort()
+			return
+		}
+
+		var missing []string
+		for _, s := range scopes {
+			if !p.HasScope(s) {
+				missing = append(missing, s)
+			}
+		}
+		if len(missing) > 0 {
+			c.Problem(&Problem{
+				Status:     http.StatusForbidden,
+				Detail:     "missing scopes: " + strings.Join(missing, " "),
+				Extensions: map[string]any{"required_scopes": scopes},
+			})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+
+	h := r.handlers
+	last := len(h) - 1
+	chain := make([]HandlerFunc, 0, len(h)+1)
+	chain = append(chain, h[:last]...)
+	r.handlers = append(chain, check, h[last])
+	return r
+}
+
+// Scopes returns the scopes required with RequireScopes.
+func (r *Route) Scopes() []string {
+	v, _ := r.Meta(scopesMeta)
+	scopes, _ := v.([]string)
+	return scopes
+}
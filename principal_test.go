@@ -0,0 +1,73 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+type testPrincipal []string
+
+func (p testPrincipal) Subject() string { return "tester" }
+func (p testPrincipal) HasScope(s string) bool {
+	for _, have := range p {
+		if have == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestRoute_RequireScopes(t *testing.T) {
+	auth := func(c *Context) {
+		if scopes := c.QueryParam("scopes"); scopes != "" {
+			c.SetPrincipal(testPrincipal{scopes})
+		}
+		c.Next()
+	}
+
+	an := New()
+	an.GET("/orders/:id", auth, func(c *Context) {}).RequireScopes("orders:read")
+
+	tests := []struct {
+		query string
+		code  int
+	}{
+		{"", http.StatusUnauthorized},
+		{"?scopes=profile", http.StatusForbidden},
+		{"?scopes=orders:read", http.StatusOK},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/1"+tt.query, nil))
+		if w.Code != tt.code {
+			t.Errorf("%q: status = %d, want %d", tt.query, w.Code, tt.code)
+		}
+		if w.Code != http.StatusOK && w.Header().Get("Content-Type") != MIMEProblem {
+			t.Errorf("%q: Content-Type = %q", tt.query, w.Header().Get("Content-Type"))
+		}
+	}
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/1?scopes=x", nil))
+	var problem map[string]any
+	_ = json.Unmarshal(w.Body.Bytes(), &problem)
+	if problem["status"] != float64(403) || !reflect.DeepEqual(problem["required_scopes"], []any{"orders:read"}) {
+		t.Errorf("problem = %v", problem)
+	}
+
+	doc := an.OpenAPI("shop", "1.0")
+	op := doc.Paths["/orders/{id}"]["get"]
+	if op == nil || !reflect.DeepEqual(op.Security, []map[string][]string{{"oauth2": {"orders:read"}}}) {
+		t.Fatalf("operation = %+v", op)
+	}
+	if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" {
+		t.Errorf("parameters = %+v", op.Parameters)
+	}
+}
@@ -0,0 +1,68 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// MIMEProblem is the media type of RFC 9457 problem details.
+const MIMEProblem = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object.
+type Problem struct {
+	// Type is a URI identifying the problem, "about:blank" when empty.
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions are serialized as additional members.
+	Extensions map[string]any `json:"-"`
+}
+
+// MarshalJSON inlines the extension members.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	base, err := json.Marshal((*problem)(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return base, err
+	}
+
+	members := make(map[string]any, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	var std map[string]any
+	if err := json.Unmarshal(base, &std); err != nil {
+		return nil, err
+	}
+	for k, v := range std {
+		members[k] = v
+	}
+	return json.Marshal(members)
+}
+
+// Problem writes p as application/problem+json with its status, which
+// defaults to 500. An empty Title is set from the status.
+func (c *Context) Problem(p *Problem) {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		c.InternalServerError(err)
+		return
+	}
+
+	c.SetHeader("Content-Type", MIMEProblem)
+	c.Status(p.Status)
+	_, _ = c.Writer.Write(body)
+}
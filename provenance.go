@@ -0,0 +1,92 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// HandlerInfo describes a handler or middleware of a chain.
+type HandlerInfo struct {
+	// Name is the name of the function, "main.main.func3" for the third
+	// anonymous function of main.
+	Name string
+	// Source is the "file:line" where the function is defined.
+	Source string
+}
+
+func (h HandlerInfo) String() string {
+	return h.Name + " (" + h.Source + ")"
+}
+
+var handlerInfos sync.Map // code pointer -> HandlerInfo
+
+// describeHandler returns the name and definition site of h.
+func describeHandler(h HandlerFunc) HandlerInfo {
+	pc := reflect.ValueOf(h).Pointer()
+	if info, ok := handlerInfos.Load(pc); ok {
+		return info.(HandlerInfo)
+	}
+
+	info := HandlerInfo{Name: "unknown", Source: "unknown"}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		file, line := fn.FileLine(fn.Entry())
+		info = HandlerInfo{Name: fn.Name(), Source: file + ":" + strconv.Itoa(line)}
+	}
+	handlerInfos.Store(pc, info)
+	return info
+}
+
+// describeChain describes every handler of handlers.
+func describeChain(handlers []HandlerFunc) []HandlerInfo {
+	chain := make([]HandlerInfo, len(handlers))
+	for i, h := range handlers {
+		chain[i] = describeHandler(h)
+	}
+	return chain
+}
+
+// packageDir is the directory of the framework sources, whose frames
+// registrationSource skips.
+var packageDir = func() string {
+	_, file, _, _ := runtime.Caller(0)
+	return filepath.Dir(file)
+}()
+
+// registrationSource returns the "file:line" of the first caller outside
+// the framework, where a route is being registered.
+func registrationSource() string {
+	var pcs [32]uintptr
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs[:])])
+	for {
+		frame, more := frames.Next()
+		if filepath.Dir(frame.File) != packageDir || strings.HasSuffix(frame.File, "_test.go") {
+			return frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+// Handler describes the handler of the chain currently running, e.g. to
+// tell which anonymous function failed.
+func (c *Context) Handler() (HandlerInfo, bool) {
+	if c.index < 0 || c.index >= len(c.handlers) {
+		return HandlerInfo{}, false
+	}
+	return describeHandler(c.handlers[c.index]), true
+}
+
+// traceHandler logs the handler about to run for requests with debug logs
+// enabled, see DebugRequests.
+func (c *Context) traceHandler() {
+	c.Logf("[DEBUG] %s %s handler %d/%d %s", c.Method(), c.Path(), c.index+1, len(c.handlers), describeHandler(c.handlers[c.index]))
+}
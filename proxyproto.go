@@ -0,0 +1,202 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// proxyV2Signature starts every PROXY protocol v2 header.
+var proxyV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+
+var errProxyHeader = errors.New("invalid PROXY protocol header")
+
+// ProxyProtoListener accepts connections prefixed with a PROXY protocol v1 or
+// v2 header, as sent by HAProxy or AWS NLB, and reports the client address
+// carried by the header as the connection's RemoteAddr. This keeps ClientIP,
+// rate limiting and logging correct behind TCP load balancers.
+//
+// Connections without a valid header are closed, so only use it when every
+// client goes through a proxy.
+// https://www.haproxy.org/download/2.9/doc/proxy-protocol.txt
+type ProxyProtoListener struct {
+	net.Listener
+	// HeaderTimeout bounds the time to receive the header, 5s when zero.
+	HeaderTimeout time.Duration
+}
+
+// Accept implements net.Listener. The header is read lazily by the
+// connection's goroutine so a slow client cannot block the accept loop.
+func (l *ProxyProtoListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := l.HeaderTimeout
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	return &proxyConn{Conn: conn, r: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+type proxyConn struct {
+	net.Conn
+	r       *bufio.Reader
+	timeout time.Duration
+
+	once   sync.Once
+	err    error
+	remote net.Addr
+	local  net.Addr
+}
+
+func (c *proxyConn) Read(p []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	return c.r.Read(p)
+}
+
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remote != nil {
+		return c.remote
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.local != nil {
+		return c.local
+	}
+	return c.Conn.LocalAddr()
+}
+
+func (c *proxyConn) readHeader() {
+	_ = c.Conn.SetReadDeadline(time.Now().Add(c.timeout))
+	defer func() { _ = c.Conn.SetReadDeadline(time.Time{}) }()
+
+	sig, err := c.r.Peek(len(proxyV2Signature))
+	if err == nil && bytes.Equal(sig, proxyV2Signature) {
+		c.remote, c.local, c.err = readProxyV2(c.r)
+	} else {
+		c.remote, c.local, c.err = readProxyV1(c.r)
+	}
+
+	if c.err != nil {
+		_ = c.Conn.Close()
+	}
+}
+
+// readProxyV1 parses "PROXY TCP4 src dst sport dport\r\n".
+func readProxyV1(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	// A v1 header is at most 107 bytes long.
+	var line []byte
+	for len(line) < 107 {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+	}
+
+	header, ok := strings.CutSuffix(string(line), "\r\n")
+	if !ok || !strings.HasPrefix(header, "PROXY ") {
+		return nil, nil, errProxyHeader
+	}
+
+	fields := strings.Fields(header)
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return nil, nil, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, nil, errProxyHeader
+	}
+
+	src, err := proxyTCPAddr(fields[2], fields[4])
+	if err != nil {
+		return nil, nil, err
+	}
+	dst, err := proxyTCPAddr(fields[3], fields[5])
+	if err != nil {
+		return nil, nil, err
+	}
+	return src, dst, nil
+}
+
+func proxyTCPAddr(ip, port string) (*net.TCPAddr, error) {
+	addr := net.ParseIP(ip)
+	p, err := strconv.Atoi(port)
+	if addr == nil || err != nil || p < 0 || p > 65535 {
+		return nil, errProxyHeader
+	}
+	return &net.TCPAddr{IP: addr, Port: p}, nil
+}
+
+// readProxyV2 parses the binary v2 header.
+func readProxyV2(r *bufio.Reader) (net.Addr, net.Addr, error) {
+	var hdr [16]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, nil, err
+	}
+
+	verCmd, family := hdr[12], hdr[13]
+	length := int(binary.BigEndian.Uint16(hdr[14:16]))
+	if verCmd>>4 != 2 {
+		return nil, nil, fmt.Errorf("%w: version %d", errProxyHeader, verCmd>>4)
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, nil, err
+	}
+
+	// LOCAL connections (health checks from the proxy itself) keep the real addresses.
+	if verCmd&0x0F == 0 {
+		return nil, nil, nil
+	}
+
+	switch family >> 4 {
+	case 1: // AF_INET
+		if len(payload) < 12 {
+			return nil, nil, errProxyHeader
+		}
+		return proxyV2Addrs(family, payload[0:4], payload[4:8], payload[8:10], payload[10:12])
+	case 2: // AF_INET6
+		if len(payload) < 36 {
+			return nil, nil, errProxyHeader
+		}
+		return proxyV2Addrs(family, payload[0:16], payload[16:32], payload[32:34], payload[34:36])
+	}
+
+	// AF_UNSPEC and AF_UNIX carry no usable IP address.
+	return nil, nil, nil
+}
+
+func proxyV2Addrs(family byte, src, dst, sport, dport []byte) (net.Addr, net.Addr, error) {
+	srcIP, dstIP := net.IP(append([]byte(nil), src...)), net.IP(append([]byte(nil), dst...))
+	sp, dp := int(binary.BigEndian.Uint16(sport)), int(binary.BigEndian.Uint16(dport))
+
+	if family&0x0F == 2 { // DGRAM
+		return &net.UDPAddr{IP: srcIP, Port: sp}, &net.UDPAddr{IP: dstIP, Port: dp}, nil
+	}
+	return &net.TCPAddr{IP: srcIP, Port: sp}, &net.TCPAddr{IP: dstIP, Port: dp}, nil
+}
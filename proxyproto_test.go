@@ -0,0 +1,75 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestProxyProto_readProxyV1(t *testing.T) {
+	r := bufio.NewReader(strings.NewReader("PROXY TCP4 203.0.113.7 10.0.0.1 56324 443\r\nGET / HTTP/1.1\r\n"))
+
+	src, dst, err := readProxyV1(r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if src.String() != "203.0.113.7:56324" || dst.String() != "10.0.0.1:443" {
+		t.Errorf("unexpected addresses %v -> %v", src, dst)
+	}
+	if rest, _ := r.ReadString('\n'); rest != "GET / HTTP/1.1\r\n" {
+		t.Errorf("header consumed too much: %q", rest)
+	}
+
+	if _, _, err := readProxyV1(bufio.NewReader(strings.NewReader("GET / HTTP/1.1\r\n"))); err == nil {
+		t.Errorf("expected error without header")
+	}
+}
+
+func TestProxyProto_Listener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pl := &ProxyProtoListener{Listener: ln}
+	defer pl.Close()
+
+	go func() {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var hdr bytes.Buffer
+		hdr.Write(proxyV2Signature)
+		hdr.Write([]byte{0x21, 0x11})
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(12))
+		hdr.Write(net.ParseIP("198.51.100.4").To4())
+		hdr.Write(net.ParseIP("10.0.0.1").To4())
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(40000))
+		_ = binary.Write(&hdr, binary.BigEndian, uint16(80))
+		hdr.WriteString("ping")
+		_, _ = conn.Write(hdr.Bytes())
+	}()
+
+	conn, err := pl.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if got := conn.RemoteAddr().String(); got != "198.51.100.4:40000" {
+		t.Errorf("RemoteAddr = %s", got)
+	}
+	buf := make([]byte, 4)
+	if _, err := conn.Read(buf); err != nil || string(buf) != "ping" {
+		t.Errorf("Read = %q, %v", buf, err)
+	}
+}
@@ -0,0 +1,395 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// QueryEvent describes a call made through a database connection observed
+// by ObserveConnector or OpenDB.
+type QueryEvent struct {
+	// Kind is "query", "exec", "prepare", "begin", "commit" or "rollback".
+	Kind string
+	// Query is the SQL statement, empty for transactions.
+	Query    string
+	Duration time.Duration
+	Err      error
+}
+
+// QueryHook receives the database calls along with the context they were
+// made with, so they can be attached to the trace span of the request or
+// fed to metrics.
+type QueryHook func(ctx context.Context, e QueryEvent)
+
+// ObserveConnector wraps connector so the calls made through its
+// connections are timed and reported to hooks:
+//
+//	db := sql.OpenDB(alsonow.ObserveConnector(connector, hook))
+//
+// Calls made with the context of a request served through ServerTiming,
+// c.Context() or one derived from it, are also attributed to the request.
+// Query durations run until the first results are available; the time
+// spent iterating the rows is not included.
+func ObserveConnector(connector driver.Connector, hooks ...QueryHook) driver.Connector {
+	return &observedConnector{Connector: connector, hooks: hooks}
+}
+
+// OpenDB opens a database like sql.Open, with its connections observed as
+// with ObserveConnector.
+func OpenDB(driverName, dsn string, hooks ...QueryHook) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	_ = db.Close()
+
+	var connector driver.Connector = dsnConnector{driver: d, dsn: dsn}
+	if dc, ok := d.(driver.DriverContext); ok {
+		if connector, err = dc.OpenConnector(dsn); err != nil {
+			return nil, err
+		}
+	}
+	return sql.OpenDB(ObserveConnector(connector, hooks...)), nil
+}
+
+// queryRecorderKey is the context key of the queryRecorder of a request.
+type queryRecorderKey struct{}
+
+// queryRecorder sums the database calls of a request.
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries int
+	total   time.Duration
+}
+
+// ServerTiming times the database calls made with the context of the
+// request through observed connections, and reports them in the
+// Server-Timing response header along with the time taken to start the
+// response, so they show in the network panel of the browser:
+//
+//	Server-Timing: db;dur=12.5;desc="3 queries", app;dur=20.1
+//
+// The figures are also available to handlers through c.QueryTime.
+func ServerTiming() HandlerFunc {
+	return func(c *Context) {
+		rec := &queryRecorder{}
+		c.Req = c.Req.WithContext(context.WithValue(c.Req.Context(), queryRecorderKey{}, rec))
+		start := time.Now()
+
+		w := c.Writer
+		tw := &timingWriter{ResponseWriter: w, rec: rec, start: start}
+		c.Writer = tw
+		defer func() { c.Writer = w }()
+
+		c.Next()
+		tw.setHeader()
+	}
+}
+
+// QueryTime returns the number of queries and statements the request ran,
+// and the total time of its database calls, when served through
+// ServerTiming.
+func (c *Context) QueryTime() (int, time.Duration) {
+	rec, ok := c.Context().Value(queryRecorderKey{}).(*queryRecorder)
+	if !ok {
+		return 0, 0
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return rec.queries, rec.total
+}
+
+// timingWriter adds the Server-Timing header just before the response
+// headers are sent.
+type timingWriter struct {
+	http.ResponseWriter
+	rec    *queryRecorder
+	start  time.Time
+	headed bool
+}
+
+func (w *timingWriter) setHeader() {
+	if w.headed {
+		return
+	}
+	w.headed = true
+
+	w.rec.mu.Lock()
+	queries, total := w.rec.queries, w.rec.total
+	w.rec.mu.Unlock()
+	w.Header().Add("Server-Timing", fmt.Sprintf(`db;dur=%.1f;desc="%d queries", app;dur=%.1f`,
+		milliseconds(total), queries, milliseconds(time.Since(w.start))))
+}
+
+func (w *timingWriter) WriteHeader(code int) {
+	if code < 100 || code >= 200 || code == http.StatusSwitchingProtocols {
+		w.setHeader()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timingWriter) Write(p []byte) (int, error) {
+	w.setHeader()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *timingWriter) Flush() {
+	w.setHeader()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *timingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// observedConnector wraps the connections of a driver.Connector.
+type observedConnector struct {
+	driver.Connector
+	hooks []QueryHook
+}
+
+func (c *observedConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &observedConn{Conn: conn, connector: c}, nil
+}
+
+// Close closes the wrapped connector when it is an io.Closer, as sql.DB
+// does when closed.
+func (c *observedConnector) Close() error {
+	if closer, ok := c.Connector.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// observe reports a call that started at start.
+func (c *observedConnector) observe(ctx context.Context, kind, query string, start time.Time, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+	d := time.Since(start)
+	if rec, ok := ctx.Value(queryRecorderKey{}).(*queryRecorder); ok {
+		rec.mu.Lock()
+		if kind == "query" || kind == "exec" {
+			rec.queries++
+		}
+		rec.total += d
+		rec.mu.Unlock()
+	}
+
+	e := QueryEvent{Kind: kind, Query: query, Duration: d, Err: err}
+	for _, h := range c.hooks {
+		h(ctx, e)
+	}
+}
+
+// dsnConnector is the driver.Connector of the drivers without one.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) { return c.driver.Open(c.dsn) }
+func (c dsnConnector) Driver() driver.Driver                        { return c.driver }
+
+// observedConn times the calls of a connection. The optional interfaces
+// the wrapped connection lacks fall back to the behavior of database/sql.
+type observedConn struct {
+	driver.Conn
+	connector *observedConnector
+}
+
+func (c *observedConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *observedConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	start := time.Now()
+	var stmt driver.Stmt
+	var err error
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = p.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	c.connector.observe(ctx, "prepare", query, start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &observedStmt{Stmt: stmt, conn: c, query: query}, nil
+}
+
+func (c *observedConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *observedConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	start := time.Now()
+	var tx driver.Tx
+	var err error
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		tx, err = b.BeginTx(ctx, opts)
+	} else if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		err = errors.New("sql: driver does not support non-default isolation level or read-only transactions")
+	} else {
+		tx, err = c.Conn.Begin()
+	}
+	c.connector.observe(ctx, "begin", "", start, err)
+	if err != nil {
+		return nil, err
+	}
+	return &observedTx{Tx: tx, ctx: ctx, connector: c.connector}, nil
+}
+
+func (c *observedConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	c.connector.observe(ctx, "query", query, start, err)
+	return rows, err
+}
+
+func (c *observedConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	e, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := e.ExecContext(ctx, query, args)
+	c.connector.observe(ctx, "exec", query, start, err)
+	return res, err
+}
+
+func (c *observedConn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *observedConn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *observedConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// observedStmt times the executions of a prepared statement.
+type observedStmt struct {
+	driver.Stmt
+	conn  *observedConn
+	query string
+}
+
+func (s *observedStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var res driver.Result
+	var err error
+	if e, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = e.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	s.conn.connector.observe(ctx, "exec", s.query, start, err)
+	return res, err
+}
+
+func (s *observedStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var rows driver.Rows
+	var err error
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = driverValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.conn.connector.observe(ctx, "query", s.query, start, err)
+	return rows, err
+}
+
+// CheckNamedValue defers to the statement, then to its connection, as
+// database/sql does with unwrapped statements.
+func (s *observedStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+	return s.conn.CheckNamedValue(nv)
+}
+
+// driverValues converts args for the drivers predating named values.
+func driverValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("sql: driver does not support the use of Named Parameters")
+		}
+		values[i] = arg.Value
+	}
+	return values, nil
+}
+
+// observedTx times the end of a transaction, reported with the context it
+// began with.
+type observedTx struct {
+	driver.Tx
+	ctx       context.Context
+	connector *observedConnector
+}
+
+func (t *observedTx) Commit() error {
+	start := time.Now()
+	err := t.Tx.Commit()
+	t.connector.observe(t.ctx, "commit", "", start, err)
+	return err
+}
+
+func (t *observedTx) Rollback() error {
+	start := time.Now()
+	err := t.Tx.Rollback()
+	t.connector.observe(t.ctx, "rollback", "", start, err)
+	return err
+}
@@ -0,0 +1,111 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queryTestDriver runs statements by sleeping, through ExecerContext for
+// plain calls and through a prepared statement for the others.
+type queryTestDriver struct{}
+
+func (queryTestDriver) Open(string) (driver.Conn, error) { return queryTestConn{}, nil }
+
+type queryTestConn struct{}
+
+func (queryTestConn) Prepare(string) (driver.Stmt, error) { return queryTestStmt{}, nil }
+func (queryTestConn) Close() error                        { return nil }
+func (queryTestConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (queryTestConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	time.Sleep(time.Millisecond)
+	if query == "FAIL" {
+		return nil, errors.New("failed")
+	}
+	return driver.RowsAffected(1), nil
+}
+
+type queryTestStmt struct{}
+
+func (queryTestStmt) Close() error  { return nil }
+func (queryTestStmt) NumInput() int { return -1 }
+func (queryTestStmt) Exec([]driver.Value) (driver.Result, error) {
+	time.Sleep(time.Millisecond)
+	return driver.RowsAffected(1), nil
+}
+func (queryTestStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("not supported")
+}
+
+func TestServerTiming(t *testing.T) {
+	sql.Register("alsonow-query-test", queryTestDriver{})
+	var mu sync.Mutex
+	var events []QueryEvent
+	db, err := OpenDB("alsonow-query-test", "", func(_ context.Context, e QueryEvent) {
+		mu.Lock()
+		events = append(events, e)
+		mu.Unlock()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	an := New()
+	an.GET("/", ServerTiming(), func(c *Context) {
+		if _, err := db.ExecContext(c.Context(), "UPDATE a"); err != nil {
+			t.Error(err)
+		}
+		if _, err := db.ExecContext(c.Context(), "FAIL"); err == nil {
+			t.Error("FAIL succeeded")
+		}
+		stmt, err := db.PrepareContext(c.Context(), "UPDATE b")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer stmt.Close()
+		if _, err := stmt.ExecContext(c.Context(), 1); err != nil {
+			t.Error(err)
+		}
+
+		if n, d := c.QueryTime(); n != 3 || d < 3*time.Millisecond {
+			t.Errorf("QueryTime() = %d, %v, want 3, >= 3ms", n, d)
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	timing := w.Header().Get("Server-Timing")
+	if !regexp.MustCompile(`^db;dur=\d+\.\d;desc="3 queries", app;dur=\d+\.\d$`).MatchString(timing) {
+		t.Errorf("Server-Timing = %q", timing)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	var kinds []string
+	for _, e := range events {
+		kinds = append(kinds, e.Kind+" "+e.Query)
+	}
+	want := []string{"exec UPDATE a", "exec FAIL", "prepare UPDATE b", "exec UPDATE b"}
+	if len(kinds) != len(want) || events[1].Err == nil {
+		t.Fatalf("events = %q, want %q", kinds, want)
+	}
+	for i := range want {
+		if kinds[i] != want[i] {
+			t.Errorf("events = %q, want %q", kinds, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"syscall"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// RunQUIC is RunTLS also serving HTTP/3 over QUIC on the UDP port of addr.
+// Responses over TCP carry an Alt-Svc header announcing it, so browsers
+// switch to HTTP/3 for the following requests. The UDP port must be open
+// in firewalls as well.
+func (an *AlsoNow) RunQUIC(addr, certFile, keyFile string) error {
+	if addr == "" {
+		addr = ":443"
+	}
+
+	reloader, err := NewCertReloader(certFile, keyFile)
+	if err != nil {
+		return fmt.Errorf("TLS certificate error: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go reloader.Watch(ctx, certPollInterval)
+	go reloader.ReloadOnSignal(ctx, syscall.SIGHUP)
+
+	config := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	an.applyClientAuth(config)
+
+	conn, err := net.ListenPacket("udp", addr)
+	if err != nil {
+		return err
+	}
+	// http3.Server.Close does not close the connections it is given.
+	defer conn.Close()
+	h3 := &http3.Server{
+		Addr:      addr,
+		Handler:   an.server.Handler,
+		TLSConfig: http3.ConfigureTLSConfig(config),
+	}
+	defer h3.Close()
+	go func() {
+		if err := h3.Serve(conn); err != nil && !errors.Is(err, http.ErrServerClosed) && !errors.Is(err, net.ErrClosed) {
+			log.Printf("[QUIC] %v", err)
+		}
+	}()
+
+	an.quic.Store(h3)
+	defer an.quic.Store(nil)
+	return an.runTLS(addr, config)
+}
+
+// ServeHTTP implements http.Handler. While RunQUIC runs, responses carry
+// the Alt-Svc header announcing HTTP/3.
+func (an *AlsoNow) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if h3 := an.quic.Load(); h3 != nil {
+		_ = h3.SetQuicHeaders(w.Header())
+	}
+	an.Router.ServeHTTP(w, req)
+}
@@ -0,0 +1,80 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+func TestAlsoNowRunQUIC(t *testing.T) {
+	cert, err := DevCertificate(nil, "127.0.0.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	keyDER, _ := x509.MarshalPKCS8PrivateKey(cert.PrivateKey)
+	_ = os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Certificate[0]}), 0o600)
+	_ = os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600)
+
+	an := New()
+	an.GET("/", func(c *Context) { _, _ = c.Writer.Write([]byte(c.Req.Proto)) })
+	done := make(chan error, 1)
+	go func() { done <- an.RunQUIC("127.0.0.1:2027", certFile, keyFile) }()
+	time.Sleep(200 * time.Millisecond)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(cert.Leaf)
+	tlsConfig := &tls.Config{RootCAs: roots}
+
+	tcp := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+	resp, err := tcp.Get("https://127.0.0.1:2027/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got := resp.Header.Get("Alt-Svc"); got != `h3=":2027"; ma=2592000` {
+		t.Errorf("Alt-Svc = %q", got)
+	}
+
+	rt := &http3.RoundTripper{TLSClientConfig: tlsConfig}
+	defer rt.Close()
+	resp, err = (&http.Client{Transport: rt}).Get("https://127.0.0.1:2027/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "HTTP/3.0" {
+		t.Errorf("proto = %q", body)
+	}
+
+	an.Stop()
+	if err := <-done; err != nil {
+		t.Errorf("RunQUIC = %v after Stop", err)
+	}
+	conn, err := net.ListenPacket("udp", "127.0.0.1:2027")
+	if err != nil {
+		t.Fatalf("UDP port still bound after RunQUIC returned: %v", err)
+	}
+	conn.Close()
+	rec := httptest.NewRecorder()
+	an.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get("Alt-Svc"); got != "" {
+		t.Errorf("Alt-Svc = %q after RunQUIC returned", got)
+	}
+}
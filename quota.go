@@ -0,0 +1,182 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/alsonow/alsonow/cache"
+)
+
+// Limits are the request limits of a client or tenant. Zero fields mean
+// unlimited.
+type Limits struct {
+	// Rate is the sustained number of requests per second and Burst the
+	// number of requests allowed at once.
+	Rate  float64
+	Burst int
+	// Quota is the number of requests allowed per QuotaPeriod.
+	Quota       int64
+	QuotaPeriod time.Duration
+}
+
+// LimitProvider looks up the limits of a key, typically from the plan of
+// the tenant it belongs to, so limits change without a redeploy.
+type LimitProvider interface {
+	Limits(ctx context.Context, key string) (Limits, error)
+}
+
+// LimitProviderFunc adapts a function to LimitProvider.
+type LimitProviderFunc func(ctx context.Context, key string) (Limits, error)
+
+func (f LimitProviderFunc) Limits(ctx context.Context, key string) (Limits, error) {
+	return f(ctx, key)
+}
+
+// StaticLimits returns a LimitProvider giving every key the same limits.
+func StaticLimits(l Limits) LimitProvider {
+	return LimitProviderFunc(func(context.Context, string) (Limits, error) { return l, nil })
+}
+
+// CachedLimits caches the limits returned by p for ttl, so a database or
+// billing API is not queried on every request.
+func CachedLimits(p LimitProvider, ttl time.Duration) LimitProvider {
+	return &cachedLimits{provider: p, cache: cache.NewMemory(cache.Options[string, Limits]{TTL: ttl})}
+}
+
+type cachedLimits struct {
+	provider LimitProvider
+	cache    *cache.Memory[string, Limits]
+}
+
+func (cl *cachedLimits) Limits(ctx context.Context, key string) (Limits, error) {
+	if l, ok := cl.cache.Get(key); ok {
+		return l, nil
+	}
+
+	l, err := cl.provider.Limits(ctx, key)
+	if err != nil {
+		return l, err
+	}
+	cl.cache.Set(key, l)
+	return l, nil
+}
+
+// QuotaStore counts requests per key and window.
+type QuotaStore interface {
+	// Incr increments the counter of key in the window starting at window
+	// and returns its new value. The counter may be dropped after ttl.
+	Incr(ctx context.Context, key string, window time.Time, ttl time.Duration) (int64, error)
+}
+
+// QuotaConfig configures Quota.
+type QuotaConfig struct {
+	// Limits gives the Quota and QuotaPeriod of each key.
+	Limits LimitProvider
+	// Store counts the requests, a MemoryQuotaStore when nil.
+	Store QuotaStore
+	// KeyFunc identifies the client or tenant, ClientIP when nil.
+	KeyFunc func(*Context) string
+}
+
+// Quota caps the number of requests of each key per period, such as
+// 10000 per day on a free plan. Periods are fixed windows aligned on
+// multiples of the period since the Unix epoch. The X-Quota-Limit,
+// X-Quota-Remaining and X-Quota-Reset headers report the usage, and
+// requests over quota get 429 Too Many Requests. It panics when cfg has no
+// Limits.
+func Quota(cfg QuotaConfig) HandlerFunc {
+	if cfg.Limits == nil {
+		panic("alsonow: Quota needs a LimitProvider")
+	}
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryQuotaStore()
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *Context) string { return ClientIP(c.Req) }
+	}
+
+	return func(c *Context) {
+		key := cfg.KeyFunc(c)
+
+		limits, err := cfg.Limits.Limits(c.Context(), key)
+		if err != nil {
+			c.Logf("[QUOTA] limits of %q: %v", key, err)
+			c.Next()
+			return
+		}
+		if limits.Quota <= 0 || limits.QuotaPeriod <= 0 {
+			c.Next()
+			return
+		}
+
+		window := time.Now().Truncate(limits.QuotaPeriod)
+		reset := window.Add(limits.QuotaPeriod)
+		used, err := cfg.Store.Incr(c.Context(), key, window, limits.QuotaPeriod)
+		if err != nil {
+			// Fail open: an unavailable store must not take the API down.
+			c.Logf("[QUOTA] count %q: %v", key, err)
+			c.Next()
+			return
+		}
+
+		c.SetHeader("X-Quota-Limit", strconv.FormatInt(limits.Quota, 10))
+		c.SetHeader("X-Quota-Remaining", strconv.FormatInt(max(limits.Quota-used, 0), 10))
+		c.SetHeader("X-Quota-Reset", strconv.FormatInt(reset.Unix(), 10))
+
+		if used > limits.Quota {
+			c.SetHeader("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			c.Error(http.StatusTooManyRequests, "quota exceeded")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// MemoryQuotaStore counts requests in memory.
+type MemoryQuotaStore struct {
+	mu        sync.Mutex
+	counts    map[string]quotaCount
+	lastSweep time.Time
+}
+
+type quotaCount struct {
+	window  time.Time
+	n       int64
+	expires time.Time
+}
+
+// NewMemoryQuotaStore returns an empty store.
+func NewMemoryQuotaStore() *MemoryQuotaStore {
+	return &MemoryQuotaStore{counts: make(map[string]quotaCount), lastSweep: time.Now()}
+}
+
+func (s *MemoryQuotaStore) Incr(_ context.Context, key string, window time.Time, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Counters of finished windows are dropped at most once per ttl.
+	if now := time.Now(); now.Sub(s.lastSweep) > ttl {
+		for k, old := range s.counts {
+			if now.After(old.expires) {
+				delete(s.counts, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	qc := s.counts[key]
+	if !qc.window.Equal(window) {
+		qc = quotaCount{window: window, expires: window.Add(ttl)}
+	}
+	qc.n++
+	s.counts[key] = qc
+	return qc.n, nil
+}
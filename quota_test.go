@@ -0,0 +1,62 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestQuota_PerTenant(t *testing.T) {
+	var lookups int
+	plans := LimitProviderFunc(func(_ context.Context, tenant string) (Limits, error) {
+		lookups++
+		if tenant == "pro" {
+			return Limits{Quota: 3, QuotaPeriod: time.Hour}, nil
+		}
+		return Limits{Quota: 1, QuotaPeriod: time.Hour}, nil
+	})
+
+	an := New()
+	an.GET("/", Quota(QuotaConfig{
+		Limits:  CachedLimits(plans, time.Minute),
+		KeyFunc: func(c *Context) string { return c.Header("X-Tenant") },
+	}), func(c *Context) {})
+
+	do := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	for i, want := range []int{200, 200, 200, 429} {
+		if w := do("pro"); w.Code != want {
+			t.Errorf("pro request %d: status = %d, want %d", i+1, w.Code, want)
+		}
+	}
+	if w := do("free"); w.Code != http.StatusOK || w.Header().Get("X-Quota-Remaining") != "0" {
+		t.Errorf("free: %d, remaining %q", w.Code, w.Header().Get("X-Quota-Remaining"))
+	}
+	if w := do("free"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" {
+		t.Errorf("free over quota: %d", w.Code)
+	}
+	if lookups != 2 {
+		t.Errorf("provider called %d times, want 2 with caching", lookups)
+	}
+}
+
+func TestQuota_NeedsLimits(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Quota without Limits did not panic")
+		}
+	}()
+	Quota(QuotaConfig{})
+}
@@ -0,0 +1,304 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// node represents a radix tree node.
+// https://en.wikipedia.org/wiki/Radix_tree
+type node struct {
+	children   map[string]*node
+	paramChild *node
+	// catchAll matches the rest of the path, registered as "*name".
+	catchAll  *node
+	isEnd     bool
+	paramName string
+	route     *Route
+
+	// constraint restricts the values of a param child, registered as
+	// ":name(constraintName)".
+	constraint     ParamConstraint
+	constraintName string
+}
+
+// radixMatcher is the default MatcherBackend, a radix tree per method.
+type radixMatcher struct {
+	trees map[string]*node
+}
+
+// NewRadixMatcher returns the default MatcherBackend. Static segments take
+// precedence over parameters and parameters over catch-alls, the search
+// backtracking when a branch dead-ends.
+func NewRadixMatcher() MatcherBackend {
+	return &radixMatcher{trees: make(map[string]*node)}
+}
+
+func (m *radixMatcher) getTree(method string) *node {
+	if m.trees[method] == nil {
+		m.trees[method] = &node{
+			children: make(map[string]*node),
+		}
+	}
+	return m.trees[method]
+}
+
+// Add stores route at its path.
+func (m *radixMatcher) Add(route *Route) {
+	path := normalizePath(route.Path)
+	root := m.getTree(route.Method)
+
+	if path == "/" {
+		root.isEnd = true
+		root.route = route
+		return
+	}
+
+	segments := strings.Split(path[1:], "/")
+	cur := root
+
+	for i, segment := range segments {
+		isParam := segment[0] == ':'
+		var child *node
+
+		if segment[0] == '*' {
+			if i != len(segments)-1 {
+				panic(fmt.Sprintf("cannot register '%s': catch-all '%s' must be the last segment", path, segment))
+			}
+			paramName := catchAllName(segment)
+			if cur.catchAll != nil && cur.catchAll.paramName != paramName {
+				panic(fmt.Sprintf(
+					"cannot register '%s': catch-all name '*%s' conflicts with existing '*%s' in previously registered path",
+					path, paramName, cur.catchAll.paramName,
+				))
+			}
+			if cur.catchAll == nil {
+				cur.catchAll = &node{paramName: paramName}
+			}
+			cur.catchAll.isEnd = true
+			cur.catchAll.route = route
+			return
+		}
+
+		if isParam {
+			paramName, constraintName := splitParam(segment[1:])
+			if cur.paramChild != nil {
+				if cur.paramChild.paramName != paramName {
+					panic(fmt.Sprintf(
+						"cannot register '%s': parameter name ':%s' conflicts with existing ':%s' in previously registered path",
+						path, paramName, cur.paramChild.paramName,
+					))
+				}
+				if cur.paramChild.constraintName != constraintName {
+					panic(fmt.Sprintf(
+						"cannot register '%s': constraint of ':%s' conflicts with '%s' in previously registered path",
+						path, paramName, cur.paramChild.constraintName,
+					))
+				}
+			} else {
+				cur.paramChild = &node{
+					paramName:      paramName,
+					constraintName: constraintName,
+				}
+				if constraintName != "" {
+					fn, err := compileConstraint(constraintName)
+					if err != nil {
+						panic(fmt.Sprintf("cannot register '%s': %v", path, err))
+					}
+					cur.paramChild.constraint = fn
+				}
+			}
+			child = cur.paramChild
+		} else {
+			if cur.children == nil {
+				cur.children = make(map[string]*node)
+			}
+
+			if _, ok := cur.children[segment]; !ok {
+				cur.children[segment] = &node{
+					children: make(map[string]*node),
+				}
+			}
+			child = cur.children[segment]
+		}
+
+		cur = child
+	}
+
+	// At this point, len(segments) must be greater than 0
+	cur.isEnd = true
+	cur.route = route
+}
+
+// Match returns the route matching path, appending its parameters to
+// params when not nil.
+func (m *radixMatcher) Match(method, path string, params *[]Param) *Route {
+	root := m.trees[method]
+	if root == nil {
+		return nil
+	}
+	if path == "/" {
+		path = ""
+	}
+	if n := matchNode(root, path, params); n != nil {
+		return n.route
+	}
+	return nil
+}
+
+// matchNode matches path, the part of the request path following the
+// segment of cur such as "/42/posts", below cur. Static children are
+// preferred to the param child and the param child to the catch-all. When
+// a branch dead-ends deeper down, the next alternative is tried, so
+// "/users/new/posts" still matches "/users/:id/posts" next to
+// "/users/new/edit". params only gets the values of the branch that
+// matched. Segments and values are substrings of the path, so matching
+// does not allocate.
+func matchNode(cur *node, path string, params *[]Param) *node {
+	if path == "" {
+		if cur.isEnd {
+			return cur
+		}
+		// "/static/*filepath" also matches "/static" itself.
+		if cur.catchAll != nil {
+			appendParam(params, cur.catchAll.paramName, "")
+			return cur.catchAll
+		}
+		return nil
+	}
+
+	segment, rest := path[1:], ""
+	if i := strings.IndexByte(segment, '/'); i >= 0 {
+		segment, rest = segment[:i], segment[i:]
+	}
+
+	if child, ok := cur.children[segment]; ok {
+		if n := matchNode(child, rest, params); n != nil {
+			return n
+		}
+	}
+
+	if p := cur.paramChild; p != nil && (p.constraint == nil || p.constraint(segment)) {
+		if n := matchNode(p, rest, params); n != nil {
+			appendParam(params, p.paramName, segment)
+			return n
+		}
+	}
+
+	if cur.catchAll != nil {
+		appendParam(params, cur.catchAll.paramName, path[1:])
+		return cur.catchAll
+	}
+	return nil
+}
+
+// appendParam appends a parameter to params unless params is nil, for
+// lookups only interested in the node.
+func appendParam(params *[]Param, key, value string) {
+	if params != nil {
+		*params = append(*params, Param{Key: key, Value: value})
+	}
+}
+
+// MatchFold is Match comparing static segments case-insensitively. It
+// also returns the path with the case of the route, the parameter values
+// being kept as sent.
+func (m *radixMatcher) MatchFold(method, path string, params *[]Param) (*Route, string) {
+	root := m.trees[method]
+	if root == nil || path == "/" {
+		return nil, ""
+	}
+
+	segments := strings.Split(path[1:], "/")
+	fixed := make([]string, 0, len(segments))
+	if n, fixed := foldNode(root, segments, fixed, params); n != nil {
+		for i := range fixed {
+			fixed[i] = url.PathEscape(fixed[i])
+		}
+		return n.route, "/" + strings.Join(fixed, "/")
+	}
+	return nil, ""
+}
+
+// foldNode matches segments below cur, backtracking over the static
+// children equal under case folding.
+func foldNode(cur *node, segments, fixed []string, params *[]Param) (*node, []string) {
+	if len(segments) == 0 {
+		if cur.isEnd {
+			return cur, fixed
+		}
+		if cur.catchAll != nil {
+			appendParam(params, cur.catchAll.paramName, "")
+			return cur.catchAll, fixed
+		}
+		return nil, nil
+	}
+
+	segment := segments[0]
+	for name, child := range cur.children {
+		if strings.EqualFold(name, segment) {
+			if n, f := foldNode(child, segments[1:], append(fixed, name), params); n != nil {
+				return n, f
+			}
+		}
+	}
+
+	if p := cur.paramChild; p != nil && (p.constraint == nil || p.constraint(segment)) {
+		if n, f := foldNode(p, segments[1:], append(fixed, segment), params); n != nil {
+			appendParam(params, p.paramName, segment)
+			return n, f
+		}
+	}
+
+	if cur.catchAll != nil {
+		appendParam(params, cur.catchAll.paramName, strings.Join(segments, "/"))
+		return cur.catchAll, append(fixed, segments...)
+	}
+	return nil, nil
+}
+
+// Routes returns the routes sorted by method, then static segments before
+// parameters before catch-alls.
+func (m *radixMatcher) Routes() []*Route {
+	methods := make([]string, 0, len(m.trees))
+	for method := range m.trees {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+
+	var routes []*Route
+	for _, method := range methods {
+		routes = walkRoutes(routes, m.trees[method])
+	}
+	return routes
+}
+
+// walkRoutes appends the routes of n and its descendants.
+func walkRoutes(routes []*Route, n *node) []*Route {
+	if n.isEnd {
+		routes = append(routes, n.route)
+	}
+
+	segments := make([]string, 0, len(n.children))
+	for segment := range n.children {
+		segments = append(segments, segment)
+	}
+	sort.Strings(segments)
+	for _, segment := range segments {
+		routes = walkRoutes(routes, n.children[segment])
+	}
+
+	if n.paramChild != nil {
+		routes = walkRoutes(routes, n.paramChild)
+	}
+	if n.catchAll != nil {
+		routes = walkRoutes(routes, n.catchAll)
+	}
+	return routes
+}
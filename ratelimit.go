@@ -0,0 +1,251 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// rateSweepInterval is how often the buckets of idle keys are dropped.
+const rateSweepInterval = time.Minute
+
+// RateLimitConfig configures RateLimit.
+type RateLimitConfig struct {
+	// Rate is the sustained number of requests per second allowed to each
+	// key, and Burst the number allowed at once, Rate rounded up when zero.
+	Rate  float64
+	Burst int
+	// Limits gives the Rate and Burst of each key instead, such as those
+	// of the plan of its tenant.
+	Limits LimitProvider
+	// KeyFunc identifies the client, ClientIP when nil.
+	KeyFunc func(*Context) string
+	// Store counts the requests in a store shared by the instances of a
+	// deployment, such as Redis. Requests are then limited with a sliding
+	// window rather than a token bucket: each key is allowed Rate × Window
+	// requests per Window, Burst being ignored.
+	Store RateLimitStore
+	// Window is the length of the sliding window, a minute when zero.
+	Window time.Duration
+}
+
+// RateLimitStore counts the requests of RateLimit per key, for its sliding
+// window.
+type RateLimitStore interface {
+	// Get returns the counter of key, 0 when it does not exist.
+	Get(ctx context.Context, key string) (int64, error)
+	// Incr increments the counter of key and returns its new value. The
+	// counter may be dropped ttl after its creation.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+// RateLimit limits the requests of each key, the client IP by default,
+// with a token bucket: Burst requests are allowed at once, then Rate per
+// second. Requests over the limit get 429 Too Many Requests with a
+// Retry-After header, and the X-RateLimit-Limit and X-RateLimit-Remaining
+// headers report the state of the bucket. The buckets are kept in memory,
+// those of idle keys being dropped. With a Store, the limit is shared by
+// the instances using it, and stores failing let requests through.
+func RateLimit(cfg RateLimitConfig) HandlerFunc {
+	if cfg.Limits == nil {
+		if cfg.Rate <= 0 {
+			panic("alsonow: RateLimit needs a positive Rate or a LimitProvider")
+		}
+		cfg.Limits = StaticLimits(Limits{Rate: cfg.Rate, Burst: cfg.Burst})
+	}
+	if cfg.KeyFunc == nil {
+		cfg.KeyFunc = func(c *Context) string { return ClientIP(c.Req) }
+	}
+	if cfg.Window <= 0 {
+		cfg.Window = time.Minute
+	}
+	buckets := newRateBuckets()
+
+	return func(c *Context) {
+		key := cfg.KeyFunc(c)
+
+		limits, err := cfg.Limits.Limits(c.Context(), key)
+		if err != nil {
+			c.Logf("[RATELIMIT] limits of %q: %v", key, err)
+			c.Next()
+			return
+		}
+		if limits.Rate <= 0 {
+			c.Next()
+			return
+		}
+
+		var limit, remaining int
+		var retry time.Duration
+		if cfg.Store != nil {
+			limit = max(int(limits.Rate*cfg.Window.Seconds()), 1)
+			remaining, retry, err = slidingWindow(c.Context(), cfg.Store, key, limit, cfg.Window, time.Now())
+			if err != nil {
+				// Fail open: an unavailable store must not take the API down.
+				c.Logf("[RATELIMIT] count %q: %v", key, err)
+				c.Next()
+				return
+			}
+		} else {
+			if limit = limits.Burst; limit <= 0 {
+				limit = int(math.Ceil(limits.Rate))
+			}
+			remaining, retry = buckets.take(key, limits.Rate, limit, time.Now())
+		}
+
+		c.SetHeader("X-RateLimit-Limit", strconv.Itoa(limit))
+		c.SetHeader("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		if retry > 0 {
+			c.SetHeader("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			c.Error(http.StatusTooManyRequests, "rate limit exceeded")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// rateBuckets holds the token buckets of the keys of RateLimit.
+type rateBuckets struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newRateBuckets() *rateBuckets {
+	return &rateBuckets{buckets: make(map[string]*tokenBucket), lastSweep: time.Now()}
+}
+
+// take consumes a token of the bucket of key, returning the tokens left, or
+// how long to wait for one when the bucket is empty.
+func (s *rateBuckets) take(key string, rate float64, burst int, now time.Time) (int, time.Duration) {
+	s.mu.Lock()
+	if now.Sub(s.lastSweep) > rateSweepInterval {
+		s.sweep(now)
+	}
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(burst), last: now}
+		s.buckets[key] = b
+	}
+	s.mu.Unlock()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	// The limits of a key may change, as when its tenant changes plans.
+	b.rate, b.burst = rate, float64(burst)
+	b.refill(now)
+	if b.tokens < 1 {
+		return 0, time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+	}
+	b.tokens--
+	return int(b.tokens), 0
+}
+
+// sweep drops the buckets refilled to their burst, which are as good as
+// new. s.mu must be held.
+func (s *rateBuckets) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		b.mu.Lock()
+		full := b.tokens+now.Sub(b.last).Seconds()*b.rate >= b.burst
+		b.mu.Unlock()
+		if full {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
+
+// slidingWindow counts a request of key in the current window of store and
+// estimates the requests of the last window length, weighting those of the
+// previous window by the part of it still covered. It returns the requests
+// left, or how long to wait when the estimate exceeds limit.
+func slidingWindow(ctx context.Context, store RateLimitStore, key string, limit int, window time.Duration, now time.Time) (int, time.Duration, error) {
+	start := now.Truncate(window)
+	windowKey := func(t time.Time) string { return key + ":" + strconv.FormatInt(t.UnixMilli(), 10) }
+
+	curr, err := store.Incr(ctx, windowKey(start), 2*window)
+	if err != nil {
+		return 0, 0, err
+	}
+	prev, err := store.Get(ctx, windowKey(start.Add(-window)))
+	if err != nil {
+		return 0, 0, err
+	}
+
+	elapsed := float64(now.Sub(start)) / float64(window)
+	estimate := float64(prev)*(1-elapsed) + float64(curr)
+	if estimate <= float64(limit) {
+		return int(float64(limit) - estimate), 0, nil
+	}
+
+	// Wait until the estimate leaves room for one more request, which may
+	// only happen in the next window, the current one becoming previous.
+	var at float64
+	if n := float64(limit - 1); float64(curr) <= n && prev > 0 {
+		at = 1 - (n-float64(curr))/float64(prev)
+	} else {
+		at = 1 + max(1-n/float64(curr), 0)
+	}
+	return 0, time.Duration((at - elapsed) * float64(window)), nil
+}
+
+// MemoryRateLimitStore is a RateLimitStore counting requests in memory, for
+// a single instance.
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	counts    map[string]rateCount
+	lastSweep time.Time
+}
+
+type rateCount struct {
+	n       int64
+	expires time.Time
+}
+
+// NewMemoryRateLimitStore returns an empty store.
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{counts: make(map[string]rateCount), lastSweep: time.Now()}
+}
+
+func (s *MemoryRateLimitStore) Get(_ context.Context, key string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rc, ok := s.counts[key]
+	if !ok || time.Now().After(rc.expires) {
+		return 0, nil
+	}
+	return rc.n, nil
+}
+
+func (s *MemoryRateLimitStore) Incr(_ context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Expired counters are dropped at most once per ttl.
+	now := time.Now()
+	if now.Sub(s.lastSweep) > ttl {
+		for k, rc := range s.counts {
+			if now.After(rc.expires) {
+				delete(s.counts, k)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	rc, ok := s.counts[key]
+	if !ok || now.After(rc.expires) {
+		rc = rateCount{expires: now.Add(ttl)}
+	}
+	rc.n++
+	s.counts[key] = rc
+	return rc.n, nil
+}
@@ -0,0 +1,109 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimit(t *testing.T) {
+	an := New()
+	an.GET("/", RateLimit(RateLimitConfig{Rate: 0.5, Burst: 2}), func(c *Context) {})
+
+	do := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Forwarded-For", ip)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	for i, want := range []string{"1", "0"} {
+		if w := do("192.0.2.1"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != want {
+			t.Errorf("request %d: %d, remaining %q", i+1, w.Code, w.Header().Get("X-RateLimit-Remaining"))
+		}
+	}
+	if w := do("192.0.2.1"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "2" {
+		t.Errorf("over limit: %d, Retry-After %q", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := do("192.0.2.2"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Limit") != "2" {
+		t.Errorf("other client: %d, limit %q", w.Code, w.Header().Get("X-RateLimit-Limit"))
+	}
+}
+
+func TestRateBuckets_Sweep(t *testing.T) {
+	s := newRateBuckets()
+	now := time.Now()
+	s.take("idle", 1, 5, now)
+	s.take("busy", 1, 5, now)
+	for i := 0; i < 4; i++ {
+		s.take("busy", 1, 5, now.Add(rateSweepInterval-time.Second))
+	}
+
+	// idle refilled long ago, busy still lacks a token.
+	s.take("other", 1, 5, now.Add(rateSweepInterval+time.Second))
+	if _, ok := s.buckets["idle"]; ok {
+		t.Error("idle bucket not swept")
+	}
+	if _, ok := s.buckets["busy"]; !ok {
+		t.Error("busy bucket swept")
+	}
+}
+
+func TestRateLimit_Store(t *testing.T) {
+	an := New()
+	an.GET("/", RateLimit(RateLimitConfig{Rate: 1, Window: time.Hour, Store: NewMemoryRateLimitStore(),
+		KeyFunc: func(c *Context) string { return c.Header("X-Tenant") }}), func(c *Context) {})
+
+	do := func(tenant string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Tenant", tenant)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+	for i := 0; i < 3600; i++ {
+		if w := do("a"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: %d", i+1, w.Code)
+		}
+	}
+	if w := do("a"); w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") == "" ||
+		w.Header().Get("X-RateLimit-Limit") != "3600" {
+		t.Errorf("over limit: %d, headers %v", w.Code, w.Header())
+	}
+	if w := do("b"); w.Code != http.StatusOK || w.Header().Get("X-RateLimit-Remaining") != "3599" {
+		t.Errorf("other tenant: %d, remaining %q", w.Code, w.Header().Get("X-RateLimit-Remaining"))
+	}
+}
+
+func TestSlidingWindow(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryRateLimitStore()
+	window := time.Minute
+	start := time.Now().Truncate(window)
+
+	// 10 requests at the end of the previous window.
+	for i := 0; i < 10; i++ {
+		if _, retry, _ := slidingWindow(ctx, store, "k", 10, window, start.Add(-time.Second)); retry != 0 {
+			t.Fatalf("request %d denied", i+1)
+		}
+	}
+	// A quarter into the window, 7.5 of them still count.
+	for i, want := range []int{1, 0} {
+		if remaining, retry, _ := slidingWindow(ctx, store, "k", 10, window, start.Add(window/4)); remaining != want || retry != 0 {
+			t.Errorf("request %d: %d left, retry %v", i+1, remaining, retry)
+		}
+	}
+	// 3 in this window and 7.5 before: a fourth fits once the previous
+	// window counts for 6, at 24s.
+	_, retry, _ := slidingWindow(ctx, store, "k", 10, window, start.Add(window/4))
+	if want := 9 * time.Second; retry != want {
+		t.Errorf("retry = %v, want %v", retry, want)
+	}
+}
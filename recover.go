@@ -4,20 +4,19 @@
 // license that can be found in the LICENSE file.
 package alsonow
 
-import (
-	"log"
-	"net/http"
-	"runtime/debug"
-)
+import "runtime/debug"
 
 func Recover() HandlerFunc {
 	return func(c *Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				stack := string(debug.Stack())
-				log.Printf("[PANIC] %v\n%s", err, stack)
-
-				http.Error(c.Writer, "Internal Server Error", http.StatusInternalServerError)
+				id := randomHex(4)
+				handler := "unknown"
+				if h, ok := c.Handler(); ok {
+					handler = h.String()
+				}
+				c.Logf("[PANIC] %v error_id=%s handler=%s\n%s", err, id, handler, debug.Stack())
+				c.writeServerError(id)
 			}
 		}()
 		c.Next()
@@ -0,0 +1,297 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRememberTokenNotFound is returned by a RememberMeStore for unknown series.
+var ErrRememberTokenNotFound = errors.New("remember-me token not found")
+
+// ErrRememberTokenRotated is returned by RememberMeStore.Rotate when the
+// token was rotated by another request in the meantime.
+var ErrRememberTokenRotated = errors.New("remember-me token already rotated")
+
+// RememberToken is a persistent login. The series stays the same for the
+// life of the login while the token rotates on every use; only a hash of
+// the token is stored.
+type RememberToken struct {
+	Series    string
+	TokenHash string
+	UserID    string
+	Expires   time.Time
+	// PrevTokenHash is the hash of the token replaced at Rotated, still
+	// accepted for a grace period.
+	PrevTokenHash string
+	Rotated       time.Time
+}
+
+// RememberMeStore persists remember-me tokens.
+type RememberMeStore interface {
+	Get(ctx context.Context, series string) (RememberToken, error)
+	Save(ctx context.Context, token RememberToken) error
+	Delete(ctx context.Context, series string) error
+	// DeleteUser removes every series of a user.
+	DeleteUser(ctx context.Context, userID string) error
+	// Rotate replaces the series of token if its token hash is still
+	// prevHash, and returns ErrRememberTokenRotated otherwise.
+	Rotate(ctx context.Context, prevHash string, token RememberToken) error
+}
+
+// RememberMeConfig configures a RememberMe.
+type RememberMeConfig struct {
+	// Store keeps the tokens, a MemoryRememberMeStore when nil.
+	Store RememberMeStore
+	// CookieName defaults to "alsonow_remember".
+	CookieName string
+	// MaxAge is the lifetime of a login, 30 days when zero.
+	MaxAge time.Duration
+	// SessionKey is the session value holding the user id, "user_id" when empty.
+	SessionKey string
+	// Secure marks the cookie as HTTPS only.
+	Secure bool
+	// OnTheft is called when a stolen cookie is detected, after every login
+	// of the user has been revoked.
+	OnTheft func(c *Context, userID string)
+	// RotationGrace is how long the previous token of a series stays valid
+	// after a rotation, for the requests the browser sent in parallel with
+	// the one rotating it. One minute when zero.
+	RotationGrace time.Duration
+}
+
+// RememberMe issues persistent login cookies following the series/token
+// scheme: a cookie replayed after the legitimate client rotated it reveals
+// a theft, and every login of the user is then revoked.
+type RememberMe struct {
+	cfg RememberMeConfig
+}
+
+// NewRememberMe returns a RememberMe using cfg.
+func NewRememberMe(cfg RememberMeConfig) *RememberMe {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryRememberMeStore()
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "alsonow_remember"
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = 30 * 24 * time.Hour
+	}
+	if cfg.SessionKey == "" {
+		cfg.SessionKey = "user_id"
+	}
+	if cfg.RotationGrace == 0 {
+		cfg.RotationGrace = time.Minute
+	}
+	return &RememberMe{cfg: cfg}
+}
+
+// Remember issues a new series for userID, usually after a login with the
+// "remember me" box checked.
+func (r *RememberMe) Remember(c *Context, userID string) error {
+	return r.issue(c, randomHex(16), userID)
+}
+
+// Forget revokes the series of the request and deletes its cookie.
+func (r *RememberMe) Forget(c *Context) error {
+	c.DeleteCookie(r.cfg.CookieName)
+	series, _, ok := r.cookie(c)
+	if !ok {
+		return nil
+	}
+	return r.cfg.Store.Delete(c.Context(), series)
+}
+
+// Middleware logs the client in from its remember-me cookie when the session
+// holds no user. It must run after Sessions.
+func (r *RememberMe) Middleware() HandlerFunc {
+	return func(c *Context) {
+		s := c.Session()
+		if s == nil {
+			panic("alsonow: RememberMe middleware requires Sessions")
+		}
+		if _, ok := s.Get(r.cfg.SessionKey); !ok {
+			r.login(c, s)
+		}
+		c.Next()
+	}
+}
+
+func (r *RememberMe) login(c *Context, s *Session) {
+	series, token, ok := r.cookie(c)
+	if !ok {
+		return
+	}
+
+	stored, err := r.cfg.Store.Get(c.Context(), series)
+	if err != nil || time.Now().After(stored.Expires) {
+		if err != nil && !errors.Is(err, ErrRememberTokenNotFound) {
+			c.Logf("[REMEMBER] load: %v", err)
+		}
+		c.DeleteCookie(r.cfg.CookieName)
+		return
+	}
+
+	hash := hashRememberToken(token)
+	switch {
+	case subtle.ConstantTimeCompare([]byte(hash), []byte(stored.TokenHash)) == 1:
+		err := r.rotate(c, stored)
+		if err != nil && !errors.Is(err, ErrRememberTokenRotated) {
+			c.Logf("[REMEMBER] rotate: %v", err)
+			return
+		}
+		// A parallel request rotated the token first; the browser gets
+		// the new cookie from its response.
+	case stored.PrevTokenHash != "" && time.Since(stored.Rotated) < r.cfg.RotationGrace &&
+		subtle.ConstantTimeCompare([]byte(hash), []byte(stored.PrevTokenHash)) == 1:
+		// Sent along with the request that rotated the token.
+	default:
+		// The series is valid but the token was already used: someone else
+		// holds a copy of the cookie.
+		if err := r.cfg.Store.DeleteUser(c.Context(), stored.UserID); err != nil {
+			c.Logf("[REMEMBER] revoke: %v", err)
+		}
+		c.DeleteCookie(r.cfg.CookieName)
+		if r.cfg.OnTheft != nil {
+			r.cfg.OnTheft(c, stored.UserID)
+		}
+		return
+	}
+
+	s.Regenerate()
+	s.Set(r.cfg.SessionKey, stored.UserID)
+}
+
+func (r *RememberMe) issue(c *Context, series, userID string) error {
+	token := randomHex(32)
+	err := r.cfg.Store.Save(c.Context(), RememberToken{
+		Series:    series,
+		TokenHash: hashRememberToken(token),
+		UserID:    userID,
+		Expires:   time.Now().Add(r.cfg.MaxAge),
+	})
+	if err != nil {
+		return err
+	}
+	r.setCookie(c, series, token)
+	return nil
+}
+
+// rotate gives the series of stored a new token, unless a parallel request
+// did already.
+func (r *RememberMe) rotate(c *Context, stored RememberToken) error {
+	token := randomHex(32)
+	now := time.Now()
+	err := r.cfg.Store.Rotate(c.Context(), stored.TokenHash, RememberToken{
+		Series:        stored.Series,
+		TokenHash:     hashRememberToken(token),
+		UserID:        stored.UserID,
+		Expires:       now.Add(r.cfg.MaxAge),
+		PrevTokenHash: stored.TokenHash,
+		Rotated:       now,
+	})
+	if err != nil {
+		return err
+	}
+	r.setCookie(c, stored.Series, token)
+	return nil
+}
+
+func (r *RememberMe) setCookie(c *Context, series, token string) {
+	c.SetCookie(&http.Cookie{
+		Name:     r.cfg.CookieName,
+		Value:    series + ":" + token,
+		Path:     "/",
+		MaxAge:   int(r.cfg.MaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   r.cfg.Secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func (r *RememberMe) cookie(c *Context) (series, token string, ok bool) {
+	v, err := c.Cookie(r.cfg.CookieName)
+	if err != nil {
+		return "", "", false
+	}
+	series, token, ok = strings.Cut(v, ":")
+	return series, token, ok && series != "" && token != ""
+}
+
+func hashRememberToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// MemoryRememberMeStore keeps remember-me tokens in memory.
+type MemoryRememberMeStore struct {
+	mu     sync.Mutex
+	tokens map[string]RememberToken
+}
+
+// NewMemoryRememberMeStore returns an empty store.
+func NewMemoryRememberMeStore() *MemoryRememberMeStore {
+	return &MemoryRememberMeStore{tokens: make(map[string]RememberToken)}
+}
+
+func (s *MemoryRememberMeStore) Get(_ context.Context, series string) (RememberToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[series]
+	if !ok {
+		return RememberToken{}, ErrRememberTokenNotFound
+	}
+	return t, nil
+}
+
+func (s *MemoryRememberMeStore) Save(_ context.Context, token RememberToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token.Series] = token
+	return nil
+}
+
+func (s *MemoryRememberMeStore) Rotate(_ context.Context, prevHash string, token RememberToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, ok := s.tokens[token.Series]
+	if !ok {
+		return ErrRememberTokenNotFound
+	}
+	if t.TokenHash != prevHash {
+		return ErrRememberTokenRotated
+	}
+	s.tokens[token.Series] = token
+	return nil
+}
+
+func (s *MemoryRememberMeStore) Delete(_ context.Context, series string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, series)
+	return nil
+}
+
+func (s *MemoryRememberMeStore) DeleteUser(_ context.Context, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for series, t := range s.tokens {
+		if t.UserID == userID {
+			delete(s.tokens, series)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRememberMe(t *testing.T) {
+	store := NewMemoryRememberMeStore()
+	var stolen string
+	rm := NewRememberMe(RememberMeConfig{
+		Store:   store,
+		OnTheft: func(c *Context, userID string) { stolen = userID },
+	})
+
+	an := New()
+	an.Use(Sessions(SessionConfig{}), rm.Middleware())
+	an.POST("/login", func(c *Context) {
+		c.Session().Set("user_id", "alice")
+		if err := rm.Remember(c, "alice"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	an.GET("/me", func(c *Context) {
+		v, _ := c.Session().Get("user_id")
+		_, _ = fmt.Fprint(c.Writer, v)
+	})
+
+	remember := func(w *httptest.ResponseRecorder) *http.Cookie {
+		for _, ck := range w.Result().Cookies() {
+			if ck.Name == "alsonow_remember" && ck.MaxAge > 0 {
+				return ck
+			}
+		}
+		return nil
+	}
+	me := func(ck *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/me", nil)
+		req.AddCookie(ck)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	first := remember(w)
+	if first == nil {
+		t.Fatal("no remember-me cookie issued")
+	}
+
+	// A fresh browser session is logged in and gets a rotated token.
+	w = me(first)
+	if w.Body.String() != "alice" {
+		t.Fatalf("/me = %q, want alice", w.Body.String())
+	}
+	second := remember(w)
+	if second == nil || second.Value == first.Value {
+		t.Fatal("token was not rotated")
+	}
+
+	// A request sent along with the rotating one still logs in.
+	if w = me(first); w.Body.String() != "alice" || remember(w) != nil {
+		t.Errorf("parallel request: %q, rotated again %t", w.Body.String(), remember(w) != nil)
+	}
+
+	// Replaying the old cookie later is a theft: every login of the user
+	// is revoked.
+	series, _, _ := strings.Cut(first.Value, ":")
+	tok, _ := store.Get(context.Background(), series)
+	tok.Rotated = tok.Rotated.Add(-time.Hour)
+	_ = store.Save(context.Background(), tok)
+	if w = me(first); w.Body.String() == "alice" {
+		t.Error("replayed cookie logged in")
+	}
+	if stolen != "alice" {
+		t.Errorf("OnTheft called with %q", stolen)
+	}
+	if w = me(second); w.Body.String() == "alice" {
+		t.Error("series survived theft detection")
+	}
+	if _, err := store.Get(context.Background(), "x"); err != ErrRememberTokenNotFound {
+		t.Errorf("Get = %v", err)
+	}
+}
+
+func TestRememberMe_ConcurrentRequests(t *testing.T) {
+	store := NewMemoryRememberMeStore()
+	var stolen atomic.Bool
+	rm := NewRememberMe(RememberMeConfig{
+		Store:   store,
+		OnTheft: func(*Context, string) { stolen.Store(true) },
+	})
+
+	an := New()
+	an.Use(Sessions(SessionConfig{}), rm.Middleware())
+	an.POST("/login", func(c *Context) {
+		_ = rm.Remember(c, "alice")
+	})
+	an.GET("/me", func(c *Context) {
+		v, _ := c.Session().Get("user_id")
+		_, _ = fmt.Fprint(c.Writer, v)
+	})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/login", nil))
+	var cookie *http.Cookie
+	for _, ck := range w.Result().Cookies() {
+		if ck.Name == "alsonow_remember" {
+			cookie = ck
+		}
+	}
+
+	// The session expired: the browser loads several resources at once,
+	// all with the same remember-me cookie.
+	const parallel = 8
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	bodies := make([]string, parallel)
+	rotated := make([]*http.Cookie, parallel)
+	for i := 0; i < parallel; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.AddCookie(cookie)
+			w := httptest.NewRecorder()
+			<-start
+			an.ServeHTTP(w, req)
+			bodies[i] = w.Body.String()
+			for _, ck := range w.Result().Cookies() {
+				if ck.Name == "alsonow_remember" {
+					rotated[i] = ck
+				}
+			}
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	if stolen.Load() {
+		t.Fatal("parallel requests taken for a theft")
+	}
+	var next *http.Cookie
+	for i, body := range bodies {
+		if body != "alice" {
+			t.Errorf("request %d: %q, want alice", i, body)
+		}
+		if rotated[i] != nil {
+			if next != nil {
+				t.Error("token rotated more than once")
+			}
+			next = rotated[i]
+		}
+	}
+	if next == nil {
+		t.Fatal("token not rotated")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.AddCookie(next)
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Body.String() != "alice" {
+		t.Errorf("rotated cookie: %q", w.Body.String())
+	}
+}
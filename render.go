@@ -0,0 +1,30 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// MIMEJSON is the content type of JSON responses.
+const MIMEJSON = "application/json; charset=utf-8"
+
+// JSON writes v encoded as JSON with the status code. The value is encoded
+// before anything is sent, so when encoding fails the client gets a 500
+// instead of a truncated body, and the error is returned.
+func (c *Context) JSON(code int, v any) error {
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(v); err != nil {
+		c.InternalServerError(fmt.Errorf("encode JSON: %w", err))
+		return err
+	}
+
+	c.SetHeader("Content-Type", MIMEJSON)
+	c.Status(code)
+	_, err := c.Writer.Write(buf.Bytes())
+	return err
+}
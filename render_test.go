@@ -0,0 +1,35 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestContext_JSON(t *testing.T) {
+	an := New()
+	an.GET("/ok", func(c *Context) {
+		_ = c.JSON(http.StatusCreated, map[string]int{"id": 7})
+	})
+	an.GET("/bad", func(c *Context) {
+		if err := c.JSON(http.StatusOK, map[string]any{"f": func() {}}); err == nil {
+			t.Error("expected an encoding error")
+		}
+	})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if w.Code != http.StatusCreated || w.Body.String() != "{\"id\":7}\n" || w.Header().Get("Content-Type") != MIMEJSON {
+		t.Errorf("/ok: %d %q %q", w.Code, w.Body.String(), w.Header().Get("Content-Type"))
+	}
+
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/bad", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("/bad: status = %d", w.Code)
+	}
+}
@@ -0,0 +1,86 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"log"
+	"strings"
+)
+
+// requestIDKey is the Context key of the request ID.
+const requestIDKey = "alsonow.request_id"
+
+// RequestIDConfig configures RequestID.
+type RequestIDConfig struct {
+	// Header carries the ID in both directions, "X-Request-ID" when empty.
+	Header string
+	// Generator returns new IDs, 16 random hex digits when nil.
+	Generator func() string
+	// TrustIncoming keeps a well-formed ID sent by the client or a proxy
+	// instead of generating one.
+	TrustIncoming bool
+}
+
+// RequestID gives every request an ID, sent back in the response header and
+// appended to the framework's log lines, including the access log and
+// panics, so they can be matched with each other and with client reports.
+// It should be registered first.
+func RequestID(cfg RequestIDConfig) HandlerFunc {
+	if cfg.Header == "" {
+		cfg.Header = "X-Request-ID"
+	}
+	if cfg.Generator == nil {
+		cfg.Generator = func() string { return randomHex(8) }
+	}
+
+	return func(c *Context) {
+		id := c.Header(cfg.Header)
+		if !cfg.TrustIncoming || !validRequestID(id) {
+			id = cfg.Generator()
+		}
+
+		c.Set(requestIDKey, id)
+		c.SetHeader(cfg.Header, id)
+		c.Next()
+	}
+}
+
+// RequestID returns the ID given to the request by the RequestID
+// middleware, or "".
+func (c *Context) RequestID() string {
+	v, _ := c.Get(requestIDKey)
+	id, _ := v.(string)
+	return id
+}
+
+// validRequestID accepts short IDs of letters, digits and -_.:, so that
+// client supplied IDs cannot forge log lines.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, r := range id {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("-_.:", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// Logf logs like log.Printf, appending the request ID to the first line
+// when the request has one.
+func (c *Context) Logf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	if id := c.RequestID(); id != "" {
+		suffix := " request_id=" + id
+		if i := strings.IndexByte(msg, '\n'); i >= 0 {
+			msg = msg[:i] + suffix + msg[i:]
+		} else {
+			msg += suffix
+		}
+	}
+	log.Print(msg)
+}
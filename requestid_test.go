@@ -0,0 +1,57 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID_PanicLog(t *testing.T) {
+	var buf bytes.Buffer
+	defer log.SetOutput(log.Writer())
+	log.SetOutput(&buf)
+
+	an := New()
+	an.Use(RequestID(RequestIDConfig{TrustIncoming: true}))
+	an.GET("/", func(c *Context) { panic("boom") })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "abc-123")
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+
+	if w.Header().Get("X-Request-ID") != "abc-123" {
+		t.Errorf("response ID = %q", w.Header().Get("X-Request-ID"))
+	}
+	errorID := w.Header().Get(ErrorIDHeader)
+	first, _, _ := strings.Cut(buf.String(), "\n")
+	if !strings.Contains(first, "[PANIC] boom error_id="+errorID+" handler=github.com/alsonow/alsonow.TestRequestID_PanicLog.func1 (") ||
+		!strings.HasSuffix(first, "request_id=abc-123") {
+		t.Errorf("panic log = %q", first)
+	}
+	if errorID == "" || !strings.Contains(w.Body.String(), errorID) || strings.Contains(w.Body.String(), "boom") {
+		t.Errorf("body = %q, error ID %q", w.Body.String(), errorID)
+	}
+}
+
+func TestRequestID_Untrusted(t *testing.T) {
+	var id string
+	an := New()
+	an.Use(RequestID(RequestIDConfig{TrustIncoming: true}))
+	an.GET("/", func(c *Context) { id = c.RequestID() })
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "forged\nline")
+	an.ServeHTTP(httptest.NewRecorder(), req)
+
+	if id == "" || strings.Contains(id, "forged") {
+		t.Errorf("invalid incoming ID kept: %q", id)
+	}
+}
@@ -0,0 +1,186 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+// Route is a registered route, returned by the registration methods so
+// options can be attached to it:
+//
+//	an.POST("/webhooks/stripe", h).SetMeta("auth", "signature")
+type Route struct {
+	Method string
+	// Path is the normalized pattern the route was registered with.
+	Path string
+	// Host is the host pattern of routes registered with Router.Host.
+	Host string
+
+	name string
+	meta map[string]any
+	// trailingSlash records that the route was registered with a trailing
+	// slash, its canonical form for WithRedirectTrailingSlash.
+	trailingSlash bool
+	// handlers is the chain of the route, middlewares included.
+	handlers []HandlerFunc
+	// defaults are copied into the Context data, see Group.SetDefault.
+	defaults map[string]any
+	// localeRedirect marks the bare path redirect of a Localized route, which
+	// a route registered later at the same path replaces.
+	localeRedirect bool
+	// localized is the route a per-locale variant registered in the matcher
+	// for a Localized route stands for, and locale the locale of the variant.
+	localized *Route
+	locale    string
+	// source is the "file:line" where the route was registered.
+	source string
+	router *routerImpl
+}
+
+// Name names the route so its URL can be built with AlsoNow.URL. Names
+// must be unique.
+func (r *Route) Name(name string) *Route {
+	if other, ok := r.router.names[name]; ok && other != r {
+		panic(fmt.Sprintf("route name %q already used by %s %s", name, other.Method, other.Path))
+	}
+	if r.router.names == nil {
+		r.router.names = make(map[string]*Route)
+	}
+	delete(r.router.names, r.name)
+	r.name = name
+	r.router.names[name] = r
+	return r
+}
+
+// URL builds the path of the route named name, substituting its parameters
+// with the values paired with their names:
+//
+//	an.GET("/users/:id", showUser).Name("user.show")
+//	an.URL("user.show", "id", "42") // "/users/42"
+func (an *AlsoNow) URL(name string, pairs ...string) (string, error) {
+	route, ok := an.router().names[name]
+	if !ok {
+		return "", fmt.Errorf("alsonow: no route named %q", name)
+	}
+	return expandPattern(route.Path, pairs...)
+}
+
+// URLQuery is URL followed by the query string of query, url.Values or a
+// struct encoded with EncodeQuery, so filter and pagination links use the
+// tags their handler binds with:
+//
+//	an.URLQuery("user.posts", PostFilter{Page: 2}, "id", "42") // "/users/42/posts?page=2"
+func (an *AlsoNow) URLQuery(name string, query any, pairs ...string) (string, error) {
+	u, err := an.URL(name, pairs...)
+	if err != nil {
+		return "", err
+	}
+	values, ok := query.(url.Values)
+	if !ok {
+		values = EncodeQuery(query)
+	}
+	if len(values) > 0 {
+		u += "?" + values.Encode()
+	}
+	return u, nil
+}
+
+// SetMeta attaches a value to the route, for middleware to read with
+// Context.RouteMeta. Metadata must be set before the server starts.
+func (r *Route) SetMeta(key string, value any) *Route {
+	if r.meta == nil {
+		r.meta = make(map[string]any)
+	}
+	r.meta[key] = value
+	return r
+}
+
+// Meta returns the value attached to the route under key.
+func (r *Route) Meta(key string) (any, bool) {
+	v, ok := r.meta[key]
+	return v, ok
+}
+
+// Route returns the route matching the request, or nil for requests
+// handled by a mount.
+func (c *Context) Route() *Route {
+	return c.route
+}
+
+// RouteMeta returns the metadata of the matched route under key.
+func (c *Context) RouteMeta(key string) (any, bool) {
+	if c.route == nil {
+		return nil, false
+	}
+	return c.route.Meta(key)
+}
+
+// RouteInfo describes a registered route.
+type RouteInfo struct {
+	Method string
+	// Host is the host pattern of routes registered with Router.Host.
+	Host string
+	// Name is the name given with Route.Name, if any.
+	Name string
+	// Path is the pattern, with its ":param" and "*catchAll" segments.
+	Path string
+	// Handler is the name of the last handler, Handlers those of the whole
+	// chain including middlewares.
+	Handler  string
+	Handlers []string
+	// Chain describes the handlers with their definition site, and Source
+	// is the "file:line" where the route was registered.
+	Chain  []HandlerInfo
+	Source string
+}
+
+// Routes returns the registered routes sorted by method and path, e.g. to
+// list them on an admin page or assert them in tests. The routes of any
+// host come first, followed by those of each Host sorted by pattern.
+func (an *AlsoNow) Routes() []RouteInfo {
+	r := an.router()
+	routes := routeInfos(nil, r.matcher)
+
+	patterns := make([]string, 0, len(r.hosts))
+	for pattern := range r.hosts {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		routes = routeInfos(routes, r.hosts[pattern].matcher)
+	}
+	return routes
+}
+
+// routeInfos appends the description of the routes of matcher.
+func routeInfos(infos []RouteInfo, matcher MatcherBackend) []RouteInfo {
+	for _, variant := range matcher.Routes() {
+		// The variants of Localized routes are listed at their own path.
+		route := variant
+		if variant.localized != nil {
+			route = variant.localized
+		}
+		info := RouteInfo{
+			Method:   route.Method,
+			Host:     route.Host,
+			Name:     route.name,
+			Path:     variant.Path,
+			Handlers: make([]string, len(route.handlers)),
+			Chain:    describeChain(route.handlers),
+			Source:   route.source,
+		}
+		for i, h := range route.handlers {
+			info.Handlers[i] = handlerName(h)
+		}
+		if len(info.Handlers) > 0 {
+			info.Handler = info.Handlers[len(info.Handlers)-1]
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
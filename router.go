@@ -6,60 +6,166 @@ package alsonow
 
 import (
 	"fmt"
+	"html/template"
+	"io/fs"
 	"net/http"
+	"net/url"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 )
 
 type Router interface {
 	http.Handler
-	GET(path string, handlers ...HandlerFunc)
-	POST(path string, handlers ...HandlerFunc)
-	PUT(path string, handlers ...HandlerFunc)
-	DELETE(path string, handlers ...HandlerFunc)
-	PATCH(path string, handlers ...HandlerFunc)
-	OPTIONS(path string, handlers ...HandlerFunc)
-	HEAD(path string, handlers ...HandlerFunc)
+	GET(path string, handlers ...HandlerFunc) *Route
+	POST(path string, handlers ...HandlerFunc) *Route
+	PUT(path string, handlers ...HandlerFunc) *Route
+	DELETE(path string, handlers ...HandlerFunc) *Route
+	PATCH(path string, handlers ...HandlerFunc) *Route
+	OPTIONS(path string, handlers ...HandlerFunc) *Route
+	HEAD(path string, handlers ...HandlerFunc) *Route
+	Handle(method, path string, handlers ...HandlerFunc) *Route
+	Any(path string, handlers ...HandlerFunc) []*Route
+
+	Static(prefix, dir string)
+	StaticFS(prefix string, fsys fs.FS)
+	StaticFile(path, file string)
+	Mount(prefix string, h http.Handler, middlewares ...HandlerFunc)
 
 	Group(prefix string, middlewares ...HandlerFunc) *Group
+	Host(pattern string, middlewares ...HandlerFunc) *Group
+	Localized(locales []string, middlewares ...HandlerFunc) *Group
 	Use(middlewares ...HandlerFunc)
-}
-
-// node represents a radix tree node.
-// https://en.wikipedia.org/wiki/Radix_tree
-type node struct {
-	children   map[string]*node
-	paramChild *node
-	handlers   []HandlerFunc
-	isEnd      bool
-	paramName  string
+	UseStack(names ...string)
+	NoRoute(handlers ...HandlerFunc)
 }
 
 // routerImpl router implementation
 type routerImpl struct {
-	// trees method -> root node
-	trees       map[string]*node
+	// matcher holds the routes of any host, and newMatcher creates the
+	// matchers of the router and of each Host.
+	matcher    MatcherBackend
+	newMatcher func() MatcherBackend
+	// methods lists the methods having routes, for the Allow header.
+	methods []string
+	// hosts holds the routes registered with Host, by host pattern.
+	hosts       map[string]*hostRoutes
 	middlewares []HandlerFunc
-	pool        sync.Pool
+	mounts      []mount
+	noRoute     []HandlerFunc
+	// notFound is the precomposed chain of unmatched requests, and
+	// notAllowed caches the chain of the 405 of each Allow header.
+	notFound   []HandlerFunc
+	notAllowed *sync.Map
+	// streams tracks the streaming responses, for shutdown.
+	streams streamRegistry
+	pool    sync.Pool
+
+	// stacks names the middleware stacks applied with UseStack, and
+	// routeStacks those in effect for each "METHOD /path" route.
+	stacks      []string
+	routeStacks map[string][]string
+
+	// metrics receives the timings of every handler when set.
+	metrics MiddlewareObserver
+
+	// routes lists the registered routes in registration order, and names
+	// indexes those given a name.
+	routes []*Route
+	names  map[string]*Route
+
+	// validator checks the values decoded by the Bind methods.
+	validator Validator
+
+	// redirectTrailingSlash redirects requests whose trailing slash differs
+	// from their route's.
+	redirectTrailingSlash bool
+
+	// fixedPath matches paths differing from a route only by case.
+	fixedPath FixedPathMode
+
+	// errorRenderer writes the error responses, RenderError when nil.
+	errorRenderer ErrorRenderer
+
+	// assets lists the file systems served by Static and StaticFS.
+	assets []staticAssets
+
+	// exemptPaths are let through by Maintenance, CanonicalHost and
+	// HTTPSRedirect.
+	exemptPaths []string
+
+	// publisher sends the messages of Context.Publish.
+	publisher Publisher
+
+	// jsonBodyLimit is the size of the bodies read by BindJSON.
+	jsonBodyLimit int64
+
+	// templates are rendered by Context.Fragment. They may be swapped while
+	// serving when reloaded in development.
+	templates atomic.Pointer[template.Template]
+}
+
+// anyMethods are the methods registered by Any.
+var anyMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// checkMethod panics when method is not a valid HTTP method token.
+func checkMethod(method string) {
+	if method == "" {
+		panic("alsonow: empty HTTP method")
+	}
+	for _, r := range method {
+		if r <= ' ' || r >= 0x7f || strings.ContainsRune(`()<>@,;:\"/[]?={}`, r) {
+			panic(fmt.Sprintf("alsonow: invalid HTTP method %q", method))
+		}
+	}
+}
+
+// mount dispatches every request under prefix to handlers, whatever the
+// method and the remaining path.
+type mount struct {
+	prefix   string
+	handlers []HandlerFunc
+	// chain is handlers preceded by the router middlewares, recomposed by
+	// composeChains.
+	chain []HandlerFunc
+	// host restricts the mount to the hosts of a Host group.
+	host *hostRoutes
 }
 
 type Group struct {
 	prefix      string
 	middlewares []HandlerFunc
+	stacks      []string
 	parent      *Group
 	router      *routerImpl
+	// host restricts the routes of the group to a host, see Router.Host.
+	host *hostRoutes
+	// defaults are copied into the Context data, see SetDefault.
+	defaults map[string]any
+	// locales are the locales of a Localized group.
+	locales []string
 }
 
 func newRouter() Router {
 	r := &routerImpl{
-		trees: make(map[string]*node),
+		matcher:       NewRadixMatcher(),
+		newMatcher:    NewRadixMatcher,
+		validator:     TagValidator{},
+		exemptPaths:   append([]string(nil), defaultExemptPaths...),
+		jsonBodyLimit: defaultJSONBodyLimit,
 	}
 	r.pool.New = func() any {
 		return &Context{
-			params: make(map[string]string, 4),
+			params: make([]Param, 0, 4),
 			data:   make(map[string]any, 10),
 		}
 	}
+	r.composeChains()
 	return r
 }
 
@@ -86,138 +192,148 @@ func normalizePath(path string) string {
 	return path
 }
 
-func (r *routerImpl) getTree(method string) *node {
-	if r.trees[method] == nil {
-		r.trees[method] = &node{
-			children: make(map[string]*node),
-		}
+// addRoute registers the route in the matcher of host, or in the one
+// matching any host when nil.
+func (r *routerImpl) addRoute(host *hostRoutes, method, path string, middlewares, handlers []HandlerFunc) *Route {
+	route := r.newRoute(host, method, path, middlewares, handlers)
+	if prev := r.lookupRoute(host, method, route.Path); prev != nil && prev.localeRedirect {
+		r.routes = slices.DeleteFunc(r.routes, func(other *Route) bool { return other == prev })
 	}
-	return r.trees[method]
+	r.matcherOf(host).Add(route)
+	r.register(route)
+	return route
 }
 
-func (r *routerImpl) insert(method, path string, combined []HandlerFunc) {
-	path = normalizePath(path)
-	root := r.getTree(method)
-
-	if path == "/" {
-		root.isEnd = true
-		root.handlers = combined
-		return
+// newRoute builds the route of path without registering it.
+func (r *routerImpl) newRoute(host *hostRoutes, method, path string, middlewares, handlers []HandlerFunc) *Route {
+	// If middlewares is nil, use an empty slice instead.
+	if middlewares == nil {
+		middlewares = []HandlerFunc{}
 	}
 
-	fmt.Println(path)
-	segments := strings.Split(path[1:], "/")
-	cur := root
-
-	for _, segment := range segments {
-		isParam := segment[0] == ':'
-		var child *node
-
-		if isParam {
-			paramName := segment[1:]
-			if cur.paramChild != nil {
-				if cur.paramChild.paramName != paramName {
-					panic(fmt.Sprintf(
-						"cannot register '%s': parameter name ':%s' conflicts with existing ':%s' in previously registered path",
-						path, paramName, cur.paramChild.paramName,
-					))
-				}
-			} else {
-				cur.paramChild = &node{
-					paramName: paramName,
-				}
-			}
-			child = cur.paramChild
-		} else {
-			if cur.children == nil {
-				cur.children = make(map[string]*node)
-			}
-
-			if _, ok := cur.children[segment]; !ok {
-				cur.children[segment] = &node{
-					children: make(map[string]*node),
-				}
-			}
-			child = cur.children[segment]
-		}
+	combined := make([]HandlerFunc, 0, len(middlewares)+len(handlers))
+	combined = append(combined, middlewares...)
+	combined = append(combined, handlers...)
 
-		cur = child
+	translated, err := TranslatePattern(path)
+	if err != nil {
+		panic(fmt.Sprintf("cannot register '%s': %v", path, err))
 	}
+	path = translated
 
-	// At this point, len(segments) must be greater than 0
-	cur.isEnd = true
-	cur.handlers = combined
+	route := &Route{Method: method, Path: normalizePath(path), handlers: combined, source: registrationSource(), router: r}
+	route.trailingSlash = route.Path != "/" && strings.HasSuffix(strings.TrimSpace(path), "/")
+	if host != nil {
+		route.Host = host.pattern
+	}
+	return route
 }
 
-func (r *routerImpl) search(method, path string) ([]HandlerFunc, map[string]string) {
-	path = normalizePath(path)
-	root := r.trees[method]
-	if root == nil {
-		return nil, nil
+// matcherOf returns the matcher of host, or the one matching any host when
+// nil.
+func (r *routerImpl) matcherOf(host *hostRoutes) MatcherBackend {
+	if host != nil {
+		return host.matcher
 	}
+	return r.matcher
+}
 
-	if path == "/" {
-		if root.isEnd {
-			return root.handlers, nil
-		}
-		return nil, nil
+// register lists route among the routes of the router.
+func (r *routerImpl) register(route *Route) {
+	r.routes = append(r.routes, route)
+	if !slices.Contains(r.methods, route.Method) {
+		r.methods = append(r.methods, route.Method)
 	}
+}
 
-	segments := strings.Split(path[1:], "/")
-	params := make(map[string]string)
-	cur := root
-
-	for _, segment := range segments {
-		if cur.children != nil {
-			if child, ok := cur.children[segment]; ok {
-				cur = child
-				continue
-			}
-		}
-
-		if cur.paramChild != nil {
-			cur = cur.paramChild
-			params[cur.paramName] = segment
-			continue
-		}
-
-		return nil, nil
+// lookupRoute returns the route registered for method and path on host, or
+// nil.
+func (r *routerImpl) lookupRoute(host *hostRoutes, method, path string) *Route {
+	if translated, err := TranslatePattern(path); err == nil {
+		path = translated
 	}
-
-	if cur.isEnd {
-		return cur.handlers, params
+	path = normalizePath(path)
+	pattern := ""
+	if host != nil {
+		pattern = host.pattern
 	}
-
-	return nil, nil
+	for _, route := range r.routes {
+		if route.Method == method && route.Path == path && route.Host == pattern {
+			return route
+		}
+	}
+	return nil
 }
 
-func (r *routerImpl) addRoute(method, path string, middlewares, handlers []HandlerFunc) {
-	// If middlewares is nil, use an empty slice instead.
-	if middlewares == nil {
-		middlewares = []HandlerFunc{}
+// recordStacks remembers which middleware stacks apply to a route.
+func (r *routerImpl) recordStacks(method, path string, stacks []string) {
+	if len(stacks) == 0 {
+		return
+	}
+	if r.routeStacks == nil {
+		r.routeStacks = make(map[string][]string)
 	}
+	r.routeStacks[method+" "+normalizePath(path)] = append([]string(nil), stacks...)
+}
 
-	combined := make([]HandlerFunc, 0, len(middlewares)+len(handlers))
-	combined = append(combined, middlewares...)
-	combined = append(combined, handlers...)
+// handle registers a route directly on the router.
+func (r *routerImpl) handle(method, path string, h []HandlerFunc) *Route {
+	route := r.addRoute(nil, method, path, r.middlewares, h)
+	r.recordStacks(method, path, r.stacks)
+	return route
+}
 
-	r.insert(method, path, combined)
+// Routes registered on the router run the middlewares registered with Use
+// before them, like group routes do.
+func (r *routerImpl) GET(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodGet, path, h)
+}
+func (r *routerImpl) POST(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodPost, path, h)
+}
+func (r *routerImpl) PUT(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodPut, path, h)
+}
+func (r *routerImpl) DELETE(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodDelete, path, h)
+}
+func (r *routerImpl) PATCH(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodPatch, path, h)
+}
+func (r *routerImpl) OPTIONS(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodOptions, path, h)
+}
+func (r *routerImpl) HEAD(path string, h ...HandlerFunc) *Route {
+	return r.handle(http.MethodHead, path, h)
 }
 
-func (r *routerImpl) GET(path string, h ...HandlerFunc)  { r.addRoute(http.MethodGet, path, nil, h) }
-func (r *routerImpl) POST(path string, h ...HandlerFunc) { r.addRoute(http.MethodPost, path, nil, h) }
-func (r *routerImpl) PUT(path string, h ...HandlerFunc)  { r.addRoute(http.MethodPut, path, nil, h) }
-func (r *routerImpl) DELETE(path string, h ...HandlerFunc) {
-	r.addRoute(http.MethodDelete, path, nil, h)
+// Handle registers a route for any method, such as TRACE or the WebDAV
+// PROPFIND. Methods are case-sensitive.
+func (r *routerImpl) Handle(method, path string, h ...HandlerFunc) *Route {
+	checkMethod(method)
+	return r.handle(method, path, h)
 }
-func (r *routerImpl) PATCH(path string, h ...HandlerFunc) { r.addRoute(http.MethodPatch, path, nil, h) }
-func (r *routerImpl) OPTIONS(path string, h ...HandlerFunc) {
-	r.addRoute(http.MethodOptions, path, nil, h)
+
+// Any registers the route for every standard method.
+func (r *routerImpl) Any(path string, h ...HandlerFunc) []*Route {
+	routes := make([]*Route, len(anyMethods))
+	for i, method := range anyMethods {
+		routes[i] = r.handle(method, path, h)
+	}
+	return routes
 }
-func (r *routerImpl) HEAD(path string, h ...HandlerFunc) { r.addRoute(http.MethodHead, path, nil, h) }
 
+// Use appends global middlewares. They apply to routes registered afterwards.
 func (r *routerImpl) Use(m ...HandlerFunc) {
 	r.middlewares = append(r.middlewares, m...)
+	r.composeChains()
+}
+
+// UseStack appends the middlewares of the named stacks, see Stack.
+func (r *routerImpl) UseStack(names ...string) {
+	r.middlewares = append(r.middlewares, Stacked(names...)...)
+	r.stacks = append(r.stacks, names...)
+	r.composeChains()
 }
 
 func (r *routerImpl) Group(prefix string, m ...HandlerFunc) *Group {
@@ -228,38 +344,137 @@ func (r *routerImpl) Group(prefix string, m ...HandlerFunc) *Group {
 	}
 }
 
+// collectMiddlewares returns the middlewares of the router and of every
+// group from the outermost to g, in the order they run.
 func (g *Group) collectMiddlewares() []HandlerFunc {
-	var mids []HandlerFunc
-	current := g
-	for current != nil {
-		mids = append(mids, current.middlewares...)
-		current = current.parent
+	var groups []*Group
+	for current := g; current != nil; current = current.parent {
+		groups = append(groups, current)
 	}
 
-	mids = append(mids, g.router.middlewares...)
+	mids := append([]HandlerFunc(nil), g.router.middlewares...)
+	for i := len(groups) - 1; i >= 0; i-- {
+		mids = append(mids, groups[i].middlewares...)
+	}
 	return mids
 }
 
-func (g *Group) add(method, path string, h ...HandlerFunc) {
+func (g *Group) add(method, path string, h ...HandlerFunc) *Route {
 	fullPath := g.prefix
-	if path = normalizePath(path); path != "/" {
+	if normalized := normalizePath(path); normalized != "/" {
 		if !strings.HasSuffix(fullPath, "/") {
 			fullPath += "/"
 		}
-		fullPath += strings.TrimPrefix(path, "/")
+		fullPath += strings.TrimPrefix(normalized, "/")
+		// Keep the trailing slash for WithRedirectTrailingSlash.
+		if strings.HasSuffix(strings.TrimSpace(path), "/") {
+			fullPath += "/"
+		}
 	}
 
 	middlewares := g.collectMiddlewares()
-	g.router.addRoute(method, fullPath, middlewares, h)
+	var route *Route
+	if lg := g.localized(); lg != nil {
+		route = g.addLocalized(lg, method, fullPath, middlewares, h)
+	} else {
+		route = g.router.addRoute(g.host, method, fullPath, middlewares, h)
+	}
+	route.defaults = g.collectDefaults()
+	g.router.recordStacks(method, fullPath, g.collectStacks())
+	if g.localized() != nil {
+		g.addLocaleRedirect(method, fullPath)
+	}
+	return route
+}
+
+// collectStacks returns the names of the stacks applied to the router and
+// to every group from the outermost to g.
+func (g *Group) collectStacks() []string {
+	var groups []*Group
+	for current := g; current != nil; current = current.parent {
+		groups = append(groups, current)
+	}
+
+	stacks := append([]string(nil), g.router.stacks...)
+	for i := len(groups) - 1; i >= 0; i-- {
+		stacks = append(stacks, groups[i].stacks...)
+	}
+	return stacks
 }
 
-func (g *Group) GET(path string, h ...HandlerFunc)     { g.add(http.MethodGet, path, h...) }
-func (g *Group) POST(path string, h ...HandlerFunc)    { g.add(http.MethodPost, path, h...) }
-func (g *Group) PUT(path string, h ...HandlerFunc)     { g.add(http.MethodPut, path, h...) }
-func (g *Group) DELETE(path string, h ...HandlerFunc)  { g.add(http.MethodDelete, path, h...) }
-func (g *Group) PATCH(path string, h ...HandlerFunc)   { g.add(http.MethodPatch, path, h...) }
-func (g *Group) OPTIONS(path string, h ...HandlerFunc) { g.add(http.MethodOptions, path, h...) }
-func (g *Group) HEAD(path string, h ...HandlerFunc)    { g.add(http.MethodHead, path, h...) }
+// SetDefault sets a value copied into the data of the Context of every
+// request to the routes of the group and of its subgroups, for handlers to
+// read with Context.Get, so feature modules can carry their own wiring:
+//
+//	billing := an.Group("/billing")
+//	billing.SetDefault("invoices", invoiceService)
+//
+// Like middlewares, defaults apply to the routes registered afterwards.
+// Subgroups override the values of their parents, and handlers and
+// middlewares may override them for a request with Context.Set.
+func (g *Group) SetDefault(key string, value any) *Group {
+	if g.defaults == nil {
+		g.defaults = make(map[string]any)
+	}
+	g.defaults[key] = value
+	return g
+}
+
+// collectDefaults merges the defaults of every group from the outermost to
+// g, or returns nil when there are none.
+func (g *Group) collectDefaults() map[string]any {
+	var defaults map[string]any
+	var groups []*Group
+	for current := g; current != nil; current = current.parent {
+		groups = append(groups, current)
+	}
+	for i := len(groups) - 1; i >= 0; i-- {
+		for k, v := range groups[i].defaults {
+			if defaults == nil {
+				defaults = make(map[string]any)
+			}
+			defaults[k] = v
+		}
+	}
+	return defaults
+}
+
+// UseStack appends the middlewares of the named stacks to the group. They
+// apply to routes registered on the group afterwards.
+func (g *Group) UseStack(names ...string) *Group {
+	g.middlewares = append(g.middlewares, Stacked(names...)...)
+	g.stacks = append(g.stacks, names...)
+	return g
+}
+
+func (g *Group) GET(path string, h ...HandlerFunc) *Route  { return g.add(http.MethodGet, path, h...) }
+func (g *Group) POST(path string, h ...HandlerFunc) *Route { return g.add(http.MethodPost, path, h...) }
+func (g *Group) PUT(path string, h ...HandlerFunc) *Route  { return g.add(http.MethodPut, path, h...) }
+func (g *Group) DELETE(path string, h ...HandlerFunc) *Route {
+	return g.add(http.MethodDelete, path, h...)
+}
+func (g *Group) PATCH(path string, h ...HandlerFunc) *Route {
+	return g.add(http.MethodPatch, path, h...)
+}
+func (g *Group) OPTIONS(path string, h ...HandlerFunc) *Route {
+	return g.add(http.MethodOptions, path, h...)
+}
+func (g *Group) HEAD(path string, h ...HandlerFunc) *Route { return g.add(http.MethodHead, path, h...) }
+
+// Handle registers a route for any method on the group.
+func (g *Group) Handle(method, path string, h ...HandlerFunc) *Route {
+	checkMethod(method)
+	return g.add(method, path, h...)
+}
+
+// Any registers the route for every standard method on the group.
+func (g *Group) Any(path string, h ...HandlerFunc) []*Route {
+	routes := make([]*Route, len(anyMethods))
+	for i, method := range anyMethods {
+		routes[i] = g.add(method, path, h...)
+	}
+	return routes
+}
 
 func (g *Group) Group(sub string, m ...HandlerFunc) *Group {
 	newPrefix := g.prefix
@@ -273,19 +488,213 @@ func (g *Group) Group(sub string, m ...HandlerFunc) *Group {
 		middlewares: m,
 		parent:      g,
 		router:      g.router,
+		host:        g.host,
+	}
+}
+
+// NoRoute sets the handlers run when no route matches, after the global
+// middlewares. By default a plain 404 is written.
+func (r *routerImpl) NoRoute(h ...HandlerFunc) {
+	r.noRoute = h
+	r.composeChains()
+}
+
+// composeChains precomposes the chains of unmatched requests and of the
+// mounts with the router middlewares, as they change.
+func (r *routerImpl) composeChains() {
+	r.notFound = r.notFoundChain()
+	r.notAllowed = new(sync.Map)
+	for i := range r.mounts {
+		m := &r.mounts[i]
+		m.chain = make([]HandlerFunc, 0, len(r.middlewares)+len(m.handlers))
+		m.chain = append(append(m.chain, r.middlewares...), m.handlers...)
+	}
+}
+
+// notFoundChain returns the handlers run for unmatched requests.
+func (r *routerImpl) notFoundChain() []HandlerFunc {
+	handlers := r.noRoute
+	if len(handlers) == 0 {
+		handlers = []HandlerFunc{notFound}
+	}
+
+	combined := make([]HandlerFunc, 0, len(r.middlewares)+len(handlers))
+	combined = append(combined, r.middlewares...)
+	return append(combined, handlers...)
+}
+
+// trailingSlashRedirect returns the canonical URL of req when its trailing
+// slash differs from the one route was registered with. Catch-all routes
+// keep whatever they are given.
+func trailingSlashRedirect(route *Route, req *http.Request) (string, bool) {
+	if route == nil || route.Path == "/" || strings.Contains(route.Path, "/*") {
+		return "", false
+	}
+	if strings.HasSuffix(req.URL.Path, "/") == route.trailingSlash {
+		return "", false
+	}
+
+	// The normalized path starts with a single slash, so the target can
+	// never be a protocol-relative URL to another host.
+	target := normalizePath(req.URL.EscapedPath())
+	if route.trailingSlash {
+		target += "/"
+	}
+	if req.URL.RawQuery != "" {
+		target += "?" + req.URL.RawQuery
+	}
+	return target, true
+}
+
+// redirectChain returns the handlers redirecting req to target: 301 for
+// GET and HEAD, 308 otherwise so the method and body are kept.
+func (r *routerImpl) redirectChain(req *http.Request, target string) []HandlerFunc {
+	code := permanentRedirectCode(req.Method)
+	combined := make([]HandlerFunc, 0, len(r.middlewares)+1)
+	combined = append(combined, r.middlewares...)
+	return append(combined, func(c *Context) {
+		http.Redirect(c.Writer, c.Req, target, code)
+	})
+}
+
+// allowedMethods returns the comma separated methods other than method
+// having a route for path, on any host or on host, for the Allow header of
+// a 405.
+func (r *routerImpl) allowedMethods(host *hostRoutes, method, path string) string {
+	path = normalizePath(path)
+	var allowed []string
+	for _, m := range r.methods {
+		if m == method {
+			continue
+		}
+		if r.matcher.Match(m, path, nil) != nil || (host != nil && host.matcher.Match(m, path, nil) != nil) {
+			allowed = append(allowed, m)
+		}
+	}
+	sort.Strings(allowed)
+	return strings.Join(allowed, ", ")
+}
+
+// methodNotAllowedChain returns the handlers run when the path only has
+// routes for other methods. The chains are composed once per Allow header,
+// made of registered methods only.
+func (r *routerImpl) methodNotAllowedChain(allowed string) []HandlerFunc {
+	if chain, ok := r.notAllowed.Load(allowed); ok {
+		return chain.([]HandlerFunc)
+	}
+	combined := make([]HandlerFunc, 0, len(r.middlewares)+1)
+	combined = append(combined, r.middlewares...)
+	combined = append(combined, func(c *Context) {
+		c.SetHeader("Allow", allowed)
+		c.Error(http.StatusMethodNotAllowed, "")
+	})
+	chain, _ := r.notAllowed.LoadOrStore(allowed, combined)
+	return chain.([]HandlerFunc)
+}
+
+func notFound(c *Context) {
+	c.Error(http.StatusNotFound, "")
+}
+
+// mount registers handlers for every path under prefix. Mounts are only
+// consulted when no route matches.
+func (r *routerImpl) mount(host *hostRoutes, prefix string, handlers []HandlerFunc) {
+	r.mounts = append(r.mounts, mount{prefix: normalizePath(prefix), handlers: handlers, host: host})
+	r.composeChains()
+}
+
+// Mount dispatches every request under prefix, whatever its method, to h
+// with the prefix stripped from the path, e.g. to serve a metrics or pprof
+// handler. The global middlewares and then middlewares run before h. Routes
+// take precedence over mounts.
+//
+//	an.Mount("/metrics", promhttp.Handler())
+func (r *routerImpl) Mount(prefix string, h http.Handler, m ...HandlerFunc) {
+	r.mount(nil, prefix, mountHandlers(prefix, h, m))
+}
+
+// Mount dispatches every request under prefix in the group to h, see
+// Router.Mount. The group middlewares run before middlewares.
+func (g *Group) Mount(prefix string, h http.Handler, m ...HandlerFunc) {
+	fullPath := g.prefix
+	if prefix = normalizePath(prefix); prefix != "/" {
+		fullPath = strings.TrimSuffix(fullPath, "/") + prefix
+	}
+
+	// The router applies its own middlewares to mounts.
+	mids := g.collectMiddlewares()[len(g.router.middlewares):]
+	g.router.mount(g.host, fullPath, mountHandlers(fullPath, h, append(mids, m...)))
+}
+
+// mountHandlers returns middlewares followed by h served with prefix
+// stripped from the request path.
+func mountHandlers(prefix string, h http.Handler, middlewares []HandlerFunc) []HandlerFunc {
+	prefix = normalizePath(prefix)
+	if prefix == "/" {
+		prefix = ""
+	}
+
+	handlers := make([]HandlerFunc, 0, len(middlewares)+1)
+	handlers = append(handlers, middlewares...)
+	return append(handlers, func(c *Context) {
+		req := new(http.Request)
+		*req = *c.Req
+		req.URL = new(url.URL)
+		*req.URL = *c.Req.URL
+		req.URL.Path = "/" + strings.TrimPrefix(strings.TrimPrefix(c.Req.URL.Path, prefix), "/")
+		if c.Req.URL.RawPath != "" {
+			req.URL.RawPath = "/" + strings.TrimPrefix(strings.TrimPrefix(c.Req.URL.RawPath, prefix), "/")
+		}
+		h.ServeHTTP(c.Writer, req)
+	})
+}
+
+// matchMount returns the longest mount prefix covering path and its
+// handlers, among the mounts of any host and those of host. A mount of
+// host wins over one of any host with the same prefix.
+func (r *routerImpl) matchMount(host *hostRoutes, path string) (string, []HandlerFunc) {
+	path = normalizePath(path)
+
+	var best *mount
+	for i := range r.mounts {
+		m := &r.mounts[i]
+		if m.host != nil && m.host != host {
+			continue
+		}
+		if m.prefix != "/" && path != m.prefix && !strings.HasPrefix(path, m.prefix+"/") {
+			continue
+		}
+		if best == nil || len(m.prefix) > len(best.prefix) || (len(m.prefix) == len(best.prefix) && m.host != nil) {
+			best = m
+		}
 	}
+	if best == nil {
+		return "", nil
+	}
+	return best.prefix, best.chain
 }
 
-func (r *routerImpl) acquireCtx(w http.ResponseWriter, req *http.Request, h []HandlerFunc) *Context {
+func (r *routerImpl) acquireCtx(w http.ResponseWriter, req *http.Request) *Context {
 	ctx := r.pool.Get().(*Context)
-	ctx.Writer = w
+	ctx.resp.reset(w)
+	ctx.resp.streams = &r.streams
+	ctx.Writer = &ctx.resp
 	ctx.Req = req
-	ctx.handlers = h
 	ctx.index = -1
 	ctx.aborted = false
+	ctx.abortStatus, ctx.abortPriority = 0, 0
+	ctx.debug = false
+	ctx.metrics = r.metrics
+	ctx.validator = r.validator
+	ctx.errorRenderer = r.errorRenderer
+	ctx.templates = r.templates.Load()
+	ctx.exemptPaths = r.exemptPaths
+	ctx.publisher = r.publisher
+	ctx.jsonBodyLimit = r.jsonBodyLimit
+	ctx.childTime = 0
 
 	// go1.21+
-	clear(ctx.params)
+	ctx.params = ctx.params[:0]
 	clear(ctx.data)
 
 	return ctx
@@ -293,23 +702,86 @@ func (r *routerImpl) acquireCtx(w http.ResponseWriter, req *http.Request, h []Ha
 
 func (r *routerImpl) releaseCtx(ctx *Context) {
 	ctx.handlers = nil
+	ctx.route = nil
+	ctx.mount = ""
 	ctx.Writer = nil
+	if ctx.resp.streaming {
+		r.streams.remove(&ctx.resp)
+	}
+	ctx.resp.reset(nil)
 	ctx.Req = nil
 	r.pool.Put(ctx)
 }
 
 func (r *routerImpl) ServeHTTP(w http.ResponseWriter, req *http.Request) {
-	handlers, params := r.search(req.Method, req.URL.Path)
-	if handlers == nil {
-		http.NotFound(w, req)
-		return
+	// The parameters are appended to the pooled slice of the context, so
+	// matching a route does not allocate.
+	ctx := r.acquireCtx(w, req)
+
+	host, label := r.matchHost(req.Host)
+	if host != nil {
+		ctx.route, ctx.handlers = r.lookup(host.matcher, req, &ctx.params)
+		if ctx.handlers != nil && host.param != "" {
+			ctx.params = append(ctx.params, Param{Key: host.param, Value: label})
+		}
 	}
-
-	ctx := r.acquireCtx(w, req, handlers)
-	for k, v := range params {
-		ctx.params[k] = v
+	if ctx.handlers == nil {
+		ctx.route, ctx.handlers = r.lookup(r.matcher, req, &ctx.params)
+	}
+	if ctx.route != nil {
+		for k, v := range ctx.route.defaults {
+			ctx.data[k] = v
+		}
+	}
+	if ctx.handlers == nil {
+		ctx.mount, ctx.handlers = r.matchMount(host, req.URL.Path)
+	}
+	if ctx.handlers == nil {
+		if allowed := r.allowedMethods(host, req.Method, req.URL.Path); allowed != "" {
+			ctx.handlers = r.methodNotAllowedChain(allowed)
+		} else {
+			ctx.handlers = r.notFound
+		}
 	}
 
 	ctx.Next()
+	ctx.writeAbortStatus()
+	ctx.runAfterCommit()
 	r.releaseCtx(ctx)
 }
+
+// lookup returns the route of matcher matching req and its handlers,
+// appending its parameters to params, or nil handlers when none matches.
+// Requests to redirect get the redirect handlers and no route.
+func (r *routerImpl) lookup(matcher MatcherBackend, req *http.Request, params *[]Param) (*Route, []HandlerFunc) {
+	mark := len(*params)
+	path := normalizePath(req.URL.Path)
+	if route := matcher.Match(req.Method, path, params); route != nil {
+		if r.redirectTrailingSlash {
+			if target, ok := trailingSlashRedirect(route, req); ok {
+				*params = (*params)[:mark]
+				return nil, r.redirectChain(req, target)
+			}
+		}
+		route = resolveLocalized(route, params)
+		return route, route.handlers
+	}
+
+	fm, ok := matcher.(FoldMatcher)
+	if r.fixedPath == FixedPathOff || !ok {
+		return nil, nil
+	}
+	route, fixed := fm.MatchFold(req.Method, path, params)
+	if route == nil {
+		return nil, nil
+	}
+	if r.fixedPath == FixedPathRedirect {
+		*params = (*params)[:mark]
+		if req.URL.RawQuery != "" {
+			fixed += "?" + req.URL.RawQuery
+		}
+		return nil, r.redirectChain(req, fixed)
+	}
+	route = resolveLocalized(route, params)
+	return route, route.handlers
+}
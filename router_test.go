@@ -6,6 +6,8 @@ package alsonow
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 )
@@ -93,3 +95,699 @@ func TestRouter_ClearAndLoopDelete(t *testing.T) {
 		clear(m)
 	})
 }
+
+func TestRouter_MiddlewareOrder(t *testing.T) {
+	var trace []string
+	mark := func(name string) HandlerFunc {
+		return func(c *Context) {
+			trace = append(trace, name)
+			c.Next()
+		}
+	}
+
+	r := newRouter()
+	r.Use(mark("global"))
+	r.GET("/direct", mark("handler"))
+	api := r.Group("/api", mark("api"))
+	api.Group("/v1", mark("v1")).GET("/users", mark("handler"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/direct", "global,handler"},
+		{"/api/v1/users", "global,api,v1,handler"},
+	}
+
+	for _, tt := range tests {
+		trace = nil
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if got := strings.Join(trace, ","); got != tt.want {
+			t.Errorf("%s: ran %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRouter_CatchAll(t *testing.T) {
+	r := newRouter()
+	echo := func(name string) HandlerFunc {
+		return func(c *Context) { _, _ = c.Writer.Write([]byte(name + "=" + c.Param("filepath"))) }
+	}
+	r.GET("/static/*filepath", echo("static"))
+	r.GET("/static/index.html", echo("index"))
+	r.GET("/files/:bucket/*filepath", echo("files"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/static/css/app.css", "static=css/app.css"},
+		{"/static/a", "static=a"},
+		{"/static", "static="},
+		{"/static/index.html", "index="},
+		{"/files/photos/2025/cat.jpg", "files=2025/cat.jpg"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Body.String() != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.path, w.Body.String(), tt.want)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("catch-all in the middle of a path did not panic")
+		}
+	}()
+	r.GET("/bad/*rest/more", echo("bad"))
+}
+
+func TestRouter_NoRoute(t *testing.T) {
+	var logged []string
+	r := newRouter()
+	r.Use(func(c *Context) {
+		c.Next()
+		logged = append(logged, c.Path())
+	})
+	r.GET("/", func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("default: status = %d", w.Code)
+	}
+
+	r.NoRoute(func(c *Context) {
+		_ = c.JSON(http.StatusNotFound, map[string]string{"error": "not found"})
+	})
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != MIMEJSON {
+		t.Errorf("custom: %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+
+	if len(logged) != 2 {
+		t.Errorf("global middleware ran for %v", logged)
+	}
+}
+
+func TestContext_AbortNested(t *testing.T) {
+	var trace []string
+	wrap := func(name string) HandlerFunc {
+		return func(c *Context) {
+			trace = append(trace, name+">")
+			c.Next()
+			trace = append(trace, "<"+name)
+		}
+	}
+
+	r := newRouter()
+	r.GET("/",
+		wrap("outer"),
+		wrap("inner"),
+		func(c *Context) {
+			trace = append(trace, "auth")
+			c.AbortWithStatus(http.StatusUnauthorized)
+		},
+		func(c *Context) { trace = append(trace, "handler") },
+	)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if got := strings.Join(trace, ","); got != "outer>,inner>,auth,<inner,<outer" {
+		t.Errorf("ran %q", got)
+	}
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestContext_AbortWithPriority(t *testing.T) {
+	var seen []int
+	guard := func(status, priority int) HandlerFunc {
+		return func(c *Context) {
+			c.Next()
+			c.AbortWithPriority(status, priority)
+			seen = append(seen, c.StatusCode())
+		}
+	}
+	handler := func(c *Context) {
+		c.AbortWithPriority(http.StatusNotFound, 1)
+		seen = append(seen, c.StatusCode())
+	}
+
+	r := newRouter()
+	r.GET("/auth", guard(http.StatusForbidden, 5), guard(http.StatusUnauthorized, 10), handler,
+		func(c *Context) { t.Error("handler after abort ran") })
+	r.GET("/low", guard(http.StatusTeapot, 0), handler)
+	r.GET("/written", guard(http.StatusUnauthorized, 10), func(c *Context) {
+		c.Status(http.StatusAccepted)
+		c.AbortWithPriority(http.StatusNotFound, 1)
+	})
+
+	tests := []struct {
+		path   string
+		status int
+		seen   []int
+	}{
+		{"/auth", http.StatusUnauthorized, []int{http.StatusNotFound, http.StatusUnauthorized, http.StatusUnauthorized}},
+		{"/low", http.StatusNotFound, []int{http.StatusNotFound, http.StatusNotFound}},
+		{"/written", http.StatusAccepted, []int{http.StatusAccepted}},
+	}
+	for _, tt := range tests {
+		seen = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: status %d, want %d", tt.path, w.Code, tt.status)
+		}
+		if fmt.Sprint(seen) != fmt.Sprint(tt.seen) {
+			t.Errorf("%s: statuses seen %v, want %v", tt.path, seen, tt.seen)
+		}
+	}
+}
+
+func TestContext_NextLongChain(t *testing.T) {
+	count := 0
+	handlers := make([]HandlerFunc, 300)
+	for i := range handlers {
+		handlers[i] = func(c *Context) { count++ }
+	}
+
+	r := newRouter()
+	r.GET("/", handlers...)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if count != len(handlers) {
+		t.Errorf("ran %d handlers, want %d", count, len(handlers))
+	}
+}
+
+func listUsers(c *Context) {}
+
+func TestAlsoNow_Routes(t *testing.T) {
+	an := New()
+	an.GET("/users", listUsers)
+	api := an.Group("/api")
+	api.POST("/users/:id", func(c *Context) {})
+	an.GET("/files/*path", func(c *Context) {})
+	an.GET("/", func(c *Context) {})
+
+	var got []string
+	for _, r := range an.Routes() {
+		got = append(got, r.Method+" "+r.Path)
+	}
+	want := "GET /,GET /files/*path,GET /users,POST /api/users/:id"
+	if strings.Join(got, ",") != want {
+		t.Errorf("Routes = %v, want %s", got, want)
+	}
+
+	users := an.Routes()[2]
+	if !strings.HasSuffix(users.Handler, ".listUsers") || len(users.Handlers) != 2 {
+		t.Errorf("handler = %q, chain = %v", users.Handler, users.Handlers)
+	}
+	if !strings.Contains(users.Source, "router_test.go:") {
+		t.Errorf("source = %q", users.Source)
+	}
+	if last := users.Chain[len(users.Chain)-1]; !strings.Contains(last.Source, "router_test.go:") || !strings.Contains(users.Chain[0].Source, "recover.go:") {
+		t.Errorf("chain = %v", users.Chain)
+	}
+}
+
+func TestAlsoNow_URL(t *testing.T) {
+	an := New()
+	an.GET("/users/:id", func(c *Context) {}).Name("user.show")
+	an.Group("/files").GET("/*path", func(c *Context) {}).Name("file")
+
+	if u, err := an.URL("user.show", "id", "a b"); err != nil || u != "/users/a%20b" {
+		t.Errorf("URL(user.show) = %q, %v", u, err)
+	}
+	if u, err := an.URL("file", "path", "docs/read me.txt"); err != nil || u != "/files/docs/read%20me.txt" {
+		t.Errorf("URL(file) = %q, %v", u, err)
+	}
+	if _, err := an.URL("user.show"); err == nil {
+		t.Error("missing parameter accepted")
+	}
+	if _, err := an.URL("nope"); err == nil {
+		t.Error("unknown name accepted")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate name did not panic")
+		}
+	}()
+	an.GET("/other", func(c *Context) {}).Name("file")
+}
+
+func TestRouter_HandleAny(t *testing.T) {
+	r := newRouter()
+	r.Handle("PROPFIND", "/dav", func(c *Context) { c.Status(207) })
+	r.Group("/api").Any("/echo", func(c *Context) { c.SetHeader("X-Method", c.Req.Method) })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PROPFIND", "/dav", nil))
+	if w.Code != 207 {
+		t.Errorf("PROPFIND status = %d", w.Code)
+	}
+
+	for _, method := range []string{http.MethodGet, http.MethodDelete, http.MethodTrace} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/api/echo", nil))
+		if w.Header().Get("X-Method") != method {
+			t.Errorf("Any did not handle %s", method)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid method did not panic")
+		}
+	}()
+	r.Handle("BAD METHOD", "/", func(c *Context) {})
+}
+
+func TestRouter_Mount(t *testing.T) {
+	var paths []string
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { paths = append(paths, r.URL.Path) })
+
+	an := New()
+	global := 0
+	an.Use(func(c *Context) { global++; c.Next() })
+	an.Mount("/metrics", h)
+	an.Group("/admin").Mount("/debug", h, func(c *Context) {
+		if c.Header("X-Admin") == "" {
+			c.Status(http.StatusForbidden)
+			c.Abort()
+			return
+		}
+		c.Next()
+	})
+
+	for _, path := range []string{"/metrics", "/metrics/a/b", "/metricsx"} {
+		an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/debug/pprof", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("group middleware not run: %d", w.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/admin/debug/pprof", nil)
+	req.Header.Set("X-Admin", "1")
+	an.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got := strings.Join(paths, ","); got != "/,/a/b,/pprof" {
+		t.Errorf("paths = %s", got)
+	}
+	if global != 5 {
+		t.Errorf("global middleware ran %d times, want 5", global)
+	}
+}
+
+func TestRouter_ParamConstraints(t *testing.T) {
+	echo := func(name string) HandlerFunc {
+		return func(c *Context) { _, _ = c.Writer.Write([]byte(name)) }
+	}
+	r := newRouter()
+	r.GET("/users/:id(int)", echo("user"))
+	r.GET("/files/:name(uuid)", echo("file"))
+	r.GET("/posts/:slug([a-z0-9-]+)", echo("post"))
+
+	tests := []struct {
+		path, want string
+		code       int
+	}{
+		{"/users/42", "user", http.StatusOK},
+		{"/users/-1", "user", http.StatusOK},
+		{"/users/abc", "", http.StatusNotFound},
+		{"/files/123e4567-e89b-12d3-a456-426614174000", "file", http.StatusOK},
+		{"/files/123E4567-E89B-12D3-A456-426614174000", "file", http.StatusOK},
+		{"/files/readme.txt", "", http.StatusNotFound},
+		{"/files/" + strings.Repeat("%10", 8) + "-e89b-12d3-a456-426614174000", "", http.StatusNotFound},
+		{"/files/123e4567-e89b-12d3-a456-42661417400g", "", http.StatusNotFound},
+		{"/posts/hello-world-2", "post", http.StatusOK},
+		{"/posts/Hello", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code || (tt.want != "" && w.Body.String() != tt.want) {
+			t.Errorf("%s: %d %q", tt.path, w.Code, w.Body.String())
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("invalid regular expression did not panic")
+		}
+	}()
+	r.GET("/x/:id([a-z)", echo("x"))
+}
+
+func TestRouter_RedirectTrailingSlash(t *testing.T) {
+	an := New().WithRedirectTrailingSlash()
+	an.GET("/users", func(c *Context) {})
+	an.Group("/docs").GET("/guide/", func(c *Context) {})
+	an.POST("/items", func(c *Context) {})
+	an.GET("/static/*filepath", func(c *Context) {})
+	an.GET("/:page", func(c *Context) {})
+
+	tests := []struct {
+		method, path string
+		code         int
+		location     string
+	}{
+		{http.MethodGet, "/users", http.StatusOK, ""},
+		{http.MethodGet, "/users/?page=2", http.StatusMovedPermanently, "/users?page=2"},
+		{http.MethodGet, "/docs/guide", http.StatusMovedPermanently, "/docs/guide/"},
+		{http.MethodGet, "/docs/guide/", http.StatusOK, ""},
+		{http.MethodPost, "/items/", http.StatusPermanentRedirect, "/items"},
+		{http.MethodGet, "/static/css/", http.StatusOK, ""},
+		{http.MethodGet, "//evil.com/", http.StatusMovedPermanently, "/evil.com"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Code != tt.code || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s %s: %d %q", tt.method, tt.path, w.Code, w.Header().Get("Location"))
+		}
+	}
+}
+
+func TestRouter_FixedPath(t *testing.T) {
+	an := New().WithFixedPath(FixedPathRedirect)
+	an.GET("/users/:id", func(c *Context) { _, _ = c.Writer.Write([]byte(c.Param("id"))) })
+	an.GET("/Docs/API", func(c *Context) {})
+
+	tests := []struct {
+		path, location string
+	}{
+		{"/Users/AbC", "/users/AbC"},
+		{"/USERS/a%20b?x=1", "/users/a%20b?x=1"},
+		{"/docs/api", "/Docs/API"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != tt.location {
+			t.Errorf("%s: %d %q", tt.path, w.Code, w.Header().Get("Location"))
+		}
+	}
+
+	an.WithFixedPath(FixedPathMatch)
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/USERS/AbC", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "AbC" {
+		t.Errorf("match: %d %q", w.Code, w.Body.String())
+	}
+
+	an.WithFixedPath(FixedPathOff)
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/USERS/AbC", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("off: %d", w.Code)
+	}
+}
+
+func TestRouter_Host(t *testing.T) {
+	an := New()
+	write := func(s string) HandlerFunc {
+		return func(c *Context) { _, _ = c.Writer.Write([]byte(s + c.Param("tenant"))) }
+	}
+	an.GET("/", write("www"))
+	an.GET("/health", write("health"))
+	an.Host("api.example.com").GET("/", write("api"))
+	an.Host("API.example.com").Group("/v1").POST("/users", write("users"))
+	an.Host(":tenant.example.com").GET("/", write("tenant:"))
+
+	tests := []struct {
+		method, host, path string
+		code               int
+		body               string
+	}{
+		{http.MethodGet, "example.com", "/", 200, "www"},
+		{http.MethodGet, "api.example.com", "/", 200, "api"},
+		{http.MethodGet, "Api.Example.com:8080", "/", 200, "api"},
+		{http.MethodGet, "api.example.com", "/health", 200, "health"},
+		{http.MethodPost, "api.example.com", "/v1/users", 200, "users"},
+		{http.MethodPost, "example.com", "/v1/users", 404, ""},
+		{http.MethodGet, "api.example.com", "/v1/users", 405, ""},
+		{http.MethodGet, "acme.example.com", "/", 200, "tenant:acme"},
+		{http.MethodGet, "a.b.example.com", "/", 200, "www"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Host = tt.host
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+			t.Errorf("%s %s%s: %d %q", tt.method, tt.host, tt.path, w.Code, w.Body.String())
+		}
+	}
+
+	if routes := an.Routes(); len(routes) != 5 || routes[2].Host != ":tenant.example.com" || routes[4].Host != "api.example.com" {
+		t.Errorf("routes: %+v", routes)
+	}
+}
+
+func TestRouter_Backtracking(t *testing.T) {
+	an := New()
+	write := func(s string) HandlerFunc {
+		return func(c *Context) { _, _ = c.Writer.Write([]byte(s + c.Param("id") + c.Param("path"))) }
+	}
+	an.GET("/users/new/edit", write("edit"))
+	an.GET("/users/:id/posts", write("posts:"))
+	an.GET("/files/special/readme", write("readme"))
+	an.GET("/files/*path", write("file:"))
+
+	tests := []struct{ path, body string }{
+		{"/users/new/edit", "edit"},
+		{"/users/new/posts", "posts:new"},
+		{"/users/42/posts", "posts:42"},
+		{"/files/special/readme", "readme"},
+		{"/files/special/other", "file:special/other"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != tt.body {
+			t.Errorf("%s: %d %q, want %q", tt.path, w.Code, w.Body.String(), tt.body)
+		}
+	}
+}
+
+func TestRouter_BracePatterns(t *testing.T) {
+	an := New()
+	write := func(c *Context) {
+		_, _ = c.Writer.Write([]byte(c.Param("user_id") + "|" + c.Param("id") + "|" + c.Param("path") + "|" + c.Param("*")))
+	}
+	an.GET("/users/{user_id}/posts/{id:[0-9]+}", write).Name("post")
+	an.GET("/files/{path:.*}", write)
+	an.GET("/assets/*", write)
+
+	tests := []struct{ path, body string }{
+		{"/users/u1/posts/42", "u1|42||"},
+		{"/files/a/b.txt", "||a/b.txt|"},
+		{"/assets/css/site.css", "|||css/site.css"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != http.StatusOK || w.Body.String() != tt.body {
+			t.Errorf("%s: %d %q, want %q", tt.path, w.Code, w.Body.String(), tt.body)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/u1/posts/abc", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("constraint not applied: %d", w.Code)
+	}
+	if u, err := an.URL("post", "user_id", "u1", "id", "7"); err != nil || u != "/users/u1/posts/7" {
+		t.Errorf("URL = %q, %v", u, err)
+	}
+	if _, err := TranslatePattern("/files/{name}.json"); err == nil {
+		t.Error("partial segment accepted")
+	}
+}
+
+func TestRouter_QuantifiedRegexPatterns(t *testing.T) {
+	an := New()
+	write := func(c *Context) {
+		_, _ = c.Writer.Write([]byte(c.Param("year") + "|" + c.Param("day")))
+	}
+	an.GET("/posts/:year([0-9]{4})", write)
+	an.GET("/days/{day:[0-9]{2}}", write)
+
+	tests := []struct {
+		path, body string
+		status     int
+	}{
+		{"/posts/2025", "2025|", http.StatusOK},
+		{"/posts/25", "", http.StatusNotFound},
+		{"/days/07", "|07", http.StatusOK},
+		{"/days/7", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.status || (tt.status == http.StatusOK && w.Body.String() != tt.body) {
+			t.Errorf("%s: %d %q, want %d %q", tt.path, w.Code, w.Body.String(), tt.status, tt.body)
+		}
+	}
+}
+
+// discardWriter is a ResponseWriter that does not allocate.
+type discardWriter struct{ h http.Header }
+
+func (w *discardWriter) Header() http.Header         { return w.h }
+func (w *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardWriter) WriteHeader(int)             {}
+
+func benchmarkRoute(b *testing.B, pattern, path string) {
+	r := newRouter()
+	r.GET(pattern, func(c *Context) { _ = c.Param("id") })
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	w := &discardWriter{h: make(http.Header)}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkRouter_Static(b *testing.B) {
+	benchmarkRoute(b, "/users/all", "/users/all")
+}
+
+func BenchmarkRouter_Params(b *testing.B) {
+	benchmarkRoute(b, "/users/:id/posts/:post", "/users/42/posts/7")
+}
+
+// exactMatcher is a MatcherBackend matching static paths only.
+type exactMatcher struct{ routes map[string]*Route }
+
+func (m *exactMatcher) Add(route *Route) { m.routes[route.Method+" "+route.Path] = route }
+func (m *exactMatcher) Match(method, path string, params *[]Param) *Route {
+	return m.routes[method+" "+path]
+}
+func (m *exactMatcher) Routes() []*Route {
+	var routes []*Route
+	for _, r := range m.routes {
+		routes = append(routes, r)
+	}
+	return routes
+}
+
+func TestAlsoNow_WithMatcher(t *testing.T) {
+	an := New().WithMatcher(func() MatcherBackend { return &exactMatcher{routes: map[string]*Route{}} })
+	an.GET("/users/:id", func(c *Context) { _, _ = c.Writer.Write([]byte("literal")) })
+	an.Host("api.example.com").POST("/ping", func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/:id", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "literal" {
+		t.Errorf("custom matcher: %d %q", w.Code, w.Body.String())
+	}
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("param matched by exact matcher: %d", w.Code)
+	}
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Host = "api.example.com"
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("host matcher: %d %q", w.Code, w.Header().Get("Allow"))
+	}
+	if routes := an.Routes(); len(routes) != 2 {
+		t.Errorf("routes: %+v", routes)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("WithMatcher after registration did not panic")
+		}
+	}()
+	an.WithMatcher(NewRadixMatcher)
+}
+
+func TestRouter_MatchDoesNotAllocate(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector allocates")
+	}
+	r := newRouter()
+	r.GET("/users/:id/files/*path", func(c *Context) {})
+	r.GET("/users/new/edit", func(c *Context) {})
+	w := &discardWriter{h: make(http.Header)}
+
+	for _, path := range []string{"/users/new/files/a/b", "/users/new/edit"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if allocs := testing.AllocsPerRun(100, func() { r.ServeHTTP(w, req) }); allocs != 0 {
+			t.Errorf("%s: %v allocs per request", path, allocs)
+		}
+	}
+}
+
+func TestRouter_PrecomposedChains(t *testing.T) {
+	r := newRouter().(*routerImpl)
+	r.POST("/items", func(c *Context) {})
+	r.Mount("/metrics", http.NotFoundHandler())
+	var used []string
+	r.Use(func(c *Context) {
+		used = append(used, c.Path())
+		c.Next()
+	})
+
+	_, first := r.matchMount(nil, "/metrics/x")
+	_, second := r.matchMount(nil, "/metrics/y")
+	if len(first) != 2 || &first[0] != &second[0] {
+		t.Errorf("mount chain recomposed per request: %d handlers", len(first))
+	}
+	if a, b := r.methodNotAllowedChain("POST"), r.methodNotAllowedChain("POST"); len(a) != 2 || &a[0] != &b[0] {
+		t.Errorf("405 chain recomposed per request: %d handlers", len(a))
+	}
+
+	// The middlewares registered after the mount still run before it.
+	for _, path := range []string{"/metrics", "/items"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != http.StatusNotFound && w.Code != http.StatusMethodNotAllowed {
+			t.Errorf("GET %s: %d", path, w.Code)
+		}
+	}
+	if strings.Join(used, ",") != "/metrics,/items" {
+		t.Errorf("middleware ran for %v", used)
+	}
+}
+
+func TestGroup_SetDefault(t *testing.T) {
+	an := New()
+	api := an.Group("/api").SetDefault("db", "main").SetDefault("service", "api")
+	billing := api.Group("/billing").SetDefault("service", "billing")
+	read := func(c *Context) {
+		db, _ := c.GetString("db")
+		svc, _ := c.GetString("service")
+		_, _ = c.Writer.Write([]byte(db + "/" + svc))
+	}
+	api.GET("/users", read)
+	billing.GET("/invoices", read)
+	an.GET("/other", read)
+
+	tests := []struct{ path, body string }{
+		{"/api/users", "main/api"},
+		{"/api/billing/invoices", "main/billing"},
+		{"/other", "/"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Body.String() != tt.body {
+			t.Errorf("%s: %q, want %q", tt.path, w.Body.String(), tt.body)
+		}
+	}
+}
@@ -0,0 +1,270 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultSampleBodySize is the size of the bodies kept by default.
+const defaultSampleBodySize = 64 << 10
+
+// defaultRedactedHeaders are the headers whose values are not recorded by
+// default, since they carry credentials.
+var defaultRedactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-CSRF-Token"}
+
+// TrafficSample is a request and its response recorded by SampleTraffic.
+type TrafficSample struct {
+	Time time.Time
+	// Method and Route identify the route, Route being its pattern or
+	// UnmatchedRouteLabel.
+	Method string
+	Route  string
+	// URL is the path and query of the request.
+	URL           string
+	RequestHeader http.Header
+	// RequestBody holds the part of the body read by the handlers, up to
+	// the MaxBodySize of the config, and RequestTruncated tells whether it
+	// is incomplete; the same goes for the response.
+	RequestBody       []byte
+	RequestTruncated  bool
+	Status            int
+	ResponseHeader    http.Header
+	ResponseBody      []byte
+	ResponseTruncated bool
+	Duration          time.Duration
+}
+
+// SampleStore stores the samples of SampleTraffic, for contract tests or
+// to check an OpenAPI document against real traffic.
+type SampleStore interface {
+	Save(ctx context.Context, s *TrafficSample) error
+}
+
+// SampleConfig configures SampleTraffic.
+type SampleConfig struct {
+	Store SampleStore
+	// PerHour is the number of requests sampled per route and hour, 10
+	// when zero.
+	PerHour int
+	// MaxBodySize bounds the bodies kept, 64 KB when zero.
+	MaxBodySize int
+	// RedactHeaders lists the headers whose values are replaced by
+	// "[REDACTED]", Authorization, Proxy-Authorization, Cookie, Set-Cookie,
+	// X-Api-Key and X-CSRF-Token when nil.
+	RedactHeaders []string
+}
+
+// SampleTraffic records the first PerHour requests of every route each
+// hour, with their responses, into cfg.Store. Samples are saved once the
+// response is written, in the background, and failures are logged. It
+// should be registered after Compress, to record uncompressed responses.
+func SampleTraffic(cfg SampleConfig) HandlerFunc {
+	if cfg.Store == nil {
+		panic("alsonow: SampleTraffic needs a Store")
+	}
+	if cfg.PerHour <= 0 {
+		cfg.PerHour = 10
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = defaultSampleBodySize
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = defaultRedactedHeaders
+	}
+	counts := &sampleCounts{counts: make(map[string]int)}
+
+	return func(c *Context) {
+		route := RouteLabel(c)
+		if !counts.take(sampleMethod(c.Method())+" "+route, cfg.PerHour, time.Now()) {
+			c.Next()
+			return
+		}
+
+		s := &TrafficSample{
+			Time:          time.Now(),
+			Method:        c.Method(),
+			Route:         route,
+			URL:           c.Req.URL.RequestURI(),
+			RequestHeader: redactHeader(c.Req.Header, cfg.RedactHeaders),
+		}
+		reqBody := &sampleBuffer{limit: cfg.MaxBodySize}
+		if c.Req.Body != nil && c.Req.Body != http.NoBody {
+			body := c.Req.Body
+			c.Req.Body = &sampleReader{ReadCloser: body, buf: reqBody}
+			defer func() { c.Req.Body = body }()
+		}
+		respBody := &sampleBuffer{limit: cfg.MaxBodySize}
+		w := c.Writer
+		c.Writer = &sampleWriter{ResponseWriter: w, buf: respBody}
+		defer func() { c.Writer = w }()
+
+		c.Next()
+
+		s.Duration = time.Since(s.Time)
+		s.RequestBody, s.RequestTruncated = reqBody.data, reqBody.truncated
+		s.Status = c.StatusCode()
+		s.ResponseHeader = redactHeader(w.Header(), cfg.RedactHeaders)
+		s.ResponseBody, s.ResponseTruncated = respBody.data, respBody.truncated
+
+		// The Context is reused once the request completes.
+		ctx := context.WithoutCancel(c.Context())
+		go func() {
+			if err := cfg.Store.Save(ctx, s); err != nil {
+				log.Printf("[SAMPLE] save %s %s: %v", s.Method, s.Route, err)
+			}
+		}()
+	}
+}
+
+// Undocumented returns the samples of operations missing from the
+// document, such as routes added since it was published, so it can be
+// checked against real traffic.
+func (d *OpenAPIDocument) Undocumented(samples []*TrafficSample) []*TrafficSample {
+	var missing []*TrafficSample
+	for _, s := range samples {
+		if s.Route == UnmatchedRouteLabel {
+			continue
+		}
+		path, _ := openAPIPath(s.Route)
+		if _, ok := d.Paths[path][strings.ToLower(s.Method)]; !ok {
+			missing = append(missing, s)
+		}
+	}
+	return missing
+}
+
+// sampleMethod returns method, or "OTHER" for the non-standard methods
+// clients may make up, so they share a single count.
+func sampleMethod(method string) string {
+	for _, m := range anyMethods {
+		if m == method {
+			return m
+		}
+	}
+	return "OTHER"
+}
+
+// sampleCounts counts the samples of each route in the current hour. The
+// counts of past hours are dropped.
+type sampleCounts struct {
+	mu     sync.Mutex
+	hour   int64
+	counts map[string]int
+}
+
+// take reports whether a request of route can be sampled at now, counting
+// it when so.
+func (s *sampleCounts) take(route string, perHour int, now time.Time) bool {
+	hour := now.Unix() / 3600
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if hour != s.hour {
+		clear(s.counts)
+		s.hour = hour
+	}
+	if s.counts[route] >= perHour {
+		return false
+	}
+	s.counts[route]++
+	return true
+}
+
+// redactHeader returns a copy of h with the values of the redacted headers
+// replaced.
+func redactHeader(h http.Header, redacted []string) http.Header {
+	h = h.Clone()
+	for _, name := range redacted {
+		if values := h.Values(name); len(values) > 0 {
+			h[http.CanonicalHeaderKey(name)] = []string{"[REDACTED]"}
+		}
+	}
+	return h
+}
+
+// sampleBuffer keeps the first limit bytes written to it.
+type sampleBuffer struct {
+	data      []byte
+	limit     int
+	truncated bool
+}
+
+func (b *sampleBuffer) write(p []byte) {
+	if room := b.limit - len(b.data); len(p) > room {
+		p = p[:room]
+		b.truncated = true
+	}
+	b.data = append(b.data, p...)
+}
+
+type sampleReader struct {
+	io.ReadCloser
+	buf *sampleBuffer
+}
+
+func (r *sampleReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.buf.write(p[:n])
+	return n, err
+}
+
+type sampleWriter struct {
+	http.ResponseWriter
+	buf *sampleBuffer
+}
+
+func (w *sampleWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.buf.write(p[:n])
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (w *sampleWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *sampleWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// MemorySampleStore keeps the latest samples in memory.
+type MemorySampleStore struct {
+	mu      sync.Mutex
+	samples []*TrafficSample
+	size    int
+}
+
+// NewMemorySampleStore returns a store keeping the latest size samples.
+func NewMemorySampleStore(size int) *MemorySampleStore {
+	return &MemorySampleStore{size: max(size, 1)}
+}
+
+func (s *MemorySampleStore) Save(_ context.Context, sample *TrafficSample) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.samples) >= s.size {
+		copy(s.samples, s.samples[1:])
+		s.samples = s.samples[:len(s.samples)-1]
+	}
+	s.samples = append(s.samples, sample)
+	return nil
+}
+
+// Samples returns the stored samples, oldest first.
+func (s *MemorySampleStore) Samples() []*TrafficSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*TrafficSample(nil), s.samples...)
+}
@@ -0,0 +1,91 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampleTraffic(t *testing.T) {
+	store := NewMemorySampleStore(10)
+	an := New()
+	an.Use(SampleTraffic(SampleConfig{Store: store, PerHour: 2, MaxBodySize: 8}))
+	an.POST("/users/:id", func(c *Context) {
+		body, _ := io.ReadAll(c.Req.Body)
+		c.SetCookie(&http.Cookie{Name: "session", Value: "secret"})
+		c.JSON(http.StatusCreated, map[string]string{"echo": string(body)})
+	})
+
+	for i := 0; i < 3; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/users/42?full=1", strings.NewReader("hello world"))
+		req.Header.Set("Authorization", "Bearer secret")
+		an.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/missing", nil))
+
+	var samples []*TrafficSample
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if samples = store.Samples(); len(samples) == 3 {
+			break
+		}
+	}
+	if len(samples) != 3 {
+		t.Fatalf("%d samples, want 2 of the route and 1 unmatched", len(samples))
+	}
+
+	routes := map[string]int{}
+	for _, s := range samples {
+		routes[s.Method+" "+s.Route]++
+		if s.Route != "/users/:id" {
+			continue
+		}
+		if s.URL != "/users/42?full=1" || string(s.RequestBody) != "hello wo" || !s.RequestTruncated ||
+			s.Status != http.StatusCreated || string(s.ResponseBody) != `{"echo":` || !s.ResponseTruncated {
+			t.Errorf("sample = %+v", s)
+		}
+		if s.RequestHeader.Get("Authorization") != "[REDACTED]" || s.ResponseHeader.Get("Set-Cookie") != "[REDACTED]" {
+			t.Errorf("credentials recorded: %v %v", s.RequestHeader, s.ResponseHeader)
+		}
+	}
+	if routes["POST /users/:id"] != 2 || routes["GET "+UnmatchedRouteLabel] != 1 {
+		t.Errorf("sampled routes = %v", routes)
+	}
+
+	doc := an.OpenAPI("test", "1")
+	if missing := doc.Undocumented(samples); len(missing) != 0 {
+		t.Errorf("Undocumented() = %v", missing)
+	}
+	delete(doc.Paths, "/users/{id}")
+	if missing := doc.Undocumented(samples); len(missing) != 2 {
+		t.Errorf("Undocumented() found %d samples, want 2", len(missing))
+	}
+}
+
+func TestSampleCounts_Bounded(t *testing.T) {
+	counts := &sampleCounts{counts: make(map[string]int)}
+	now := time.Now()
+	for i := 0; i < 100; i++ {
+		counts.take(sampleMethod("X"+strings.Repeat("A", i))+" "+UnmatchedRouteLabel, 1000, now)
+	}
+	if len(counts.counts) != 1 {
+		t.Errorf("made-up methods kept %d counts, want 1", len(counts.counts))
+	}
+
+	counts.take("GET /a", 1, now)
+	if counts.take("GET /a", 1, now) {
+		t.Error("second sample of the hour was taken")
+	}
+	if !counts.take("GET /b", 1, now.Add(time.Hour)) {
+		t.Error("first sample of the next hour was refused")
+	}
+	if len(counts.counts) != 1 {
+		t.Errorf("counts of past hours kept: %v", counts.counts)
+	}
+}
@@ -0,0 +1,407 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrSessionNotFound is returned by a SessionStore for unknown or expired ids.
+var ErrSessionNotFound = errors.New("session not found")
+
+// sessionKey is the Context key of the current *Session.
+const sessionKey = "alsonow.session"
+
+// SessionSerializer encodes session values for storage. JSONSerializer and
+// GobSerializer are provided; others such as msgpack can be plugged in.
+type SessionSerializer interface {
+	Marshal(values map[string]any) ([]byte, error)
+	Unmarshal(data []byte, values *map[string]any) error
+}
+
+// JSONSerializer stores sessions as JSON. Numbers come back as float64.
+type JSONSerializer struct{}
+
+func (JSONSerializer) Marshal(values map[string]any) ([]byte, error) {
+	return json.Marshal(values)
+}
+
+func (JSONSerializer) Unmarshal(data []byte, values *map[string]any) error {
+	return json.Unmarshal(data, values)
+}
+
+// GobSerializer stores sessions with encoding/gob, which keeps Go types.
+// Custom types must be registered with gob.Register.
+type GobSerializer struct{}
+
+func (GobSerializer) Marshal(values map[string]any) ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(values)
+	return buf.Bytes(), err
+}
+
+func (GobSerializer) Unmarshal(data []byte, values *map[string]any) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(values)
+}
+
+// SessionStore persists serialized sessions.
+type SessionStore interface {
+	Load(ctx context.Context, id string) ([]byte, error)
+	Save(ctx context.Context, id string, data []byte, ttl time.Duration) error
+	Delete(ctx context.Context, id string) error
+}
+
+// SessionConfig configures Sessions.
+type SessionConfig struct {
+	// Store keeps the sessions, a MemorySessionStore when nil.
+	Store SessionStore
+	// Serializer encodes the values, JSONSerializer when nil.
+	Serializer SessionSerializer
+	// CookieName defaults to "alsonow_session".
+	CookieName string
+	// MaxAge is the idle lifetime of a session, 24h when zero.
+	MaxAge time.Duration
+	// Secure marks the cookie as HTTPS only.
+	Secure bool
+	// SameSite defaults to http.SameSiteLaxMode.
+	SameSite http.SameSite
+}
+
+// Session holds the values of a client session.
+type Session struct {
+	id       string
+	values   map[string]any
+	modified bool
+	destroy  bool
+	oldID    string
+
+	c   *Context
+	cfg *SessionConfig
+}
+
+// ID returns the session identifier.
+func (s *Session) ID() string {
+	return s.id
+}
+
+// Get returns the value stored under key.
+func (s *Session) Get(key string) (any, bool) {
+	v, ok := s.values[key]
+	return v, ok
+}
+
+// Set stores value under key.
+func (s *Session) Set(key string, value any) {
+	s.values[key] = value
+	s.modified = true
+}
+
+// Delete removes key from the session.
+func (s *Session) Delete(key string) {
+	delete(s.values, key)
+	s.modified = true
+}
+
+// Clear removes every value.
+func (s *Session) Clear() {
+	clear(s.values)
+	s.modified = true
+}
+
+// Regenerate moves the session to a new id, which must be done when the
+// privilege level changes (login, step-up) to prevent session fixation.
+// It must be called before the response headers are written.
+func (s *Session) Regenerate() {
+	if s.oldID == "" {
+		s.oldID = s.id
+	}
+	s.id = randomHex(32)
+	s.modified = true
+	s.setCookie()
+}
+
+// Destroy deletes the session and expires its cookie.
+func (s *Session) Destroy() {
+	s.destroy = true
+	s.dropCookie()
+	s.c.SetCookie(&http.Cookie{
+		Name:     s.cfg.CookieName,
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   s.cfg.Secure,
+		SameSite: s.cfg.SameSite,
+	})
+}
+
+func (s *Session) setCookie() {
+	s.dropCookie()
+	s.c.SetCookie(&http.Cookie{
+		Name:     s.cfg.CookieName,
+		Value:    s.id,
+		Path:     "/",
+		MaxAge:   int(s.cfg.MaxAge.Seconds()),
+		HttpOnly: true,
+		Secure:   s.cfg.Secure,
+		SameSite: s.cfg.SameSite,
+	})
+}
+
+// dropCookie removes a session cookie set earlier in this response.
+func (s *Session) dropCookie() {
+	h := s.c.Writer.Header()
+	cookies := h["Set-Cookie"][:0]
+	for _, v := range h["Set-Cookie"] {
+		if !strings.HasPrefix(v, s.cfg.CookieName+"=") {
+			cookies = append(cookies, v)
+		}
+	}
+	h["Set-Cookie"] = cookies
+}
+
+// Session returns the session of the request, or nil when the Sessions
+// middleware is not in use.
+func (c *Context) Session() *Session {
+	v, _ := c.Get(sessionKey)
+	s, _ := v.(*Session)
+	return s
+}
+
+// Sessions loads the client's session before the handlers run and saves it
+// when it was modified, as the response headers are written.
+func Sessions(cfg SessionConfig) HandlerFunc {
+	if cfg.Store == nil {
+		cfg.Store = NewMemorySessionStore(time.Minute)
+	}
+	if cfg.Serializer == nil {
+		cfg.Serializer = JSONSerializer{}
+	}
+	if cfg.CookieName == "" {
+		cfg.CookieName = "alsonow_session"
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = 24 * time.Hour
+	}
+	if cfg.SameSite == 0 {
+		cfg.SameSite = http.SameSiteLaxMode
+	}
+
+	return func(c *Context) {
+		s := &Session{values: make(map[string]any), c: c, cfg: &cfg}
+
+		if id, err := c.Cookie(cfg.CookieName); err == nil && id != "" {
+			data, err := cfg.Store.Load(c.Context(), id)
+			if err == nil {
+				if err := cfg.Serializer.Unmarshal(data, &s.values); err == nil {
+					s.id = id
+				} else {
+					s.values = make(map[string]any)
+				}
+			} else if !errors.Is(err, ErrSessionNotFound) {
+				c.Logf("[SESSION] load: %v", err)
+			}
+		}
+
+		if s.id == "" {
+			s.id = randomHex(32)
+		} else {
+			// Sliding expiration: refresh the store on every request.
+			s.modified = true
+		}
+		s.setCookie()
+
+		c.Set(sessionKey, s)
+
+		// The session is saved before the response headers go out, so the
+		// client never holds a cookie the store does not know yet, and
+		// again afterwards if it changed in between.
+		w := c.Writer
+		c.Writer = &sessionWriter{ResponseWriter: w, s: s}
+		defer func() { c.Writer = w }()
+
+		c.Next()
+		s.save()
+	}
+}
+
+// save writes the pending changes of the session to the store.
+func (s *Session) save() {
+	ctx := context.WithoutCancel(s.c.Context())
+	if s.oldID != "" {
+		_ = s.cfg.Store.Delete(ctx, s.oldID)
+		s.oldID = ""
+	}
+	if s.destroy {
+		_ = s.cfg.Store.Delete(ctx, s.id)
+		s.destroy, s.modified = false, false
+		return
+	}
+	if !s.modified {
+		return
+	}
+	s.modified = false
+
+	data, err := s.cfg.Serializer.Marshal(s.values)
+	if err != nil {
+		s.c.Logf("[SESSION] marshal: %v", err)
+		return
+	}
+	if err := s.cfg.Store.Save(ctx, s.id, data, s.cfg.MaxAge); err != nil {
+		s.c.Logf("[SESSION] save: %v", err)
+	}
+}
+
+// sessionWriter saves the session just before the response headers are
+// sent.
+type sessionWriter struct {
+	http.ResponseWriter
+	s     *Session
+	saved bool
+}
+
+func (w *sessionWriter) save() {
+	if !w.saved {
+		w.saved = true
+		w.s.save()
+	}
+}
+
+func (w *sessionWriter) WriteHeader(code int) {
+	if code < 100 || code >= 200 || code == http.StatusSwitchingProtocols {
+		w.save()
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *sessionWriter) Write(p []byte) (int, error) {
+	w.save()
+	return w.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher.
+func (w *sessionWriter) Flush() {
+	w.save()
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *sessionWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// MemorySessionStore keeps sessions in memory. Expired sessions are removed
+// by a background collector, which also compacts the underlying map after
+// large deletions since Go maps never shrink on their own.
+type MemorySessionStore struct {
+	mu       sync.RWMutex
+	sessions map[string]memorySession
+	peak     int
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type memorySession struct {
+	data    []byte
+	expires time.Time
+}
+
+// NewMemorySessionStore returns a store collecting expired sessions every
+// gcInterval. A zero interval disables the collector; call GC manually.
+func NewMemorySessionStore(gcInterval time.Duration) *MemorySessionStore {
+	s := &MemorySessionStore{
+		sessions: make(map[string]memorySession),
+		stop:     make(chan struct{}),
+	}
+	if gcInterval > 0 {
+		go s.collect(gcInterval)
+	}
+	return s
+}
+
+func (s *MemorySessionStore) Load(_ context.Context, id string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[id]
+	if !ok || time.Now().After(sess.expires) {
+		return nil, ErrSessionNotFound
+	}
+	return sess.data, nil
+}
+
+func (s *MemorySessionStore) Save(_ context.Context, id string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[id] = memorySession{data: data, expires: time.Now().Add(ttl)}
+	if len(s.sessions) > s.peak {
+		s.peak = len(s.sessions)
+	}
+	return nil
+}
+
+func (s *MemorySessionStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
+
+// Len returns the number of stored sessions, including expired ones not yet collected.
+func (s *MemorySessionStore) Len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessions)
+}
+
+// GC removes expired sessions and compacts the map when it holds less than
+// a quarter of its peak size.
+func (s *MemorySessionStore) GC() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, sess := range s.sessions {
+		if now.After(sess.expires) {
+			delete(s.sessions, id)
+		}
+	}
+
+	if s.peak > 1024 && len(s.sessions) < s.peak/4 {
+		compacted := make(map[string]memorySession, len(s.sessions))
+		for id, sess := range s.sessions {
+			compacted[id] = sess
+		}
+		s.sessions = compacted
+		s.peak = len(compacted)
+	}
+}
+
+// Close stops the background collector.
+func (s *MemorySessionStore) Close() {
+	s.stopOnce.Do(func() { close(s.stop) })
+}
+
+func (s *MemorySessionStore) collect(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.GC()
+		}
+	}
+}
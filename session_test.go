@@ -0,0 +1,166 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSessions(t *testing.T) {
+	store := NewMemorySessionStore(0)
+	an := New()
+	an.Use(Sessions(SessionConfig{Store: store, Serializer: GobSerializer{}}))
+	an.POST("/login", func(c *Context) {
+		c.Session().Regenerate()
+		c.Session().Set("user", 42)
+	})
+	an.GET("/me", func(c *Context) {
+		v, _ := c.Session().Get("user")
+		_, _ = fmt.Fprint(c.Writer, v)
+	})
+	an.POST("/logout", func(c *Context) {
+		c.Session().Destroy()
+	})
+
+	do := func(method, path string, cookie *http.Cookie) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/login", nil)
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("expected a single session cookie, got %v", w.Header()["Set-Cookie"])
+	}
+
+	if w = do(http.MethodGet, "/me", cookies[0]); w.Body.String() != "42" {
+		t.Errorf("/me = %q, want 42", w.Body.String())
+	}
+
+	do(http.MethodPost, "/logout", cookies[0])
+	if store.Len() != 0 {
+		t.Errorf("expected the session to be deleted, %d left", store.Len())
+	}
+	if w = do(http.MethodGet, "/me", cookies[0]); strings.Contains(w.Body.String(), "42") {
+		t.Errorf("session survived logout")
+	}
+}
+
+func TestSessions_SavedBeforeResponse(t *testing.T) {
+	release := make(chan struct{})
+	an := New()
+	an.Use(Sessions(SessionConfig{}))
+	an.POST("/login", func(c *Context) {
+		c.Session().Regenerate()
+		c.Session().Set("user", "ann")
+		c.SetHeader("Location", "/me")
+		c.Writer.WriteHeader(http.StatusSeeOther)
+		_ = c.Flush()
+		// The handler is still running when the client follows the redirect.
+		<-release
+	})
+	an.GET("/me", func(c *Context) {
+		v, _ := c.Session().Get("user")
+		_, _ = fmt.Fprint(c.Writer, v)
+	})
+	srv := httptest.NewServer(an)
+	defer srv.Close()
+	defer close(release)
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Post(srv.URL+"/login", "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusSeeOther || len(resp.Cookies()) != 1 {
+		t.Fatalf("login: %d %v", resp.StatusCode, resp.Header["Set-Cookie"])
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL+"/me", nil)
+	req.AddCookie(resp.Cookies()[0])
+	me, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer me.Body.Close()
+	if body, _ := io.ReadAll(me.Body); string(body) != "ann" {
+		t.Errorf("/me right after login = %q, want ann", body)
+	}
+}
+
+func TestMemorySessionStore_GC(t *testing.T) {
+	store := NewMemorySessionStore(0)
+	ctx := context.Background()
+
+	for i := 0; i < 2000; i++ {
+		_ = store.Save(ctx, fmt.Sprint(i), nil, -time.Second)
+	}
+	_ = store.Save(ctx, "live", []byte("x"), time.Hour)
+
+	store.GC()
+	if store.Len() != 1 {
+		t.Errorf("Len = %d after GC, want 1", store.Len())
+	}
+	if data, err := store.Load(ctx, "live"); err != nil || string(data) != "x" {
+		t.Errorf("Load(live) = %q, %v", data, err)
+	}
+}
+
+// benchmarkSessionValues builds a session of roughly 64KB.
+func benchmarkSessionValues() map[string]any {
+	values := make(map[string]any)
+	for i := 0; i < 500; i++ {
+		values[fmt.Sprintf("key-%d", i)] = strings.Repeat("v", 128)
+	}
+	return values
+}
+
+func benchmarkSerializer(b *testing.B, s SessionSerializer) {
+	values := benchmarkSessionValues()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		data, err := s.Marshal(values)
+		if err != nil {
+			b.Fatal(err)
+		}
+		out := make(map[string]any)
+		if err := s.Unmarshal(data, &out); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSessionSerializer_JSON(b *testing.B) { benchmarkSerializer(b, JSONSerializer{}) }
+func BenchmarkSessionSerializer_Gob(b *testing.B)  { benchmarkSerializer(b, GobSerializer{}) }
+
+func BenchmarkMemorySessionStore(b *testing.B) {
+	store := NewMemorySessionStore(0)
+	ctx := context.Background()
+	data := []byte(strings.Repeat("v", 4096))
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			id := fmt.Sprint(i % 1000)
+			_ = store.Save(ctx, id, data, time.Minute)
+			_, _ = store.Load(ctx, id)
+			i++
+		}
+	})
+}
@@ -0,0 +1,216 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// sitemapMeta is the route metadata set by Route.Sitemap.
+const sitemapMeta = "alsonow.sitemap"
+
+// defaultSEOMaxAge is how long sitemaps and robots.txt are cached by default.
+const defaultSEOMaxAge = time.Hour
+
+// SitemapURL is an entry of a sitemap.
+// https://www.sitemaps.org/protocol.html
+type SitemapURL struct {
+	// Loc is a path, resolved against SitemapConfig.BaseURL, or an
+	// absolute URL.
+	Loc     string
+	LastMod time.Time
+	// ChangeFreq is "always", "hourly", "daily", "weekly", "monthly",
+	// "yearly" or "never".
+	ChangeFreq string
+	// Priority is between 0 and 1, omitted when zero.
+	Priority float64
+}
+
+// Sitemap lists the route, a public page, in the sitemaps served by
+// AlsoNow.Sitemap, with the lastmod, changefreq and priority of entry, if
+// any. Routes with parameters cannot be listed; their URLs, such as those of
+// blog posts, are returned by SitemapConfig.URLs instead.
+func (r *Route) Sitemap(entry ...SitemapURL) *Route {
+	if strings.ContainsAny(r.Path, ":*") {
+		panic(fmt.Sprintf("alsonow: route '%s' has parameters and cannot be listed in a sitemap", r.Path))
+	}
+	var e SitemapURL
+	if len(entry) > 0 {
+		e = entry[0]
+	}
+	e.Loc = r.Path
+	return r.SetMeta(sitemapMeta, e)
+}
+
+// SitemapConfig configures AlsoNow.Sitemap.
+type SitemapConfig struct {
+	// BaseURL is prepended to paths, such as "https://example.com". The
+	// scheme and host of the request are used when empty.
+	BaseURL string
+	// URLs returns the URLs listed next to the routes flagged with
+	// Route.Sitemap, e.g. from the database.
+	URLs func(c *Context) ([]SitemapURL, error)
+	// MaxAge is the max-age of the Cache-Control header, an hour when zero.
+	MaxAge time.Duration
+}
+
+// Sitemap serves the sitemap.xml of the routes flagged with Route.Sitemap
+// and of the URLs of cfg at path, usually "/sitemap.xml":
+//
+//	an.GET("/about", about).Sitemap(alsonow.SitemapURL{ChangeFreq: "monthly"})
+//	an.Sitemap("/sitemap.xml", alsonow.SitemapConfig{BaseURL: "https://example.com"})
+//
+// It is served with an ETag and a Cache-Control header.
+func (an *AlsoNow) Sitemap(path string, cfg SitemapConfig) *Route {
+	return an.GET(path, func(c *Context) {
+		var urls []SitemapURL
+		for _, route := range an.router().routes {
+			if v, ok := route.Meta(sitemapMeta); ok && route.Method == http.MethodGet {
+				urls = append(urls, v.(SitemapURL))
+			}
+		}
+		sort.Slice(urls, func(i, j int) bool { return urls[i].Loc < urls[j].Loc })
+		if cfg.URLs != nil {
+			more, err := cfg.URLs(c)
+			if err != nil {
+				c.InternalServerError(err)
+				return
+			}
+			urls = append(urls, more...)
+		}
+
+		base := strings.TrimSuffix(cfg.BaseURL, "/")
+		if base == "" {
+			base = forwardedScheme(c) + "://" + c.Host()
+		}
+		body, err := sitemapXML(base, urls)
+		if err != nil {
+			c.InternalServerError(err)
+			return
+		}
+		serveSEOFile(c, "application/xml; charset=utf-8", cfg.MaxAge, body)
+	})
+}
+
+func sitemapXML(base string, urls []SitemapURL) ([]byte, error) {
+	type xmlURL struct {
+		Loc        string `xml:"loc"`
+		LastMod    string `xml:"lastmod,omitempty"`
+		ChangeFreq string `xml:"changefreq,omitempty"`
+		Priority   string `xml:"priority,omitempty"`
+	}
+	set := struct {
+		XMLName xml.Name `xml:"urlset"`
+		XMLNS   string   `xml:"xmlns,attr"`
+		URLs    []xmlURL `xml:"url"`
+	}{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+
+	for _, u := range urls {
+		x := xmlURL{Loc: u.Loc, ChangeFreq: u.ChangeFreq}
+		if !strings.Contains(u.Loc, "://") {
+			x.Loc = base + normalizePath(u.Loc)
+		}
+		if !u.LastMod.IsZero() {
+			x.LastMod = u.LastMod.UTC().Format(time.RFC3339)
+		}
+		if u.Priority > 0 {
+			x.Priority = strconv.FormatFloat(u.Priority, 'f', 1, 64)
+		}
+		set.URLs = append(set.URLs, x)
+	}
+
+	out, err := xml.Marshal(set)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), out...), nil
+}
+
+// RobotsRule is a group of robots.txt rules for the crawlers of a user agent.
+type RobotsRule struct {
+	// UserAgent is "*" when empty.
+	UserAgent string
+	Allow     []string
+	Disallow  []string
+}
+
+// RobotsConfig configures AlsoNow.Robots.
+type RobotsConfig struct {
+	// Rules allow everything to every crawler when empty.
+	Rules []RobotsRule
+	// Sitemaps are the URLs of the sitemaps, paths being resolved against
+	// the scheme and host of the request.
+	Sitemaps []string
+	// MaxAge is the max-age of the Cache-Control header, an hour when zero.
+	MaxAge time.Duration
+}
+
+// Robots serves /robots.txt with the rules of cfg:
+//
+//	an.Robots(alsonow.RobotsConfig{
+//		Rules:    []alsonow.RobotsRule{{Disallow: []string{"/admin/"}}},
+//		Sitemaps: []string{"/sitemap.xml"},
+//	})
+func (an *AlsoNow) Robots(cfg RobotsConfig) *Route {
+	rules := cfg.Rules
+	if len(rules) == 0 {
+		rules = []RobotsRule{{}}
+	}
+
+	return an.GET("/robots.txt", func(c *Context) {
+		var b bytes.Buffer
+		for i, rule := range rules {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			agent := rule.UserAgent
+			if agent == "" {
+				agent = "*"
+			}
+			b.WriteString("User-agent: " + agent + "\n")
+			for _, p := range rule.Allow {
+				b.WriteString("Allow: " + p + "\n")
+			}
+			for _, p := range rule.Disallow {
+				b.WriteString("Disallow: " + p + "\n")
+			}
+			if len(rule.Allow) == 0 && len(rule.Disallow) == 0 {
+				b.WriteString("Disallow:\n")
+			}
+		}
+		for i, s := range cfg.Sitemaps {
+			if i == 0 {
+				b.WriteString("\n")
+			}
+			if !strings.Contains(s, "://") {
+				s = forwardedScheme(c) + "://" + c.Host() + normalizePath(s)
+			}
+			b.WriteString("Sitemap: " + s + "\n")
+		}
+		serveSEOFile(c, "text/plain; charset=utf-8", cfg.MaxAge, b.Bytes())
+	})
+}
+
+// serveSEOFile writes body with an ETag and a Cache-Control header, answering
+// conditional requests with 304 Not Modified.
+func serveSEOFile(c *Context, ctype string, maxAge time.Duration, body []byte) {
+	if maxAge <= 0 {
+		maxAge = defaultSEOMaxAge
+	}
+	sum := sha256.Sum256(body)
+	c.SetHeader("Content-Type", ctype)
+	c.SetHeader("Cache-Control", "public, max-age="+strconv.Itoa(int(maxAge/time.Second)))
+	c.SetHeader("ETag", `"`+hex.EncodeToString(sum[:8])+`"`)
+	http.ServeContent(c.Writer, c.Req, "", time.Time{}, bytes.NewReader(body))
+}
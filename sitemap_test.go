@@ -0,0 +1,74 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAlsoNow_Sitemap(t *testing.T) {
+	an := New()
+	an.GET("/", func(c *Context) {}).Sitemap(SitemapURL{Priority: 1})
+	an.GET("/about", func(c *Context) {}).Sitemap(SitemapURL{ChangeFreq: "monthly"})
+	an.GET("/admin", func(c *Context) {})
+	an.GET("/posts/:slug", func(c *Context) {})
+	an.Sitemap("/sitemap.xml", SitemapConfig{
+		BaseURL: "https://example.com/",
+		URLs: func(c *Context) ([]SitemapURL, error) {
+			return []SitemapURL{{Loc: "/posts/hello", LastMod: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)}}, nil
+		},
+	})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+	want := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>https://example.com/</loc><priority>1.0</priority></url>` +
+		`<url><loc>https://example.com/about</loc><changefreq>monthly</changefreq></url>` +
+		`<url><loc>https://example.com/posts/hello</loc><lastmod>2025-03-01T12:00:00Z</lastmod></url>` +
+		`</urlset>`
+	if w.Body.String() != want {
+		t.Errorf("sitemap =\n%s\nwant\n%s", w.Body.String(), want)
+	}
+	if w.Header().Get("Cache-Control") != "public, max-age=3600" || w.Header().Get("Content-Type") != "application/xml; charset=utf-8" {
+		t.Errorf("headers = %v", w.Header())
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusNotModified {
+		t.Errorf("revalidation status = %d", w.Code)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("listing a route with parameters should panic")
+		}
+	}()
+	an.GET("/users/:id", func(c *Context) {}).Sitemap()
+}
+
+func TestAlsoNow_Robots(t *testing.T) {
+	an := New()
+	an.Robots(RobotsConfig{
+		Rules: []RobotsRule{
+			{Disallow: []string{"/admin/"}},
+			{UserAgent: "GPTBot", Disallow: []string{"/"}},
+		},
+		Sitemaps: []string{"/sitemap.xml"},
+	})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "http://example.com/robots.txt", nil))
+	want := "User-agent: *\nDisallow: /admin/\n\nUser-agent: GPTBot\nDisallow: /\n\nSitemap: http://example.com/sitemap.xml\n"
+	if w.Body.String() != want {
+		t.Errorf("robots.txt =\n%s\nwant\n%s", w.Body.String(), want)
+	}
+}
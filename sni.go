@@ -0,0 +1,94 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// SNICertificates selects the certificate of a TLS handshake from the server
+// name sent by the client, for multi-domain deployments where each customer
+// brings their own domain. Pass its GetCertificate to RunTLSWithCertificate.
+type SNICertificates struct {
+	// Lookup is consulted for names without a static certificate, e.g. to
+	// load customer certificates from a database. It returns nil, nil when
+	// it has no certificate for name.
+	Lookup func(ctx context.Context, name string) (*tls.Certificate, error)
+	// Default is served when nothing else matches, or to clients without SNI.
+	Default *tls.Certificate
+
+	mu    sync.RWMutex
+	certs map[string]*tls.Certificate
+}
+
+// NewSNICertificates returns an empty SNICertificates.
+func NewSNICertificates() *SNICertificates {
+	return &SNICertificates{certs: make(map[string]*tls.Certificate)}
+}
+
+// Add registers cert for name, which may be a wildcard such as "*.example.com".
+func (s *SNICertificates) Add(name string, cert *tls.Certificate) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.certs == nil {
+		s.certs = make(map[string]*tls.Certificate)
+	}
+	s.certs[strings.ToLower(name)] = cert
+}
+
+// Remove unregisters the certificate of name.
+func (s *SNICertificates) Remove(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.certs, strings.ToLower(name))
+}
+
+// GetCertificate implements tls.Config.GetCertificate.
+func (s *SNICertificates) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+
+	if name != "" {
+		if cert := s.match(name); cert != nil {
+			return cert, nil
+		}
+
+		if s.Lookup != nil {
+			ctx := hello.Context()
+			if ctx == nil {
+				ctx = context.Background()
+			}
+			cert, err := s.Lookup(ctx, name)
+			if err != nil {
+				return nil, err
+			}
+			if cert != nil {
+				return cert, nil
+			}
+		}
+	}
+
+	if s.Default != nil {
+		return s.Default, nil
+	}
+	return nil, fmt.Errorf("tls: no certificate for %q", name)
+}
+
+func (s *SNICertificates) match(name string) *tls.Certificate {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if cert, ok := s.certs[name]; ok {
+		return cert
+	}
+	if _, rest, ok := strings.Cut(name, "."); ok {
+		return s.certs["*."+rest]
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/tls"
+	"testing"
+)
+
+func TestSNICertificates_GetCertificate(t *testing.T) {
+	exact, wildcard, dynamic, fallback := &tls.Certificate{}, &tls.Certificate{}, &tls.Certificate{}, &tls.Certificate{}
+
+	s := NewSNICertificates()
+	s.Add("shop.example.com", exact)
+	s.Add("*.example.com", wildcard)
+	s.Default = fallback
+	s.Lookup = func(_ context.Context, name string) (*tls.Certificate, error) {
+		if name == "customer.io" {
+			return dynamic, nil
+		}
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		want *tls.Certificate
+	}{
+		{"shop.example.com", exact},
+		{"Blog.Example.com", wildcard},
+		{"customer.io", dynamic},
+		{"unknown.org", fallback},
+		{"", fallback},
+	}
+
+	for _, tt := range tests {
+		got, err := s.GetCertificate(&tls.ClientHelloInfo{ServerName: tt.name})
+		if err != nil || got != tt.want {
+			t.Errorf("GetCertificate(%q) returned the wrong certificate (err %v)", tt.name, err)
+		}
+	}
+}
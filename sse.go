@@ -0,0 +1,173 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SSEvent is a server-sent event.
+// https://html.spec.whatwg.org/multipage/server-sent-events.html
+type SSEvent struct {
+	ID    string
+	Event string
+	Data  string
+	// Retry tells the client how long to wait before reconnecting.
+	Retry time.Duration
+}
+
+// StartSSE sends the headers of an event stream.
+func (c *Context) StartSSE() {
+	h := c.Writer.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	_ = c.Flush()
+}
+
+// SSEvent writes ev to the stream started with StartSSE and flushes it.
+func (c *Context) SSEvent(ev SSEvent) error {
+	var b strings.Builder
+	if ev.ID != "" {
+		fmt.Fprintf(&b, "id: %s\n", ev.ID)
+	}
+	if ev.Event != "" {
+		fmt.Fprintf(&b, "event: %s\n", ev.Event)
+	}
+	if ev.Retry > 0 {
+		fmt.Fprintf(&b, "retry: %d\n", ev.Retry.Milliseconds())
+	}
+	for _, line := range strings.Split(ev.Data, "\n") {
+		fmt.Fprintf(&b, "data: %s\n", line)
+	}
+	b.WriteByte('\n')
+
+	if _, err := c.Writer.Write([]byte(b.String())); err != nil {
+		return err
+	}
+	return c.Flush()
+}
+
+// SSEBroker fans published events out to every connected client. Register
+// it with AlsoNow.RegisterShutdownNotifier so clients are asked to reconnect
+// when the instance shuts down.
+type SSEBroker struct {
+	// ShutdownEvent is sent to clients on shutdown, a "reconnect" event with
+	// a one second retry when zero.
+	ShutdownEvent SSEvent
+	// Buffer is the number of events queued per client before events are
+	// dropped for that client, 16 when zero.
+	Buffer int
+
+	mu      sync.Mutex
+	clients map[chan SSEvent]struct{}
+	closed  bool
+}
+
+// NewSSEBroker returns an empty broker.
+func NewSSEBroker() *SSEBroker {
+	return &SSEBroker{clients: make(map[chan SSEvent]struct{})}
+}
+
+// Handler streams the broker's events to the client until it disconnects
+// or the broker shuts down.
+func (b *SSEBroker) Handler() HandlerFunc {
+	return func(c *Context) {
+		size := b.Buffer
+		if size == 0 {
+			size = 16
+		}
+		events := make(chan SSEvent, size)
+
+		b.mu.Lock()
+		if b.closed {
+			b.mu.Unlock()
+			c.Error(http.StatusServiceUnavailable, "")
+			return
+		}
+		b.clients[events] = struct{}{}
+		b.mu.Unlock()
+
+		defer b.remove(events)
+
+		c.StartSSE()
+		for {
+			select {
+			case <-c.Context().Done():
+				return
+			case ev, ok := <-events:
+				if !ok {
+					return
+				}
+				if err := c.SSEvent(ev); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Publish sends ev to every client. Clients whose buffer is full miss it.
+func (b *SSEBroker) Publish(ev SSEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.clients {
+		select {
+		case ch <- ev:
+		default:
+		}
+	}
+}
+
+// Clients returns the number of connected clients.
+func (b *SSEBroker) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// NotifyShutdown implements ShutdownNotifier: it sends the shutdown event to
+// every client and ends their streams. Clients whose buffer is full miss
+// their oldest queued event instead of the shutdown one.
+func (b *SSEBroker) NotifyShutdown(context.Context) {
+	ev := b.ShutdownEvent
+	if ev.Event == "" && ev.Data == "" {
+		ev = SSEvent{Event: "reconnect", Data: "server shutting down", Retry: time.Second}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for ch := range b.clients {
+		select {
+		case ch <- ev:
+		default:
+			// The client is slow: drop its oldest queued event so that it
+			// still gets the shutdown one. Only Publish sends, under mu, so
+			// the slot freed stays free.
+			select {
+			case <-ch:
+			default:
+			}
+			ch <- ev
+		}
+		close(ch)
+		delete(b.clients, ch)
+	}
+}
+
+func (b *SSEBroker) remove(ch chan SSEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.clients, ch)
+}
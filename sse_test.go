@@ -0,0 +1,68 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSSEBroker(t *testing.T) {
+	broker := NewSSEBroker()
+	an := New()
+	an.GET("/events", broker.Handler())
+
+	srv := httptest.NewServer(an)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/events")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	for broker.Clients() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	broker.Publish(SSEvent{ID: "1", Event: "greeting", Data: "hello\nworld"})
+	broker.NotifyShutdown(context.Background())
+
+	body, _ := io.ReadAll(resp.Body)
+	want := "id: 1\nevent: greeting\ndata: hello\ndata: world\n\n" +
+		"event: reconnect\nretry: 1000\ndata: server shutting down\n\n"
+	if string(body) != want {
+		t.Errorf("stream = %q, want %q", body, want)
+	}
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/events", nil))
+	if w.Code != http.StatusServiceUnavailable || !strings.Contains(w.Body.String(), "Unavailable") {
+		t.Errorf("after shutdown: status = %d", w.Code)
+	}
+}
+
+func TestSSEBroker_ShutdownFullBuffer(t *testing.T) {
+	broker := NewSSEBroker()
+	events := make(chan SSEvent, 2)
+	broker.clients[events] = struct{}{}
+	for _, id := range []string{"1", "2", "3"} {
+		broker.Publish(SSEvent{ID: id, Data: "tick"})
+	}
+
+	broker.NotifyShutdown(context.Background())
+
+	var got []string
+	for ev := range events {
+		got = append(got, ev.ID+ev.Event)
+	}
+	if strings.Join(got, ",") != "2,reconnect" {
+		t.Errorf("slow client got %v, want the newest event and the reconnect one", got)
+	}
+}
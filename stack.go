@@ -0,0 +1,111 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// MiddlewareStack is a named, reusable sequence of middlewares.
+type MiddlewareStack struct {
+	name     string
+	handlers []HandlerFunc
+}
+
+var (
+	stacksMu sync.RWMutex
+	stacks   = make(map[string]*MiddlewareStack)
+)
+
+// Stack defines a named middleware stack, replacing any previous stack with
+// the same name, so that large middleware configurations are written once:
+//
+//	alsonow.Stack("api", CORS(), Auth(), RateLimit())
+//	api := an.Group("/api").UseStack("api")
+func Stack(name string, handlers ...HandlerFunc) *MiddlewareStack {
+	s := &MiddlewareStack{name: name, handlers: handlers}
+
+	stacksMu.Lock()
+	stacks[name] = s
+	stacksMu.Unlock()
+	return s
+}
+
+// Name returns the name of the stack.
+func (s *MiddlewareStack) Name() string {
+	return s.name
+}
+
+// Handlers returns the middlewares of the stack.
+func (s *MiddlewareStack) Handlers() []HandlerFunc {
+	return append([]HandlerFunc(nil), s.handlers...)
+}
+
+// HandlerNames returns the function names of the stack's middlewares.
+func (s *MiddlewareStack) HandlerNames() []string {
+	names := make([]string, len(s.handlers))
+	for i, h := range s.handlers {
+		names[i] = handlerName(h)
+	}
+	return names
+}
+
+// Stacked returns the middlewares of the named stacks in order, for use with
+// a single route: r.GET("/x", append(alsonow.Stacked("api"), h)...).
+// It panics if a stack is not defined.
+func Stacked(names ...string) []HandlerFunc {
+	stacksMu.RLock()
+	defer stacksMu.RUnlock()
+
+	var handlers []HandlerFunc
+	for _, name := range names {
+		s, ok := stacks[name]
+		if !ok {
+			panic(fmt.Sprintf("middleware stack %q is not defined", name))
+		}
+		handlers = append(handlers, s.handlers...)
+	}
+	return handlers
+}
+
+// LookupStack returns the stack defined with name.
+func LookupStack(name string) (*MiddlewareStack, bool) {
+	stacksMu.RLock()
+	defer stacksMu.RUnlock()
+
+	s, ok := stacks[name]
+	return s, ok
+}
+
+// StackNames returns the names of all defined stacks, sorted.
+func StackNames() []string {
+	stacksMu.RLock()
+	defer stacksMu.RUnlock()
+
+	names := make([]string, 0, len(stacks))
+	for name := range stacks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RouteStacks returns the names of the stacks applied with UseStack to the
+// route registered for method and path.
+func (an *AlsoNow) RouteStacks(method, path string) []string {
+	return an.router().routeStacks[method+" "+normalizePath(path)]
+}
+
+// handlerName returns the name of the function behind h.
+func handlerName(h HandlerFunc) string {
+	if fn := runtime.FuncForPC(reflect.ValueOf(h).Pointer()); fn != nil {
+		return fn.Name()
+	}
+	return "unknown"
+}
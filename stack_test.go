@@ -0,0 +1,52 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	var trace []string
+	mark := func(name string) HandlerFunc {
+		return func(c *Context) {
+			trace = append(trace, name)
+			c.Next()
+		}
+	}
+
+	Stack("test-web", mark("session"))
+	Stack("test-api", mark("cors"), mark("auth"))
+
+	an := New()
+	api := an.Group("/api").UseStack("test-api")
+	api.GET("/users", mark("handler"))
+	an.GET("/page", append(Stacked("test-web"), mark("handler"))...)
+
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/users", nil))
+	if got := strings.Join(trace, ","); got != "cors,auth,handler" {
+		t.Errorf("ran %q", got)
+	}
+
+	if got := an.RouteStacks(http.MethodGet, "/api/users"); !reflect.DeepEqual(got, []string{"test-api"}) {
+		t.Errorf("RouteStacks = %v", got)
+	}
+
+	s, ok := LookupStack("test-api")
+	if !ok || len(s.HandlerNames()) != 2 {
+		t.Errorf("LookupStack returned %v, %v", s, ok)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for undefined stack")
+		}
+	}()
+	Stacked("missing")
+}
@@ -0,0 +1,300 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// immutableCacheControl is sent for the versioned URLs of AssetURL, whose
+// content never changes.
+const immutableCacheControl = "public, max-age=31536000, immutable"
+
+// Static serves the files under dir for every path below prefix. Directory
+// listings are disabled; a directory is only served through its index.html.
+//
+// Files are served with a Last-Modified header and an ETag hashing their
+// content, so that conditional requests get a 304 Not Modified. The ETags of
+// embedded files, which have no modification time, are computed once.
+// Requests for the versioned URLs returned by AssetURL are also told to
+// cache the file for a year. Pre-compressed siblings, "app.js.br" and
+// "app.js.gz", are served to the clients accepting their encoding.
+func (r *routerImpl) Static(prefix, dir string) {
+	serveStatic(r.GET, r.HEAD, prefix, http.Dir(dir))
+}
+
+// StaticFS serves the files of fsys, such as an embed.FS, for every path
+// below prefix. Use fs.Sub to serve a subdirectory of an embedded tree.
+func (r *routerImpl) StaticFS(prefix string, fsys fs.FS) {
+	serveStatic(r.GET, r.HEAD, prefix, http.FS(fsys))
+}
+
+// StaticFile serves a single file at path.
+func (r *routerImpl) StaticFile(path, file string) {
+	h := staticFileHandler(file)
+	r.GET(path, h)
+	r.HEAD(path, h)
+}
+
+// Static serves the files under dir for every path below prefix in the group.
+func (g *Group) Static(prefix, dir string) {
+	serveStatic(g.GET, g.HEAD, prefix, http.Dir(dir))
+}
+
+// StaticFS serves the files of fsys for every path below prefix in the group.
+func (g *Group) StaticFS(prefix string, fsys fs.FS) {
+	serveStatic(g.GET, g.HEAD, prefix, http.FS(fsys))
+}
+
+// StaticFile serves a single file at path in the group.
+func (g *Group) StaticFile(path, file string) {
+	h := staticFileHandler(file)
+	g.GET(path, h)
+	g.HEAD(path, h)
+}
+
+func serveStatic(get, head func(string, ...HandlerFunc) *Route, prefix string, fsys http.FileSystem) {
+	pattern := strings.TrimSuffix(normalizePath(prefix), "/") + "/*filepath"
+	stats := newStatCache(fsys, staticStatTTL)
+	etags := &staticETags{fsys: stats}
+	h := staticHandler(stats, etags)
+	route := get(pattern, h)
+	head(pattern, h)
+
+	r := route.router
+	r.assets = append(r.assets, staticAssets{prefix: strings.TrimSuffix(route.Path, "*filepath"), etags: etags, stats: stats})
+}
+
+// staticHandler serves the file named by the "filepath" parameter.
+func staticHandler(fsys http.FileSystem, etags *staticETags) HandlerFunc {
+	fileServer := http.FileServer(noListingFS{fsys})
+
+	return func(c *Context) {
+		name := "/" + c.Param("filepath")
+		// The router drops trailing slashes, but http.FileServer needs them
+		// to tell a directory request from a file one.
+		if strings.HasSuffix(c.Req.URL.Path, "/") && name != "/" {
+			name += "/"
+		}
+		setStaticCaching(c, etags, name)
+		if alt := precompressed(c, fsys, name); alt != "" {
+			name = alt
+			c.SetHeader("ETag", etags.etag(alt))
+		}
+
+		req := c.Req.Clone(c.Context())
+		req.URL.Path = name
+		req.URL.RawPath = ""
+		fileServer.ServeHTTP(c.Writer, req)
+	}
+}
+
+func staticFileHandler(file string) HandlerFunc {
+	etags := &staticETags{fsys: http.Dir(filepath.Dir(file))}
+	name := "/" + filepath.Base(file)
+
+	return func(c *Context) {
+		setStaticCaching(c, etags, name)
+		if alt := precompressed(c, etags.fsys, name); alt != "" {
+			c.SetHeader("ETag", etags.etag(alt))
+			http.ServeFile(c.Writer, c.Req, file+strings.TrimPrefix(alt, name))
+			return
+		}
+		http.ServeFile(c.Writer, c.Req, file)
+	}
+}
+
+// setStaticCaching sets the ETag of the file name, which http.ServeContent
+// compares with the conditional headers of the request, and the
+// Cache-Control of versioned URLs.
+func setStaticCaching(c *Context, etags *staticETags, name string) {
+	if strings.HasSuffix(name, "/") {
+		name += "index.html"
+	}
+	etag := etags.etag(name)
+	if etag == "" {
+		return
+	}
+	c.SetHeader("ETag", etag)
+	if v := c.QueryParam("v"); v != "" && v == strings.Trim(etag, `"`) {
+		c.SetHeader("Cache-Control", immutableCacheControl)
+	}
+}
+
+// precompressedEncodings are the encodings of the pre-compressed siblings
+// served in place of static files, by order of preference, with the
+// extension of their files.
+var precompressedEncodings = []struct{ encoding, ext string }{
+	{"br", ".br"},
+	{"gzip", ".gz"},
+}
+
+// precompressed returns the name of the sibling of the file name compressed
+// with an encoding accepted by the request, such as "app.js.br" built by
+// the frontend tooling, and sets the headers to serve it. It returns "" when
+// there is none, or the type of the file is unknown.
+func precompressed(c *Context, fsys http.FileSystem, name string) string {
+	if strings.HasSuffix(name, "/") {
+		name += "index.html"
+	}
+	ctype := mime.TypeByExtension(path.Ext(name))
+	if ctype == "" {
+		return ""
+	}
+
+	accept, found, vary := c.Header("Accept-Encoding"), "", false
+	for _, e := range precompressedEncodings {
+		if !isRegularFile(fsys, name+e.ext) {
+			continue
+		}
+		vary = true
+		if acceptsEncoding(accept, e.encoding) {
+			c.SetHeader("Content-Encoding", e.encoding)
+			c.SetHeader("Content-Type", ctype)
+			found = name + e.ext
+			break
+		}
+	}
+	// Caches must key the response on Accept-Encoding as soon as a
+	// compressed variant exists, whether this client accepts it or not.
+	if vary {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+	}
+	return found
+}
+
+// isRegularFile reports whether name exists in fsys and is not a directory.
+func isRegularFile(fsys http.FileSystem, name string) bool {
+	info, err := statFS(fsys, name)
+	return err == nil && !info.IsDir()
+}
+
+// acceptsEncoding reports whether the Accept-Encoding header accepts
+// encoding, explicitly or with "*", with a non-zero quality.
+func acceptsEncoding(header, encoding string) bool {
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(name, encoding) && name != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if v, err := strconv.ParseFloat(q, 64); err != nil || v == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// staticAssets are the files served below prefix, for AssetURL.
+type staticAssets struct {
+	prefix string
+	etags  *staticETags
+	stats  *statCache
+}
+
+// AssetURL returns path, the URL of a file served by Static or StaticFS,
+// with a version derived from the content of the file, such as
+// "/assets/app.js?v=3f2a1b9c0d4e5f60". Browsers can cache versioned URLs
+// forever, and fetch the file again when it changes and its URL with it.
+// path is returned as is when no static file matches it. AssetURL can be
+// added to the FuncMap of templates:
+//
+//	template.FuncMap{"asset": an.AssetURL}
+func (an *AlsoNow) AssetURL(path string) string {
+	var match *staticAssets
+	for i, a := range an.router().assets {
+		if strings.HasPrefix(path, a.prefix) && (match == nil || len(a.prefix) > len(match.prefix)) {
+			match = &an.router().assets[i]
+		}
+	}
+	if match == nil {
+		return path
+	}
+	etag := match.etags.etag("/" + strings.TrimPrefix(path, match.prefix))
+	if etag == "" {
+		return path
+	}
+	return path + "?v=" + strings.Trim(etag, `"`)
+}
+
+// staticETags computes the ETags of the files of a file system, caching them
+// until the files change.
+type staticETags struct {
+	fsys  http.FileSystem
+	cache sync.Map // name -> staticETag
+}
+
+type staticETag struct {
+	modTime time.Time
+	size    int64
+	etag    string
+}
+
+// etag returns the ETag of the file name, a hash of its content, or "" for
+// missing files and directories.
+func (e *staticETags) etag(name string) string {
+	info, err := statFS(e.fsys, name)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+	if v, ok := e.cache.Load(name); ok {
+		if t := v.(staticETag); t.modTime.Equal(info.ModTime()) && t.size == info.Size() {
+			return t.etag
+		}
+	}
+
+	f, err := e.fsys.Open(name)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	etag := `"` + hex.EncodeToString(h.Sum(nil)[:8]) + `"`
+	e.cache.Store(name, staticETag{modTime: info.ModTime(), size: info.Size(), etag: etag})
+	return etag
+}
+
+// noListingFS hides directories without an index.html, so http.FileServer
+// answers 404 instead of listing their content.
+type noListingFS struct {
+	http.FileSystem
+}
+
+func (fsys noListingFS) Open(name string) (http.File, error) {
+	f, err := fsys.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	if info.IsDir() {
+		if !isRegularFile(fsys.FileSystem, strings.TrimSuffix(name, "/")+"/index.html") {
+			_ = f.Close()
+			return nil, os.ErrNotExist
+		}
+	}
+	return f, nil
+}
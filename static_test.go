@@ -0,0 +1,297 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestRouter_Static(t *testing.T) {
+	dir := t.TempDir()
+	_ = os.MkdirAll(filepath.Join(dir, "css"), 0o755)
+	_ = os.MkdirAll(filepath.Join(dir, "docs"), 0o755)
+	_ = os.WriteFile(filepath.Join(dir, "css", "app.css"), []byte("body{}"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "docs", "index.html"), []byte("docs"), 0o644)
+	_ = os.WriteFile(filepath.Join(dir, "robots.txt"), []byte("robots"), 0o644)
+
+	var hits int
+	an := New()
+	an.Use(func(c *Context) { hits++; c.Next() })
+	an.Static("/assets", dir)
+	an.Group("/v1").StaticFile("/robots.txt", filepath.Join(dir, "robots.txt"))
+
+	tests := []struct {
+		path string
+		code int
+		body string
+	}{
+		{"/assets/css/app.css", http.StatusOK, "body{}"},
+		{"/assets/docs/", http.StatusOK, "docs"},
+		{"/assets/css/", http.StatusNotFound, ""},
+		{"/assets/missing.js", http.StatusNotFound, ""},
+		{"/v1/robots.txt", http.StatusOK, "robots"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code || (tt.body != "" && w.Body.String() != tt.body) {
+			t.Errorf("%s: %d %q, want %d %q", tt.path, w.Code, w.Body.String(), tt.code, tt.body)
+		}
+	}
+	if hits != len(tests) {
+		t.Errorf("middleware ran %d times, want %d", hits, len(tests))
+	}
+}
+
+func TestRouter_StaticFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"index.html":    {Data: []byte("<html>home</html>")},
+		"js/app.js":     {Data: []byte("console.log(1)")},
+		"img/empty.txt": {Data: []byte("x")},
+	}
+
+	an := New()
+	an.StaticFS("/", fsys)
+
+	tests := []struct {
+		path  string
+		code  int
+		ctype string
+	}{
+		{"/", http.StatusOK, "text/html; charset=utf-8"},
+		{"/js/app.js", http.StatusOK, "text/javascript; charset=utf-8"},
+		{"/img/", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.code || w.Header().Get("Content-Type") != tt.ctype && tt.ctype != "" {
+			t.Errorf("%s: %d %q, want %d %q", tt.path, w.Code, w.Header().Get("Content-Type"), tt.code, tt.ctype)
+		}
+	}
+}
+
+func TestRouter_StaticConditional(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.css")
+	_ = os.WriteFile(file, []byte("body{}"), 0o644)
+
+	an := New()
+	an.StaticFS("/js", fstest.MapFS{"app.js": {Data: []byte("console.log(1)")}})
+	an.Static("/css", dir)
+
+	get := func(path, etag string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	w := get("/js/app.js", "")
+	etag := w.Header().Get("ETag")
+	if w.Code != http.StatusOK || etag == "" || w.Header().Get("Cache-Control") != "" {
+		t.Fatalf("embedded: %d, headers %v", w.Code, w.Header())
+	}
+	if w = get("/js/app.js", etag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("embedded revalidation: %d, %d bytes", w.Code, w.Body.Len())
+	}
+
+	u := an.AssetURL("/js/app.js")
+	if u != "/js/app.js?v="+etag[1:len(etag)-1] {
+		t.Errorf("AssetURL = %q, ETag %s", u, etag)
+	}
+	if w = get(u, ""); w.Header().Get("Cache-Control") != immutableCacheControl {
+		t.Errorf("versioned Cache-Control = %q", w.Header().Get("Cache-Control"))
+	}
+	if u := an.AssetURL("/js/missing.js"); u != "/js/missing.js" {
+		t.Errorf("AssetURL of a missing file = %q", u)
+	}
+
+	w = get("/css/app.css", "")
+	etag = w.Header().Get("ETag")
+	if etag == "" || w.Header().Get("Last-Modified") == "" {
+		t.Fatalf("disk: headers %v", w.Header())
+	}
+	if w = get("/css/app.css", etag); w.Code != http.StatusNotModified {
+		t.Errorf("disk revalidation: %d", w.Code)
+	}
+	// Expire the stat cache instead of waiting for it.
+	for _, a := range an.router().assets {
+		clear(a.stats.entries)
+	}
+	_ = os.WriteFile(file, []byte("body{color:red}"), 0o644)
+	if w = get("/css/app.css", etag); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("changed file: %d, ETag %s", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestRouter_StaticPrecompressed(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app.js":       {Data: []byte("console.log(1)")},
+		"app.js.br":    {Data: []byte("br-bytes")},
+		"app.js.gz":    {Data: []byte("gz-bytes")},
+		"style.css":    {Data: []byte("body{}")},
+		"style.css.gz": {Data: []byte("gz-css")},
+		"plain.txt":    {Data: []byte("plain")},
+	}
+	an := New()
+	an.StaticFS("/", fsys)
+
+	tests := []struct {
+		path, accept, encoding, body, vary string
+	}{
+		{"/app.js", "gzip, deflate, br", "br", "br-bytes", "Accept-Encoding"},
+		{"/app.js", "gzip", "gzip", "gz-bytes", "Accept-Encoding"},
+		{"/app.js", "br;q=0, gzip", "gzip", "gz-bytes", "Accept-Encoding"},
+		{"/app.js", "", "", "console.log(1)", "Accept-Encoding"},
+		{"/style.css", "br", "", "body{}", "Accept-Encoding"},
+		{"/plain.txt", "gzip", "", "plain", ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Accept-Encoding", tt.accept)
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+
+		h := w.Header()
+		if w.Body.String() != tt.body || h.Get("Content-Encoding") != tt.encoding || h.Get("Vary") != tt.vary {
+			t.Errorf("%s %q: body %q, encoding %q, vary %q", tt.path, tt.accept, w.Body.String(), h.Get("Content-Encoding"), h.Get("Vary"))
+		}
+		if tt.encoding != "" && h.Get("Content-Type") != "text/javascript; charset=utf-8" {
+			t.Errorf("%s %q: Content-Type %q", tt.path, tt.accept, h.Get("Content-Type"))
+		}
+	}
+}
+
+func TestAlsoNow_StaticCacheStats(t *testing.T) {
+	an := New()
+	an.StaticFS("/", fstest.MapFS{"app.js": {Data: []byte("console.log(1)")}})
+
+	for i := 0; i < 10; i++ {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/app.js", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d", w.Code)
+		}
+	}
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/missing.js", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("missing file: status = %d", w.Code)
+	}
+
+	stats := an.StaticCacheStats()
+	if stats.Misses > 6 || stats.HitRate() < 0.8 {
+		t.Errorf("stats = %+v, hit rate %.2f", stats, stats.HitRate())
+	}
+}
+
+// blockingFS counts the files opened and blocks until released.
+type blockingFS struct {
+	http.FileSystem
+	opens   atomic.Int32
+	release chan struct{}
+}
+
+func (f *blockingFS) Open(name string) (http.File, error) {
+	f.opens.Add(1)
+	<-f.release
+	return f.FileSystem.Open(name)
+}
+
+func TestStatCache_Coalescing(t *testing.T) {
+	fsys := &blockingFS{FileSystem: http.FS(fstest.MapFS{"app.js": {Data: []byte("x")}}), release: make(chan struct{})}
+	s := newStatCache(fsys, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if info, err := s.Stat("/app.js"); err != nil || info.Size() != 1 {
+				t.Errorf("Stat = %v, %v", info, err)
+			}
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(fsys.release)
+	wg.Wait()
+
+	if n := fsys.opens.Load(); n != 1 {
+		t.Errorf("concurrent misses stat'ed %d times, want 1", n)
+	}
+	if s.misses.Load() != 1 || s.hits.Load() != 9 {
+		t.Errorf("misses %d, hits %d", s.misses.Load(), s.hits.Load())
+	}
+}
+
+func TestStatCache_LRU(t *testing.T) {
+	s := newStatCache(http.FS(fstest.MapFS{}), time.Hour)
+	_, _ = s.Stat("/hot")
+	for i := 0; i < maxStaticStatEntries; i++ {
+		_, _ = s.Stat(fmt.Sprintf("/scan/%d", i))
+		_, _ = s.Stat("/hot")
+	}
+
+	if s.lru.Len() != maxStaticStatEntries || len(s.entries) != maxStaticStatEntries {
+		t.Errorf("%d entries, want %d", len(s.entries), maxStaticStatEntries)
+	}
+	if _, ok := s.entries["/hot"]; !ok {
+		t.Error("frequently used entry evicted by a scan")
+	}
+	if _, ok := s.entries["/scan/0"]; ok {
+		t.Error("least recently used entry kept")
+	}
+}
+
+func TestStatCache_Watch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "css", "app.css")
+	_ = os.MkdirAll(filepath.Dir(file), 0o755)
+	_ = os.WriteFile(file, []byte("body{}"), 0o644)
+
+	s := newStatCache(http.Dir(dir), time.Hour)
+	stop, err := s.watch()
+	if err != nil {
+		t.Skipf("cannot watch: %v", err)
+	}
+	defer stop()
+
+	if info, err := s.Stat("/css/app.css"); err != nil || info.Size() != 6 {
+		t.Fatalf("Stat = %v, %v", info, err)
+	}
+	if _, err := s.Stat("/js/app.js"); err == nil {
+		t.Fatal("missing file found")
+	}
+	_ = os.WriteFile(file, []byte("body{color:red}"), 0o644)
+	_ = os.MkdirAll(filepath.Join(dir, "js"), 0o755)
+	time.Sleep(20 * time.Millisecond)
+	_ = os.WriteFile(filepath.Join(dir, "js", "app.js"), []byte("1"), 0o644)
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		info, err := s.Stat("/css/app.css")
+		_, jsErr := s.Stat("/js/app.js")
+		if err == nil && info.Size() == 15 && jsErr == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("changes not picked up: %v, %v, %v", info, err, jsErr)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,277 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"container/list"
+	"errors"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// staticStatTTL is how long the stat results of static files are
+	// reused. Changes to the directories of Static are also watched while
+	// the server runs; the TTL bounds staleness when they cannot be.
+	staticStatTTL = time.Second
+	// maxStaticStatEntries bounds the entries of a stat cache, which also
+	// holds the misses of arbitrary request paths; the least recently used
+	// are evicted.
+	maxStaticStatEntries = 4096
+)
+
+// StaticCacheStats reports the stat cache of the static file systems.
+type StaticCacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// HitRate returns the fraction of the stat calls answered from the cache.
+func (s StaticCacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// StaticCacheStats returns the figures of the stat caches of the file
+// systems served by Static and StaticFS. Serving a file stats it, its
+// index.html for directories and its pre-compressed siblings; these results
+// are reused for a second, so high traffic on assets does not hammer the
+// file system, and missing files are not looked up again either.
+// Concurrent requests for a file not cached share a single stat call,
+// counted as one miss. While the server runs, the directories of Static
+// are watched and the files changed in them are stat'ed again at once.
+func (an *AlsoNow) StaticCacheStats() StaticCacheStats {
+	var stats StaticCacheStats
+	for _, a := range an.router().assets {
+		stats.Hits += a.stats.hits.Load()
+		stats.Misses += a.stats.misses.Load()
+	}
+	return stats
+}
+
+// statCache is an http.FileSystem caching the stat results of fsys for ttl.
+type statCache struct {
+	fsys http.FileSystem
+	ttl  time.Duration
+	// dir is the directory of an http.Dir, watched for changes.
+	dir string
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // of *statEntry
+	lru     list.List
+	// calls are the stat calls in progress, shared by concurrent misses.
+	calls map[string]*statCall
+
+	hits, misses atomic.Uint64
+}
+
+type statEntry struct {
+	name    string
+	info    fs.FileInfo
+	err     error
+	expires time.Time
+}
+
+type statCall struct {
+	done chan struct{}
+	e    *statEntry
+	// stale is set when name changed during the call.
+	stale bool
+}
+
+func newStatCache(fsys http.FileSystem, ttl time.Duration) *statCache {
+	s := &statCache{
+		fsys:    fsys,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		calls:   make(map[string]*statCall),
+	}
+	if d, ok := fsys.(http.Dir); ok {
+		s.dir = string(d)
+	}
+	return s
+}
+
+// Open opens name, failing without accessing fsys when name is known to be
+// missing.
+func (s *statCache) Open(name string) (http.File, error) {
+	if _, err := s.Stat(name); err != nil {
+		return nil, err
+	}
+	return s.fsys.Open(name)
+}
+
+// Stat returns the FileInfo of name.
+func (s *statCache) Stat(name string) (fs.FileInfo, error) {
+	now := time.Now()
+	s.mu.Lock()
+	if el, ok := s.entries[name]; ok {
+		if e := el.Value.(*statEntry); now.Before(e.expires) {
+			s.lru.MoveToFront(el)
+			s.mu.Unlock()
+			s.hits.Add(1)
+			return e.info, e.err
+		}
+	}
+	if call, ok := s.calls[name]; ok {
+		s.mu.Unlock()
+		<-call.done
+		s.hits.Add(1)
+		return call.e.info, call.e.err
+	}
+	call := &statCall{done: make(chan struct{})}
+	s.calls[name] = call
+	s.mu.Unlock()
+
+	s.misses.Add(1)
+	e := &statEntry{name: name, expires: now.Add(s.ttl)}
+	e.info, e.err = statFS(s.fsys, name)
+	call.e = e
+
+	s.mu.Lock()
+	delete(s.calls, name)
+	if !call.stale {
+		s.put(e)
+	}
+	s.mu.Unlock()
+	close(call.done)
+	return e.info, e.err
+}
+
+// put caches e, evicting the least recently used entry when full.
+func (s *statCache) put(e *statEntry) {
+	if el, ok := s.entries[e.name]; ok {
+		el.Value = e
+		s.lru.MoveToFront(el)
+		return
+	}
+	s.entries[e.name] = s.lru.PushFront(e)
+	if s.lru.Len() > maxStaticStatEntries {
+		oldest := s.lru.Back()
+		s.lru.Remove(oldest)
+		delete(s.entries, oldest.Value.(*statEntry).name)
+	}
+}
+
+// invalidate forgets name, its parent directory and, for a directory, the
+// entries below it.
+func (s *statCache) invalidate(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	forget := func(n string) {
+		if el, ok := s.entries[n]; ok {
+			s.lru.Remove(el)
+			delete(s.entries, n)
+		}
+		if call, ok := s.calls[n]; ok {
+			call.stale = true
+		}
+	}
+	forget(name)
+	forget(path.Dir(name))
+	for n := range s.entries {
+		if strings.HasPrefix(n, name+"/") {
+			forget(n)
+		}
+	}
+}
+
+// watch invalidates the entries of the files of s.dir as they change,
+// until the returned function is called.
+func (s *statCache) watch() (func() error, error) {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	addTree := func(root string) {
+		_ = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err == nil && d.IsDir() {
+				if err := w.Add(p); err != nil {
+					log.Printf("[STATIC] watch %s: %v", p, err)
+				}
+			}
+			return nil
+		})
+	}
+	addTree(s.dir)
+
+	go func() {
+		for {
+			select {
+			case ev, ok := <-w.Events:
+				if !ok {
+					return
+				}
+				rel, err := filepath.Rel(s.dir, ev.Name)
+				if err != nil {
+					continue
+				}
+				s.invalidate(path.Clean("/" + filepath.ToSlash(rel)))
+				if ev.Has(fsnotify.Create) {
+					if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+						addTree(ev.Name)
+					}
+				}
+			case err, ok := <-w.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("[STATIC] watch %s: %v", s.dir, err)
+			}
+		}
+	}()
+	return w.Close, nil
+}
+
+// watchStatic watches the directories served by Static until the returned
+// function is called. Directories that cannot be watched are logged and
+// rely on the TTL of the cache.
+func (an *AlsoNow) watchStatic() func() error {
+	var closers []func() error
+	for _, a := range an.router().assets {
+		if a.stats.dir == "" {
+			continue
+		}
+		stop, err := a.stats.watch()
+		if err != nil {
+			log.Printf("[STATIC] watch %s: %v", a.stats.dir, err)
+			continue
+		}
+		closers = append(closers, stop)
+	}
+	return func() error {
+		var errs []error
+		for _, stop := range closers {
+			errs = append(errs, stop())
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// statFS returns the FileInfo of name in fsys, from its cache when it has one.
+func statFS(fsys http.FileSystem, name string) (fs.FileInfo, error) {
+	if s, ok := fsys.(*statCache); ok {
+		return s.Stat(name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return f.Stat()
+}
@@ -0,0 +1,345 @@
+// Package redis
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package redis implements the alsonow store interfaces on Redis, so that
+// several instances of an application share their sessions, logins and
+// request counters.
+//
+//	client := goredis.NewClient(&goredis.Options{Addr: "localhost:6379"})
+//	store := redis.New(client, redis.Options{Prefix: "shop:"})
+//	an.Use(alsonow.Sessions(alsonow.SessionConfig{Store: store.Sessions()}))
+package redis
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/alsonow/alsonow"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// Options configures a Store.
+type Options struct {
+	// Prefix is prepended to every key, "alsonow:" when empty.
+	Prefix string
+	// Timeout bounds each operation on top of the caller's context, 1s
+	// when zero and none when negative.
+	Timeout time.Duration
+}
+
+// Store gives access to the Redis implementations of the store interfaces.
+type Store struct {
+	client  goredis.UniversalClient
+	prefix  string
+	timeout time.Duration
+}
+
+// New returns a Store using client, which may be a single node, a sentinel
+// or a cluster client. Scripts and transactions only ever touch one key, so
+// they never span hash slots on a cluster.
+func New(client goredis.UniversalClient, opts Options) *Store {
+	if opts.Prefix == "" {
+		opts.Prefix = "alsonow:"
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = time.Second
+	}
+	return &Store{client: client, prefix: opts.Prefix, timeout: opts.Timeout}
+}
+
+// Sessions returns an alsonow.SessionStore.
+func (s *Store) Sessions() alsonow.SessionStore { return sessionStore{s} }
+
+// RememberMe returns an alsonow.RememberMeStore.
+func (s *Store) RememberMe() alsonow.RememberMeStore { return rememberStore{s} }
+
+// LoginAttempts returns an alsonow.LoginAttemptStore.
+func (s *Store) LoginAttempts() alsonow.LoginAttemptStore { return loginStore{s} }
+
+// Quotas returns an alsonow.QuotaStore.
+func (s *Store) Quotas() alsonow.QuotaStore { return quotaStore{s} }
+
+// RateLimits returns an alsonow.RateLimitStore.
+func (s *Store) RateLimits() alsonow.RateLimitStore { return rateLimitStore{s} }
+
+func (s *Store) key(kind, id string) string {
+	return s.prefix + kind + ":" + id
+}
+
+func (s *Store) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout < 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+type sessionStore struct{ *Store }
+
+func (s sessionStore) Load(ctx context.Context, id string) ([]byte, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	data, err := s.client.Get(ctx, s.key("session", id)).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, alsonow.ErrSessionNotFound
+	}
+	return data, err
+}
+
+func (s sessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Set(ctx, s.key("session", id), data, ttl).Err()
+}
+
+func (s sessionStore) Delete(ctx context.Context, id string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Del(ctx, s.key("session", id)).Err()
+}
+
+// rememberStore keeps each series in a hash and the series of every user
+// in a set, used to revoke them all at once. Both live in different hash
+// slots on a cluster, so they are updated by plain pipelines: the set may
+// briefly list a series that is gone, which revoking ignores.
+type rememberStore struct{ *Store }
+
+func (s rememberStore) Get(ctx context.Context, series string) (alsonow.RememberToken, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	fields, err := s.client.HGetAll(ctx, s.key("remember", series)).Result()
+	if err != nil {
+		return alsonow.RememberToken{}, err
+	}
+	if len(fields) == 0 {
+		return alsonow.RememberToken{}, alsonow.ErrRememberTokenNotFound
+	}
+
+	expires, _ := strconv.ParseInt(fields["expires"], 10, 64)
+	t := alsonow.RememberToken{
+		Series:        series,
+		TokenHash:     fields["token"],
+		UserID:        fields["user"],
+		Expires:       time.Unix(expires, 0),
+		PrevTokenHash: fields["prev"],
+	}
+	if ms, err := strconv.ParseInt(fields["rotated"], 10, 64); err == nil && ms > 0 {
+		t.Rotated = time.UnixMilli(ms)
+	}
+	return t, nil
+}
+
+func (s rememberStore) Save(ctx context.Context, t alsonow.RememberToken) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	key, userKey := s.key("remember", t.Series), s.key("remember_user", t.UserID)
+	_, err := s.client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		p.HSet(ctx, key, "token", t.TokenHash, "user", t.UserID, "expires", t.Expires.Unix())
+		p.ExpireAt(ctx, key, t.Expires)
+		p.SAdd(ctx, userKey, t.Series)
+		p.ExpireAt(ctx, userKey, t.Expires)
+		return nil
+	})
+	return err
+}
+
+// rememberRotateScript replaces the token of a series if it is still the
+// expected one, atomically.
+var rememberRotateScript = goredis.NewScript(`
+local current = redis.call('HGET', KEYS[1], 'token')
+if not current then
+	return -1
+end
+if current ~= ARGV[1] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'token', ARGV[2], 'prev', ARGV[1], 'rotated', ARGV[3], 'expires', ARGV[4])
+redis.call('EXPIREAT', KEYS[1], ARGV[4])
+return 1
+`)
+
+func (s rememberStore) Rotate(ctx context.Context, prevHash string, t alsonow.RememberToken) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	n, err := rememberRotateScript.Run(ctx, s.client, []string{s.key("remember", t.Series)},
+		prevHash, t.TokenHash, t.Rotated.UnixMilli(), t.Expires.Unix()).Int()
+	switch {
+	case err != nil:
+		return err
+	case n < 0:
+		return alsonow.ErrRememberTokenNotFound
+	case n == 0:
+		return alsonow.ErrRememberTokenRotated
+	}
+	return s.client.ExpireAt(ctx, s.key("remember_user", t.UserID), t.Expires).Err()
+}
+
+func (s rememberStore) Delete(ctx context.Context, series string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	key := s.key("remember", series)
+	user, err := s.client.HGet(ctx, key, "user").Result()
+	if errors.Is(err, goredis.Nil) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		p.Del(ctx, key)
+		p.SRem(ctx, s.key("remember_user", user), series)
+		return nil
+	})
+	return err
+}
+
+func (s rememberStore) DeleteUser(ctx context.Context, userID string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	userKey := s.key("remember_user", userID)
+	series, err := s.client.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.Pipelined(ctx, func(p goredis.Pipeliner) error {
+		for _, id := range series {
+			p.Del(ctx, s.key("remember", id))
+		}
+		p.Del(ctx, userKey)
+		return nil
+	})
+	return err
+}
+
+// loginFailScript records a failure, forgetting those older than the
+// reset window, atomically.
+var loginFailScript = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local reset = tonumber(ARGV[2])
+local last = tonumber(redis.call('HGET', KEYS[1], 'last') or '0')
+if now - last > reset then
+	redis.call('HSET', KEYS[1], 'failures', 0)
+end
+local n = redis.call('HINCRBY', KEYS[1], 'failures', 1)
+redis.call('HSET', KEYS[1], 'last', now)
+redis.call('PEXPIRE', KEYS[1], reset)
+return n
+`)
+
+type loginStore struct{ *Store }
+
+func (s loginStore) Get(ctx context.Context, key string) (alsonow.LoginAttempts, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	vals, err := s.client.HMGet(ctx, s.key("login", key), "failures", "last").Result()
+	if err != nil {
+		return alsonow.LoginAttempts{}, err
+	}
+
+	var a alsonow.LoginAttempts
+	if v, ok := vals[0].(string); ok {
+		a.Failures, _ = strconv.Atoi(v)
+	}
+	if v, ok := vals[1].(string); ok {
+		ms, _ := strconv.ParseInt(v, 10, 64)
+		a.LastFailure = time.UnixMilli(ms)
+	}
+	return a, nil
+}
+
+func (s loginStore) Fail(ctx context.Context, key string, resetAfter time.Duration) (alsonow.LoginAttempts, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	now := time.Now()
+	n, err := loginFailScript.Run(ctx, s.client, []string{s.key("login", key)},
+		now.UnixMilli(), resetAfter.Milliseconds()).Int()
+	if err != nil {
+		return alsonow.LoginAttempts{}, err
+	}
+	return alsonow.LoginAttempts{Failures: n, LastFailure: time.UnixMilli(now.UnixMilli())}, nil
+}
+
+// loginForgiveScript takes back one failure, atomically.
+var loginForgiveScript = goredis.NewScript(`
+local n = tonumber(redis.call('HGET', KEYS[1], 'failures') or '0')
+if n <= 1 then
+	redis.call('DEL', KEYS[1])
+else
+	redis.call('HINCRBY', KEYS[1], 'failures', -1)
+end
+return 0
+`)
+
+func (s loginStore) Forgive(ctx context.Context, key string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return loginForgiveScript.Run(ctx, s.client, []string{s.key("login", key)}).Err()
+}
+
+func (s loginStore) Reset(ctx context.Context, key string) error {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+	return s.client.Del(ctx, s.key("login", key)).Err()
+}
+
+type quotaStore struct{ *Store }
+
+func (s quotaStore) Incr(ctx context.Context, key string, window time.Time, ttl time.Duration) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	k := s.key("quota", key+":"+strconv.FormatInt(window.Unix(), 10))
+	var incr *goredis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(p goredis.Pipeliner) error {
+		incr = p.Incr(ctx, k)
+		p.ExpireAt(ctx, k, window.Add(ttl))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
+
+type rateLimitStore struct{ *Store }
+
+func (s rateLimitStore) Get(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	n, err := s.client.Get(ctx, s.key("ratelimit", key)).Int64()
+	if errors.Is(err, goredis.Nil) {
+		return 0, nil
+	}
+	return n, err
+}
+
+func (s rateLimitStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	ctx, cancel := s.withTimeout(ctx)
+	defer cancel()
+
+	k := s.key("ratelimit", key)
+	var incr *goredis.IntCmd
+	_, err := s.client.TxPipelined(ctx, func(p goredis.Pipeliner) error {
+		incr = p.Incr(ctx, k)
+		p.ExpireNX(ctx, k, ttl)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
+}
@@ -0,0 +1,186 @@
+// Package redis
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package redis
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/alsonow/alsonow"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+func newTestStore(t *testing.T) (*Store, *miniredis.Miniredis) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	client.AddHook(singleKeyHook{t})
+	t.Cleanup(func() { _ = client.Close() })
+	return New(client, Options{Prefix: "test:"}), mr
+}
+
+// singleKeyHook fails the test when a command, script or transaction
+// touches several keys, which a cluster rejects unless they share a hash
+// slot.
+type singleKeyHook struct{ t *testing.T }
+
+func (h singleKeyHook) DialHook(next goredis.DialHook) goredis.DialHook { return next }
+
+func (h singleKeyHook) ProcessHook(next goredis.ProcessHook) goredis.ProcessHook {
+	return func(ctx context.Context, cmd goredis.Cmder) error {
+		args := cmd.Args()
+		switch cmd.Name() {
+		case "eval", "evalsha":
+			if n, _ := args[2].(int); n > 1 {
+				h.t.Errorf("script touches %d keys: %v", n, args)
+			}
+		case "del":
+			if len(args) > 2 {
+				h.t.Errorf("DEL of several keys: %v", args)
+			}
+		}
+		return next(ctx, cmd)
+	}
+}
+
+func (h singleKeyHook) ProcessPipelineHook(next goredis.ProcessPipelineHook) goredis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []goredis.Cmder) error {
+		if len(cmds) > 0 && cmds[0].Name() == "multi" {
+			keys := make(map[any]bool)
+			for _, cmd := range cmds[1 : len(cmds)-1] {
+				keys[cmd.Args()[1]] = true
+			}
+			if len(keys) > 1 {
+				h.t.Errorf("transaction touches %d keys: %v", len(keys), cmds)
+			}
+		}
+		return next(ctx, cmds)
+	}
+}
+
+func TestSessions(t *testing.T) {
+	s, mr := newTestStore(t)
+	ctx := context.Background()
+	sessions := s.Sessions()
+
+	if err := sessions.Save(ctx, "abc", []byte("data"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if !mr.Exists("test:session:abc") {
+		t.Error("key not prefixed")
+	}
+	if data, err := sessions.Load(ctx, "abc"); err != nil || string(data) != "data" {
+		t.Errorf("Load = %q, %v", data, err)
+	}
+
+	mr.FastForward(2 * time.Minute)
+	if _, err := sessions.Load(ctx, "abc"); !errors.Is(err, alsonow.ErrSessionNotFound) {
+		t.Errorf("expired Load error = %v", err)
+	}
+}
+
+func TestRememberMe(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+	store := s.RememberMe()
+
+	expires := time.Now().Add(time.Hour).Truncate(time.Second)
+	for _, series := range []string{"s1", "s2"} {
+		if err := store.Save(ctx, alsonow.RememberToken{Series: series, TokenHash: "h", UserID: "u", Expires: expires}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tok, err := store.Get(ctx, "s1")
+	if err != nil || tok.UserID != "u" || !tok.Expires.Equal(expires) {
+		t.Errorf("Get = %+v, %v", tok, err)
+	}
+
+	rotated := time.Now().Truncate(time.Millisecond)
+	next := alsonow.RememberToken{Series: "s1", TokenHash: "h2", UserID: "u", Expires: expires, PrevTokenHash: "h", Rotated: rotated}
+	if err := store.Rotate(ctx, "h", next); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Rotate(ctx, "h", next); !errors.Is(err, alsonow.ErrRememberTokenRotated) {
+		t.Errorf("second Rotate error = %v", err)
+	}
+	if err := store.Rotate(ctx, "h", alsonow.RememberToken{Series: "nope", UserID: "u", Expires: expires}); !errors.Is(err, alsonow.ErrRememberTokenNotFound) {
+		t.Errorf("Rotate of an unknown series error = %v", err)
+	}
+	if tok, err := store.Get(ctx, "s1"); err != nil || tok.TokenHash != "h2" || tok.PrevTokenHash != "h" || !tok.Rotated.Equal(rotated) {
+		t.Errorf("Get after Rotate = %+v, %v", tok, err)
+	}
+
+	if err := store.DeleteUser(ctx, "u"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get(ctx, "s2"); !errors.Is(err, alsonow.ErrRememberTokenNotFound) {
+		t.Errorf("Get after DeleteUser error = %v", err)
+	}
+}
+
+func TestLoginAttempts(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+	store := s.LoginAttempts()
+
+	for i := 1; i <= 3; i++ {
+		a, err := store.Fail(ctx, "ip|bob", time.Hour)
+		if err != nil || a.Failures != i {
+			t.Fatalf("Fail #%d = %+v, %v", i, a, err)
+		}
+	}
+	if a, _ := store.Get(ctx, "ip|bob"); a.Failures != 3 || a.LastFailure.IsZero() {
+		t.Errorf("Get = %+v", a)
+	}
+	if err := store.Forgive(ctx, "ip|bob"); err != nil {
+		t.Fatal(err)
+	}
+	if a, _ := store.Get(ctx, "ip|bob"); a.Failures != 2 {
+		t.Errorf("Get after Forgive = %+v", a)
+	}
+	_ = store.Reset(ctx, "ip|bob")
+	if a, _ := store.Get(ctx, "ip|bob"); a.Failures != 0 {
+		t.Errorf("Get after Reset = %+v", a)
+	}
+}
+
+func TestQuotas(t *testing.T) {
+	s, _ := newTestStore(t)
+	ctx := context.Background()
+	store := s.Quotas()
+
+	window := time.Now().Truncate(time.Hour)
+	for i := int64(1); i <= 3; i++ {
+		if n, err := store.Incr(ctx, "tenant", window, time.Hour); err != nil || n != i {
+			t.Fatalf("Incr #%d = %d, %v", i, n, err)
+		}
+	}
+	if n, _ := store.Incr(ctx, "tenant", window.Add(time.Hour), time.Hour); n != 1 {
+		t.Errorf("next window starts at %d", n)
+	}
+}
+
+func TestRateLimits(t *testing.T) {
+	s, mr := newTestStore(t)
+	ctx := context.Background()
+	store := s.RateLimits()
+
+	if n, err := store.Get(ctx, "client"); err != nil || n != 0 {
+		t.Fatalf("Get of a missing key = %d, %v", n, err)
+	}
+	for i := int64(1); i <= 3; i++ {
+		if n, err := store.Incr(ctx, "client", time.Minute); err != nil || n != i {
+			t.Fatalf("Incr #%d = %d, %v", i, n, err)
+		}
+		// The expiry counts from the first increment.
+		mr.FastForward(20 * time.Second)
+	}
+	if n, err := store.Get(ctx, "client"); err != nil || n != 0 {
+		t.Errorf("Get after ttl = %d, %v", n, err)
+	}
+}
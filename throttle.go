@@ -0,0 +1,167 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ThrottleConfig configures Throttle. Rates are in bytes per second; zero
+// leaves that direction unthrottled.
+type ThrottleConfig struct {
+	Upload   int64
+	Download int64
+	// Burst is the largest chunk transferred at once, the rate when zero.
+	Burst int64
+	// Shared makes all requests going through the middleware share the
+	// bandwidth instead of each getting the full rate.
+	Shared bool
+}
+
+// Throttle limits the bandwidth used to read request bodies and write
+// responses, so a single bulk client cannot saturate the network or disk.
+func Throttle(cfg ThrottleConfig) HandlerFunc {
+	var upShared, downShared *tokenBucket
+	if cfg.Shared {
+		upShared = newTokenBucket(cfg.Upload, cfg.Burst)
+		downShared = newTokenBucket(cfg.Download, cfg.Burst)
+	}
+
+	return func(c *Context) {
+		up, down := upShared, downShared
+		if !cfg.Shared {
+			up = newTokenBucket(cfg.Upload, cfg.Burst)
+			down = newTokenBucket(cfg.Download, cfg.Burst)
+		}
+
+		if up != nil && c.Req.Body != nil {
+			body := c.Req.Body
+			c.Req.Body = &throttledReader{ReadCloser: body, bucket: up, ctx: c.Context()}
+			defer func() { c.Req.Body = body }()
+		}
+		if down != nil {
+			w := c.Writer
+			c.Writer = &throttledWriter{ResponseWriter: w, bucket: down, ctx: c.Context()}
+			defer func() { c.Writer = w }()
+		}
+
+		c.Next()
+	}
+}
+
+// tokenBucket is a token bucket refilled continuously at rate tokens per second.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newTokenBucket returns nil when rate is not positive.
+func newTokenBucket(rate, burst int64) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst <= 0 {
+		burst = rate
+	}
+	return &tokenBucket{rate: float64(rate), burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// take consumes n tokens, returning how long the caller must wait before
+// the tokens are actually available.
+func (b *tokenBucket) take(n int) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.refill(time.Now())
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// refill adds the tokens accrued since the last refill. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
+
+// wait consumes n tokens, sleeping until they are available or ctx is done.
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+	d := b.take(n)
+	if d == 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *tokenBucket) chunk(n int) int {
+	if limit := int(b.burst); n > limit {
+		return limit
+	}
+	return n
+}
+
+type throttledReader struct {
+	io.ReadCloser
+	bucket *tokenBucket
+	ctx    context.Context
+}
+
+func (r *throttledReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p[:r.bucket.chunk(len(p))])
+	if n > 0 {
+		if werr := r.bucket.wait(r.ctx, n); werr != nil {
+			return n, werr
+		}
+	}
+	return n, err
+}
+
+type throttledWriter struct {
+	http.ResponseWriter
+	bucket *tokenBucket
+	ctx    context.Context
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := w.bucket.chunk(len(p))
+		if err := w.bucket.wait(w.ctx, n); err != nil {
+			return written, err
+		}
+		m, err := w.ResponseWriter.Write(p[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer.
+func (w *throttledWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,35 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestThrottle(t *testing.T) {
+	an := New()
+	an.POST("/upload", Throttle(ThrottleConfig{Upload: 10000, Download: 10000, Burst: 1000}), func(c *Context) {
+		body, _ := io.ReadAll(c.Req.Body)
+		_, _ = c.Writer.Write(body)
+	})
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(strings.Repeat("x", 2000))))
+	elapsed := time.Since(start)
+
+	if w.Body.Len() != 2000 {
+		t.Fatalf("body length = %d", w.Body.Len())
+	}
+	// 1000 bytes of burst each way, the remaining 1000 at 10kB/s each way.
+	if elapsed < 150*time.Millisecond {
+		t.Errorf("transfer took %v, expected it to be throttled", elapsed)
+	}
+}
@@ -0,0 +1,173 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// TimeoutConfig configures Timeout.
+type TimeoutConfig struct {
+	// Status answers the requests timing out, 503 Service Unavailable when
+	// zero; 504 Gateway Timeout suits handlers waiting on upstream
+	// services.
+	Status int
+	// Message is shown to the client, the status text when empty.
+	Message string
+}
+
+// Timeout bounds the time the rest of the chain has to answer. When d
+// elapses, the context of the request is canceled, so database calls and
+// outgoing requests made with it stop and no further handler of the chain
+// runs, and the client gets a 503 through the ErrorRenderer at once.
+//
+// The handlers write to a buffer, sent once they complete in time: their
+// writes after the timeout fail with http.ErrHandlerTimeout instead of
+// mixing with the error response, and streaming responses are not
+// supported. Timeout waits for the handlers to return before completing
+// the request, so they must honor the cancellation of the context.
+func Timeout(d time.Duration, cfg ...TimeoutConfig) HandlerFunc {
+	var conf TimeoutConfig
+	if len(cfg) > 0 {
+		conf = cfg[0]
+	}
+	if conf.Status == 0 {
+		conf.Status = http.StatusServiceUnavailable
+	}
+
+	return func(c *Context) {
+		req, w := c.Req, c.Writer
+		ctx, cancel := context.WithTimeout(req.Context(), d)
+		defer cancel()
+
+		tw := &timeoutWriter{header: w.Header().Clone()}
+		c.Req = req.WithContext(ctx)
+		c.Writer = tw
+
+		done := make(chan struct{})
+		// panicked is the value a handler panicked with, re-raised as is
+		// for Recover, and stack the stack of the handler goroutine, lost
+		// by then.
+		var panicked any
+		var stack []byte
+		go func() {
+			defer close(done)
+			defer func() {
+				if p := recover(); p != nil {
+					panicked, stack = p, debug.Stack()
+				}
+			}()
+			c.Next()
+		}()
+
+		timedOut := false
+		select {
+		case <-done:
+		case <-ctx.Done():
+			select {
+			case <-done:
+				// Completed right at the deadline.
+			default:
+				tw.timeout()
+				timedOut = true
+			}
+		}
+
+		if !timedOut {
+			c.Req, c.Writer = req, w
+			if panicked != nil {
+				if panicked != http.ErrAbortHandler {
+					c.Logf("[TIMEOUT] panic in handler: %v\n%s", panicked, stack)
+				}
+				panic(panicked)
+			}
+			tw.flushTo(w)
+			return
+		}
+
+		// The client is gone when the context of the request is done.
+		clientGone := req.Context().Err() != nil
+		if !clientGone {
+			// Rendered on a Context of its own, the handlers still using c.
+			ec := &Context{Writer: w, Req: req, errorRenderer: c.errorRenderer, templates: c.templates}
+			ec.renderError(&HTTPError{Status: conf.Status, Message: conf.Message})
+			_ = http.NewResponseController(w).Flush()
+		}
+
+		<-done
+		c.Req, c.Writer = req, w
+		if !clientGone {
+			c.Logf("[TIMEOUT] %s %s: no response within %v", req.Method, req.URL.Path, d)
+		}
+		if panicked != nil && panicked != http.ErrAbortHandler {
+			c.Logf("[TIMEOUT] panic after the timeout: %v\n%s", panicked, stack)
+		}
+	}
+}
+
+// timeoutWriter buffers the response of the handlers run by Timeout.
+type timeoutWriter struct {
+	header http.Header
+
+	mu          sync.Mutex
+	buf         bytes.Buffer
+	status      int
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	// Informational responses are dropped, the response being sent at once.
+	if w.timedOut || w.wroteHeader || code < 200 {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+}
+
+func (w *timeoutWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.status = http.StatusOK
+	}
+	return w.buf.Write(p)
+}
+
+// timeout makes the writes fail from now on.
+func (w *timeoutWriter) timeout() {
+	w.mu.Lock()
+	w.timedOut = true
+	w.mu.Unlock()
+}
+
+// flushTo sends the buffered response to dst.
+func (w *timeoutWriter) flushTo(dst http.ResponseWriter) {
+	h := dst.Header()
+	clear(h)
+	for k, v := range w.header {
+		h[k] = v
+	}
+	if !w.wroteHeader {
+		return
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.buf.Bytes())
+}
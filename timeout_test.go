@@ -0,0 +1,85 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTimeout(t *testing.T) {
+	lateWrite := make(chan error, 1)
+	ran := false
+
+	an := New()
+	g := an.Group("/", func(c *Context) {
+		c.SetHeader("X-Outer", "1")
+		c.Next()
+	}, Timeout(20*time.Millisecond))
+	g.GET("/fast", func(c *Context) {
+		c.SetHeader("X-Inner", "1")
+		c.JSON(http.StatusCreated, "ok")
+	})
+	g.GET("/slow", func(c *Context) {
+		<-c.Context().Done()
+		time.Sleep(10 * time.Millisecond)
+		_, err := c.Writer.Write([]byte("late"))
+		lateWrite <- err
+	}, func(c *Context) { ran = true })
+	g.GET("/panic", func(c *Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+	if w.Code != http.StatusCreated || w.Header().Get("X-Outer") != "1" || w.Header().Get("X-Inner") != "1" ||
+		strings.TrimSpace(w.Body.String()) != `"ok"` {
+		t.Errorf("fast: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+	req.Header.Set("Accept", "application/json")
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusServiceUnavailable || w.Header().Get("Content-Type") != MIMEProblem ||
+		w.Header().Get("X-Outer") != "1" || strings.Contains(w.Body.String(), "late") {
+		t.Errorf("slow: %d %v %q", w.Code, w.Header(), w.Body.String())
+	}
+	if err := <-lateWrite; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Errorf("write after timeout = %v, want ErrHandlerTimeout", err)
+	}
+	if ran {
+		t.Error("chain continued after the timeout")
+	}
+
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("panic: %d, want 500 from Recover", w.Code)
+	}
+}
+
+func TestTimeout_PanicValue(t *testing.T) {
+	type typedPanic struct{ code int }
+	var got any
+	an := New()
+	g := an.Group("/", func(c *Context) {
+		defer func() { got = recover() }()
+		c.Next()
+	}, Timeout(time.Second))
+	g.GET("/abort", func(c *Context) { panic(http.ErrAbortHandler) })
+	g.GET("/typed", func(c *Context) { panic(typedPanic{7}) })
+
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/abort", nil))
+	if err, ok := got.(error); !ok || !errors.Is(err, http.ErrAbortHandler) {
+		t.Errorf("recovered %#v, want http.ErrAbortHandler", got)
+	}
+	an.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/typed", nil))
+	if got != (typedPanic{7}) {
+		t.Errorf("recovered %#v, want the typed value", got)
+	}
+}
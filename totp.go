@@ -0,0 +1,221 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// twoFactorKey is the session value holding the Unix time of the last
+// second factor verification.
+const twoFactorKey = "alsonow.2fa_at"
+
+var totpEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// TOTP generates and verifies RFC 6238 time-based one-time passwords, as
+// used by Google Authenticator and similar apps. HMAC-SHA1 is used since it
+// is the only algorithm every app supports.
+type TOTP struct {
+	// Secret is the base32 encoded shared key, see NewTOTPSecret.
+	Secret string
+	// Digits of the codes, 6 when zero.
+	Digits int
+	// Period of validity of a code, 30s when zero.
+	Period time.Duration
+	// Skew is the number of periods accepted before and after the current
+	// one to tolerate clock drift. Zero only accepts the current code.
+	Skew int
+}
+
+// NewTOTPSecret returns a random 160-bit base32 encoded secret.
+func NewTOTPSecret() string {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return totpEncoding.EncodeToString(b)
+}
+
+// URI returns the otpauth:// provisioning URI, to be shown as a QR code.
+func (t TOTP) URI(issuer, account string) string {
+	q := url.Values{}
+	q.Set("secret", t.Secret)
+	q.Set("issuer", issuer)
+	q.Set("algorithm", "SHA1")
+	q.Set("digits", strconv.Itoa(t.digits()))
+	q.Set("period", strconv.Itoa(int(t.period().Seconds())))
+
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + q.Encode()
+}
+
+// Code returns the code valid at the given time.
+func (t TOTP) Code(at time.Time) (string, error) {
+	key, err := t.key()
+	if err != nil {
+		return "", err
+	}
+	return t.code(key, t.step(at)), nil
+}
+
+// Verify checks code against the codes valid around the given time and
+// returns the time step it belongs to. Store the step of the last accepted
+// code and reject codes of older or equal steps to prevent replays.
+func (t TOTP) Verify(code string, at time.Time) (step int64, ok bool) {
+	key, err := t.key()
+	if err != nil || len(code) != t.digits() {
+		return 0, false
+	}
+
+	now := t.step(at)
+	for i := -int64(t.Skew); i <= int64(t.Skew); i++ {
+		if subtle.ConstantTimeCompare([]byte(t.code(key, now+i)), []byte(code)) == 1 {
+			return now + i, true
+		}
+	}
+	return 0, false
+}
+
+func (t TOTP) key() ([]byte, error) {
+	secret := strings.ToUpper(strings.ReplaceAll(t.Secret, " ", ""))
+	key, err := totpEncoding.DecodeString(strings.TrimRight(secret, "="))
+	if err != nil {
+		return nil, fmt.Errorf("totp: invalid secret: %w", err)
+	}
+	return key, nil
+}
+
+// code implements the HOTP truncation of RFC 4226.
+func (t TOTP) code(key []byte, counter int64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], uint64(counter))
+
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0F
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7FFFFFFF
+
+	digits := t.digits()
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%mod)
+}
+
+func (t TOTP) step(at time.Time) int64 {
+	return at.Unix() / int64(t.period().Seconds())
+}
+
+func (t TOTP) digits() int {
+	if t.Digits == 0 {
+		return 6
+	}
+	return t.Digits
+}
+
+func (t TOTP) period() time.Duration {
+	if t.Period == 0 {
+		return 30 * time.Second
+	}
+	return t.Period
+}
+
+// NewRecoveryCodes returns n single-use recovery codes to show the user
+// once, and their hashes to store in their place.
+func NewRecoveryCodes(n int) (codes, hashes []string) {
+	for i := 0; i < n; i++ {
+		code := randomHex(5)
+		codes = append(codes, code[:5]+"-"+code[5:])
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	return codes, hashes
+}
+
+// MatchRecoveryCode returns the index in hashes of code, or -1. The caller
+// must remove the matched hash so the code cannot be used again.
+func MatchRecoveryCode(hashes []string, code string) int {
+	h := hashRecoveryCode(strings.ReplaceAll(strings.ToLower(strings.TrimSpace(code)), "-", ""))
+	match := -1
+	for i, stored := range hashes {
+		if subtle.ConstantTimeCompare([]byte(h), []byte(stored)) == 1 {
+			match = i
+		}
+	}
+	return match
+}
+
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// MarkTwoFactor records in the session that the user just proved their
+// second factor. The session is regenerated as its privilege level changed.
+func MarkTwoFactor(s *Session) {
+	s.Regenerate()
+	s.Set(twoFactorKey, time.Now().Unix())
+}
+
+// TwoFactorAge returns how long ago the second factor was verified in the
+// session, and false when it never was.
+func TwoFactorAge(s *Session) (time.Duration, bool) {
+	v, ok := s.Get(twoFactorKey)
+	if !ok {
+		return 0, false
+	}
+
+	var at int64
+	switch v := v.(type) {
+	case int64:
+		at = v
+	case float64: // JSONSerializer
+		at = int64(v)
+	default:
+		return 0, false
+	}
+	return time.Since(time.Unix(at, 0)), true
+}
+
+// RequireTwoFactor only lets requests through when the second factor was
+// verified in the session within maxAge, for step-up authentication of
+// sensitive actions. Other requests are passed to onMissing, which usually
+// redirects to the verification page, or get 403 when it is nil. It must
+// run after Sessions.
+func RequireTwoFactor(maxAge time.Duration, onMissing HandlerFunc) HandlerFunc {
+	return func(c *Context) {
+		s := c.Session()
+		if s == nil {
+			panic("alsonow: RequireTwoFactor requires Sessions")
+		}
+
+		if age, ok := TwoFactorAge(s); ok && age <= maxAge {
+			c.Next()
+			return
+		}
+
+		if onMissing != nil {
+			onMissing(c)
+		} else {
+			c.Error(http.StatusForbidden, "")
+		}
+		c.Abort()
+	}
+}
@@ -0,0 +1,86 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"encoding/base32"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTOTP_RFC6238(t *testing.T) {
+	// Test vectors of RFC 6238 appendix B for SHA1.
+	secret := base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+	totp := TOTP{Secret: secret, Digits: 8}
+
+	for unix, want := range map[int64]string{
+		59:          "94287082",
+		1111111109:  "07081804",
+		1234567890:  "89005924",
+		20000000000: "65353130",
+	} {
+		if got, _ := totp.Code(time.Unix(unix, 0)); got != want {
+			t.Errorf("Code(%d) = %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestTOTP_Verify(t *testing.T) {
+	totp := TOTP{Secret: NewTOTPSecret(), Skew: 1}
+	now := time.Now()
+
+	prev, _ := totp.Code(now.Add(-30 * time.Second))
+	if _, ok := totp.Verify(prev, now); !ok {
+		t.Error("code of the previous period rejected with Skew 1")
+	}
+	old, _ := totp.Code(now.Add(-2 * time.Minute))
+	if _, ok := totp.Verify(old, now); ok {
+		t.Error("code outside the drift window accepted")
+	}
+
+	uri := totp.URI("Acme", "bob@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Acme:bob@example.com?") || !strings.Contains(uri, "secret="+totp.Secret) {
+		t.Errorf("URI = %s", uri)
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, hashes := NewRecoveryCodes(8)
+	if len(codes) != 8 || len(hashes) != 8 {
+		t.Fatalf("got %d codes", len(codes))
+	}
+	if i := MatchRecoveryCode(hashes, strings.ToUpper(codes[3])); i != 3 {
+		t.Errorf("MatchRecoveryCode = %d, want 3", i)
+	}
+	if i := MatchRecoveryCode(hashes, "00000-00000"); i != -1 {
+		t.Errorf("unknown code matched %d", i)
+	}
+}
+
+func TestRequireTwoFactor(t *testing.T) {
+	an := New()
+	an.Use(Sessions(SessionConfig{}))
+	an.POST("/2fa", func(c *Context) { MarkTwoFactor(c.Session()) })
+	an.GET("/admin", RequireTwoFactor(time.Minute, nil), func(c *Context) {})
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("without 2FA: status = %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/2fa", nil))
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	req.AddCookie(w.Result().Cookies()[0])
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("after 2FA: status = %d", w.Code)
+	}
+}
@@ -0,0 +1,477 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TusVersion is the version of the tus resumable upload protocol implemented.
+// https://tus.io/protocols/resumable-upload
+const TusVersion = "1.0.0"
+
+var (
+	// ErrUploadNotFound is returned by an UploadStore for unknown uploads.
+	ErrUploadNotFound = errors.New("upload not found")
+	// ErrUploadOffset is returned when a chunk does not start at the current offset.
+	ErrUploadOffset = errors.New("upload offset mismatch")
+)
+
+// UploadInfo describes a resumable upload.
+type UploadInfo struct {
+	ID        string            `json:"id"`
+	Size      int64             `json:"size"`
+	Offset    int64             `json:"offset"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	ExpiresAt time.Time         `json:"expires_at,omitempty"`
+}
+
+// Complete reports whether all bytes have been received.
+func (u UploadInfo) Complete() bool {
+	return u.Offset >= u.Size
+}
+
+// UploadStore persists resumable uploads.
+type UploadStore interface {
+	// Create stores a new, empty upload described by info.
+	Create(ctx context.Context, info UploadInfo) error
+	// Info returns the current state of an upload.
+	Info(ctx context.Context, id string) (UploadInfo, error)
+	// WriteChunk appends the data read from r to the upload, which must
+	// currently be at offset, and returns the number of bytes written.
+	WriteChunk(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+	// Delete removes the upload and its data.
+	Delete(ctx context.Context, id string) error
+}
+
+// TusOptions configures Tus.
+type TusOptions struct {
+	// MaxSize limits the size of a single upload, unlimited when zero.
+	MaxSize int64
+	// Expiration is how long an unfinished upload is kept, 24h when zero.
+	Expiration time.Duration
+	// OnComplete is called once an upload has received all its bytes.
+	OnComplete func(c *Context, info UploadInfo)
+}
+
+// Tus mounts a tus 1.0.0 endpoint (core, creation, expiration and
+// termination extensions) under prefix. Uploads are created with a POST on
+// prefix and resumed with HEAD/PATCH on prefix/:id.
+func (an *AlsoNow) Tus(prefix string, store UploadStore, opts TusOptions, middlewares ...HandlerFunc) {
+	if opts.Expiration == 0 {
+		opts.Expiration = 24 * time.Hour
+	}
+	t := &tusHandler{store: store, opts: opts, prefix: normalizePath(prefix)}
+
+	g := an.Group(prefix, append([]HandlerFunc{t.versionCheck}, middlewares...)...)
+	g.OPTIONS("/", t.options)
+	g.POST("/", t.create)
+	g.HEAD("/:id", t.head)
+	g.PATCH("/:id", t.patch)
+	g.DELETE("/:id", t.delete)
+}
+
+type tusHandler struct {
+	store  UploadStore
+	opts   TusOptions
+	prefix string
+}
+
+func (t *tusHandler) versionCheck(c *Context) {
+	c.SetHeader("Tus-Resumable", TusVersion)
+	if c.Method() != http.MethodOptions && c.Header("Tus-Resumable") != TusVersion {
+		c.SetHeader("Tus-Version", TusVersion)
+		c.Status(http.StatusPreconditionFailed)
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+func (t *tusHandler) options(c *Context) {
+	c.SetHeader("Tus-Version", TusVersion)
+	c.SetHeader("Tus-Extension", "creation,expiration,termination")
+	if t.opts.MaxSize > 0 {
+		c.SetHeader("Tus-Max-Size", strconv.FormatInt(t.opts.MaxSize, 10))
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (t *tusHandler) create(c *Context) {
+	size, err := strconv.ParseInt(c.Header("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		c.Error(http.StatusBadRequest, "invalid Upload-Length")
+		return
+	}
+	if t.opts.MaxSize > 0 && size > t.opts.MaxSize {
+		c.Error(http.StatusRequestEntityTooLarge, "upload too large")
+		return
+	}
+
+	meta, err := parseUploadMetadata(c.Header("Upload-Metadata"))
+	if err != nil {
+		c.Error(http.StatusBadRequest, "invalid Upload-Metadata")
+		return
+	}
+
+	info := UploadInfo{
+		ID:        randomHex(16),
+		Size:      size,
+		Metadata:  meta,
+		ExpiresAt: time.Now().Add(t.opts.Expiration),
+	}
+	if err := t.store.Create(c.Context(), info); err != nil {
+		c.InternalServerError(err)
+		return
+	}
+
+	c.SetHeader("Location", strings.TrimSuffix(t.prefix, "/")+"/"+info.ID)
+	c.SetHeader("Upload-Expires", info.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusCreated)
+
+	if size == 0 && t.opts.OnComplete != nil {
+		t.opts.OnComplete(c, info)
+	}
+}
+
+func (t *tusHandler) head(c *Context) {
+	info, ok := t.lookup(c)
+	if !ok {
+		return
+	}
+
+	c.SetHeader("Cache-Control", "no-store")
+	c.SetHeader("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	c.SetHeader("Upload-Length", strconv.FormatInt(info.Size, 10))
+	if len(info.Metadata) > 0 {
+		c.SetHeader("Upload-Metadata", formatUploadMetadata(info.Metadata))
+	}
+	if !info.Complete() {
+		c.SetHeader("Upload-Expires", info.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+	c.Status(http.StatusOK)
+}
+
+func (t *tusHandler) patch(c *Context) {
+	if c.Header("Content-Type") != "application/offset+octet-stream" {
+		c.Error(http.StatusUnsupportedMediaType, "")
+		return
+	}
+
+	offset, err := strconv.ParseInt(c.Header("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		c.Error(http.StatusBadRequest, "invalid Upload-Offset")
+		return
+	}
+
+	info, ok := t.lookup(c)
+	if !ok {
+		return
+	}
+	if offset != info.Offset {
+		c.Error(http.StatusConflict, "")
+		return
+	}
+
+	n, err := t.store.WriteChunk(c.Context(), info.ID, offset, io.LimitReader(c.Req.Body, info.Size-offset))
+	if errors.Is(err, ErrUploadOffset) {
+		c.Error(http.StatusConflict, "")
+		return
+	}
+	// A broken connection still leaves a valid, resumable offset behind,
+	// so only fail when nothing could be stored.
+	if err != nil && n == 0 {
+		c.InternalServerError(err)
+		return
+	}
+
+	info.Offset += n
+	c.SetHeader("Upload-Offset", strconv.FormatInt(info.Offset, 10))
+	c.SetHeader("Upload-Expires", info.ExpiresAt.UTC().Format(http.TimeFormat))
+	c.Status(http.StatusNoContent)
+
+	if info.Complete() && t.opts.OnComplete != nil {
+		t.opts.OnComplete(c, info)
+	}
+}
+
+func (t *tusHandler) delete(c *Context) {
+	if _, ok := t.lookup(c); !ok {
+		return
+	}
+	if err := t.store.Delete(c.Context(), c.Param("id")); err != nil {
+		c.InternalServerError(err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// lookup loads the upload named by the id parameter, writing the error
+// response itself when it cannot be used.
+func (t *tusHandler) lookup(c *Context) (UploadInfo, bool) {
+	info, err := t.store.Info(c.Context(), c.Param("id"))
+	if errors.Is(err, ErrUploadNotFound) {
+		c.Error(http.StatusNotFound, "")
+		return info, false
+	}
+	if err != nil {
+		c.InternalServerError(err)
+		return info, false
+	}
+
+	if !info.Complete() && !info.ExpiresAt.IsZero() && time.Now().After(info.ExpiresAt) {
+		_ = t.store.Delete(c.Context(), info.ID)
+		c.Error(http.StatusGone, "")
+		return info, false
+	}
+	return info, true
+}
+
+// parseUploadMetadata parses "key base64value,key2 base64value2".
+func parseUploadMetadata(header string) (map[string]string, error) {
+	if header == "" {
+		return nil, nil
+	}
+
+	meta := make(map[string]string)
+	for _, pair := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, fmt.Errorf("empty metadata key")
+		}
+		decoded, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, err
+		}
+		meta[key] = string(decoded)
+	}
+	return meta, nil
+}
+
+func formatUploadMetadata(meta map[string]string) string {
+	pairs := make([]string, 0, len(meta))
+	for k, v := range meta {
+		pairs = append(pairs, k+" "+base64.StdEncoding.EncodeToString([]byte(v)))
+	}
+	return strings.Join(pairs, ",")
+}
+
+// randomHex returns n random bytes encoded as hex.
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// FileUploadStore is an UploadStore keeping uploads in a directory: the data
+// in <id> and the state in <id>.info.
+type FileUploadStore struct {
+	dir string
+	// mu guards the .info files and uploads. It is never held while
+	// reading a request body, so a stalled client only holds the lock of
+	// its own upload.
+	mu      sync.Mutex
+	uploads map[string]*uploadLock
+}
+
+// uploadLock serializes the writes to an upload.
+type uploadLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewFileUploadStore returns a FileUploadStore rooted at dir, creating it if needed.
+func NewFileUploadStore(dir string) (*FileUploadStore, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	return &FileUploadStore{dir: dir, uploads: make(map[string]*uploadLock)}, nil
+}
+
+// Path returns the location of the data of upload id. The store rejects the
+// ids that are not hexadecimal, such as "..", before building paths.
+func (s *FileUploadStore) Path(id string) string {
+	return filepath.Join(s.dir, filepath.Base(id))
+}
+
+// validUploadID reports whether id is made of the lowercase hexadecimal
+// digits of randomHex, so it always names a file inside the directory.
+func validUploadID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if c := id[i]; !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *FileUploadStore) Create(_ context.Context, info UploadInfo) error {
+	if !validUploadID(info.ID) {
+		return fmt.Errorf("tus: invalid upload id %q", info.ID)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.OpenFile(s.Path(info.ID), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return s.writeInfo(info)
+}
+
+func (s *FileUploadStore) Info(_ context.Context, id string) (UploadInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.readInfo(id)
+}
+
+// lockUpload takes the lock of upload id and returns its release.
+func (s *FileUploadStore) lockUpload(id string) func() {
+	s.mu.Lock()
+	l := s.uploads[id]
+	if l == nil {
+		l = &uploadLock{}
+		s.uploads[id] = l
+	}
+	l.refs++
+	s.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		s.mu.Lock()
+		if l.refs--; l.refs == 0 {
+			delete(s.uploads, id)
+		}
+		s.mu.Unlock()
+	}
+}
+
+func (s *FileUploadStore) WriteChunk(_ context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	if !validUploadID(id) {
+		return 0, ErrUploadNotFound
+	}
+	defer s.lockUpload(id)()
+
+	s.mu.Lock()
+	info, err := s.readInfo(id)
+	s.mu.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	if info.Offset != offset {
+		return 0, ErrUploadOffset
+	}
+
+	f, err := os.OpenFile(s.Path(id), os.O_WRONLY, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, copyErr := io.Copy(f, r)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	// The upload may have been deleted during the copy.
+	if _, err := s.readInfo(id); err != nil {
+		return n, err
+	}
+	info.Offset += n
+	if err := s.writeInfo(info); err != nil {
+		return n, err
+	}
+	return n, copyErr
+}
+
+func (s *FileUploadStore) Delete(_ context.Context, id string) error {
+	if !validUploadID(id) {
+		return ErrUploadNotFound
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.Remove(s.Path(id) + ".info"); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return ErrUploadNotFound
+		}
+		return err
+	}
+	return os.Remove(s.Path(id))
+}
+
+// PurgeExpired removes unfinished uploads whose expiration has passed.
+func (s *FileUploadStore) PurgeExpired(ctx context.Context) error {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*.info"))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, m := range matches {
+		id := strings.TrimSuffix(filepath.Base(m), ".info")
+		info, err := s.Info(ctx, id)
+		if err != nil {
+			continue
+		}
+		if !info.Complete() && !info.ExpiresAt.IsZero() && now.After(info.ExpiresAt) {
+			if err := s.Delete(ctx, id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *FileUploadStore) readInfo(id string) (UploadInfo, error) {
+	var info UploadInfo
+	if !validUploadID(id) {
+		return info, ErrUploadNotFound
+	}
+
+	raw, err := os.ReadFile(s.Path(id) + ".info")
+	if errors.Is(err, fs.ErrNotExist) {
+		return info, ErrUploadNotFound
+	}
+	if err != nil {
+		return info, err
+	}
+	return info, json.Unmarshal(raw, &info)
+}
+
+func (s *FileUploadStore) writeInfo(info UploadInfo) error {
+	raw, err := json.Marshal(info)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.Path(info.ID)+".info", raw, 0o640)
+}
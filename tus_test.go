@@ -0,0 +1,177 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAlsoNow_Tus(t *testing.T) {
+	store, err := NewFileUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completed := false
+	an := New()
+	an.Tus("/files", store, TusOptions{
+		MaxSize:    100,
+		OnComplete: func(c *Context, info UploadInfo) { completed = true },
+	})
+
+	do := func(method, target, body string, headers ...string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Tus-Resumable", TusVersion)
+		for i := 0; i < len(headers); i += 2 {
+			req.Header.Set(headers[i], headers[i+1])
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/files", "", "Upload-Length", "11", "Upload-Metadata", "filename aGVsbG8udHh0")
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d", w.Code)
+	}
+	location := w.Header().Get("Location")
+
+	w = do(http.MethodPatch, location, "hello ", "Content-Type", "application/offset+octet-stream", "Upload-Offset", "0")
+	if w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "6" {
+		t.Fatalf("patch: status = %d, offset = %q", w.Code, w.Header().Get("Upload-Offset"))
+	}
+
+	w = do(http.MethodPatch, location, "world", "Content-Type", "application/offset+octet-stream", "Upload-Offset", "0")
+	if w.Code != http.StatusConflict {
+		t.Errorf("stale offset: status = %d, want %d", w.Code, http.StatusConflict)
+	}
+
+	w = do(http.MethodHead, location, "")
+	if w.Header().Get("Upload-Offset") != "6" || w.Header().Get("Upload-Length") != "11" {
+		t.Errorf("head: unexpected headers %v", w.Header())
+	}
+
+	w = do(http.MethodPatch, location, "world", "Content-Type", "application/offset+octet-stream", "Upload-Offset", "6")
+	if w.Code != http.StatusNoContent || !completed {
+		t.Fatalf("final patch: status = %d, completed = %v", w.Code, completed)
+	}
+
+	data, _ := os.ReadFile(store.Path(strings.TrimPrefix(location, "/files/")))
+	if string(data) != "hello world" {
+		t.Errorf("stored data = %q", data)
+	}
+
+	if w = do(http.MethodPost, "/files", "", "Upload-Length", "101"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("too large: status = %d", w.Code)
+	}
+}
+
+func TestFileUploadStore_StalledWriter(t *testing.T) {
+	store, err := NewFileUploadStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"a", "b"} {
+		if err := store.Create(ctx, UploadInfo{ID: id, Size: 100}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	written := make(chan int64, 1)
+	go func() {
+		n, _ := store.WriteChunk(ctx, "a", 0, pr)
+		written <- n
+	}()
+	if _, err := pw.Write([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The client of upload a stalls; the other uploads are not held up.
+	done := make(chan error, 1)
+	go func() {
+		info, err := store.Info(ctx, "b")
+		if err == nil && info.Offset != 0 {
+			t.Errorf("offset of b = %d", info.Offset)
+		}
+		if err == nil {
+			_, err = store.WriteChunk(ctx, "b", 0, strings.NewReader("xyz"))
+		}
+		if err == nil {
+			err = store.Create(ctx, UploadInfo{ID: "c", Size: 1})
+		}
+		if err == nil {
+			err = store.Delete(ctx, "c")
+		}
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("other uploads blocked by a stalled writer")
+	}
+
+	if _, err := pw.Write([]byte("de")); err != nil {
+		t.Fatal(err)
+	}
+	_ = pw.Close()
+	if n := <-written; n != 5 {
+		t.Errorf("written = %d, want 5", n)
+	}
+	if info, err := store.Info(ctx, "a"); err != nil || info.Offset != 5 {
+		t.Errorf("a = %+v, %v", info, err)
+	}
+	if len(store.uploads) != 0 {
+		t.Errorf("upload locks left: %v", store.uploads)
+	}
+}
+
+func TestFileUploadStore_RejectsTraversal(t *testing.T) {
+	base := t.TempDir()
+	store, err := NewFileUploadStore(filepath.Join(base, "a", "uploads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "<dir>/.." + ".info" names a file next to the parent of the store.
+	victim := filepath.Join(base, "a.info")
+	if err := os.WriteFile(victim, []byte(`{"id":"..","size":10}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	an := New()
+	an.Tus("/files", store, TusOptions{})
+	for _, method := range []string{http.MethodHead, http.MethodPatch, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/files/x", strings.NewReader("data"))
+		req.URL.Path = "/files/.."
+		req.Header.Set("Tus-Resumable", TusVersion)
+		req.Header.Set("Content-Type", "application/offset+octet-stream")
+		req.Header.Set("Upload-Offset", "0")
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		if w.Code != http.StatusNotFound {
+			t.Errorf("%s /files/..: status = %d", method, w.Code)
+		}
+	}
+
+	ctx := context.Background()
+	if err := store.Create(ctx, UploadInfo{ID: "../b"}); err == nil {
+		t.Error("Create accepted a path as id")
+	}
+	if _, err := os.Stat(victim); err != nil {
+		t.Errorf("file outside the store: %v", err)
+	}
+}
@@ -0,0 +1,77 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+)
+
+// txKey is the Context key of the transaction of Tx.
+const txKey = "alsonow.tx"
+
+// Tx runs the rest of the chain in a transaction of db, read with c.Tx.
+// The transaction is committed when the handlers complete with a status
+// below 400, and rolled back when they answer an error, through c.Error,
+// c.InternalServerError or any status of 400 or more, or panic; the panic
+// then propagates to Recover.
+//
+// The functions registered with c.AfterCommit while the transaction is
+// open only run when it is committed.
+//
+// The response may be written before the commit. When committing fails, a
+// 500 is answered if nothing was written yet, and the failure is logged in
+// any case, so handlers whose response depends on the commit should write
+// it once done with the database.
+func Tx(db *sql.DB, opts ...*sql.TxOptions) HandlerFunc {
+	var txOpts *sql.TxOptions
+	if len(opts) > 0 {
+		txOpts = opts[0]
+	}
+
+	return func(c *Context) {
+		tx, err := db.BeginTx(c.Context(), txOpts)
+		if err != nil {
+			c.InternalServerError(fmt.Errorf("begin transaction: %w", err))
+			c.Abort()
+			return
+		}
+		c.Set(txKey, tx)
+		hooks := len(c.afterCommit)
+
+		committed := false
+		defer func() {
+			if !committed {
+				_ = tx.Rollback()
+				c.discardAfterCommit(hooks)
+			}
+		}()
+
+		c.Next()
+
+		if status := c.StatusCode(); status >= http.StatusBadRequest {
+			return
+		}
+		committed = true
+		if err := tx.Commit(); err != nil {
+			c.discardAfterCommit(hooks)
+			err = fmt.Errorf("commit transaction: %w", err)
+			if c.Written() {
+				c.Logf("[TX] %v", err)
+				return
+			}
+			c.InternalServerError(err)
+		}
+	}
+}
+
+// Tx returns the transaction of the request started by the Tx middleware,
+// or nil.
+func (c *Context) Tx() *sql.Tx {
+	v, _ := c.Get(txKey)
+	tx, _ := v.(*sql.Tx)
+	return tx
+}
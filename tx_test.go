@@ -0,0 +1,89 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// txLog records the transactions of the txTestDriver connections.
+type txLog struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (l *txLog) add(event string) {
+	l.mu.Lock()
+	l.events = append(l.events, event)
+	l.mu.Unlock()
+}
+
+func (l *txLog) take() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	events := l.events
+	l.events = nil
+	return events
+}
+
+type txTestDriver struct{ log *txLog }
+
+func (d txTestDriver) Open(string) (driver.Conn, error) { return txTestConn(d), nil }
+
+type txTestConn struct{ log *txLog }
+
+func (c txTestConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c txTestConn) Close() error                        { return nil }
+func (c txTestConn) Begin() (driver.Tx, error)           { c.log.add("begin"); return txTestTx(c), nil }
+
+type txTestTx struct{ log *txLog }
+
+func (t txTestTx) Commit() error   { t.log.add("commit"); return nil }
+func (t txTestTx) Rollback() error { t.log.add("rollback"); return nil }
+
+func TestTx(t *testing.T) {
+	log := &txLog{}
+	sql.Register("alsonow-tx-test", txTestDriver{log})
+	db, err := sql.Open("alsonow-tx-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	an := New()
+	api := an.Group("/", Tx(db))
+	api.POST("/ok", func(c *Context) {
+		if c.Tx() == nil {
+			t.Error("c.Tx() = nil")
+		}
+		c.Status(http.StatusCreated)
+	})
+	api.POST("/invalid", func(c *Context) { c.Error(http.StatusUnprocessableEntity, "invalid") })
+	api.POST("/panic", func(c *Context) { panic("boom") })
+
+	tests := []struct {
+		path string
+		code int
+		want string
+	}{
+		{"/ok", http.StatusCreated, "commit"},
+		{"/invalid", http.StatusUnprocessableEntity, "rollback"},
+		{"/panic", http.StatusInternalServerError, "rollback"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+		events := log.take()
+		if w.Code != tt.code || len(events) != 2 || events[0] != "begin" || events[1] != tt.want {
+			t.Errorf("%s: %d %v, want %d [begin %s]", tt.path, w.Code, events, tt.code, tt.want)
+		}
+	}
+}
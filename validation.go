@@ -0,0 +1,277 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Validator checks a bound value. The Bind methods run it after decoding;
+// plug another implementation, such as an adapter for
+// go-playground/validator, with AlsoNow.WithValidator. Returning
+// ValidationErrors gives clients per-field details.
+type Validator interface {
+	Validate(v any) error
+}
+
+// FieldError describes why a field failed validation.
+type FieldError struct {
+	// Field is the path of the field as named on the wire, e.g. "address.city".
+	Field string `json:"field"`
+	// Rule is the failed rule, e.g. "required" or "max".
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+	// Offset, Line and Column locate a binding error in a JSON body.
+	Offset int64 `json:"offset,omitempty"`
+	Line   int   `json:"line,omitempty"`
+	Column int   `json:"column,omitempty"`
+	// Err is the error of a Validator not reporting fields, or the decoding
+	// error of a binding error.
+	Err error `json:"-"`
+}
+
+// ValidationErrors is the error returned by the Bind methods when the
+// value is invalid. Render it as 400 Bad Request.
+type ValidationErrors []FieldError
+
+func (ve ValidationErrors) Error() string {
+	msgs := make([]string, len(ve))
+	for i, fe := range ve {
+		if fe.Field == "" {
+			msgs[i] = fe.Message
+		} else {
+			msgs[i] = fe.Field + ": " + fe.Message
+		}
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+// Unwrap returns the errors of a foreign Validator, for errors.As.
+func (ve ValidationErrors) Unwrap() []error {
+	var errs []error
+	for _, fe := range ve {
+		if fe.Err != nil {
+			errs = append(errs, fe.Err)
+		}
+	}
+	return errs
+}
+
+// WithValidator sets the validator used by the Bind methods; nil disables
+// validation. TagValidator is used by default.
+func (an *AlsoNow) WithValidator(v Validator) *AlsoNow {
+	an.router().validator = v
+	return an
+}
+
+// validate runs the validator of the router on v.
+func (c *Context) validate(v any) error {
+	if c.validator == nil {
+		return nil
+	}
+	err := c.validator.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	var ve ValidationErrors
+	if errors.As(err, &ve) {
+		return ve
+	}
+	return ValidationErrors{{Message: err.Error(), Err: err}}
+}
+
+// TagValidator validates structs from their `validate` tags, a comma
+// separated list of rules:
+//
+//	required     the field is not the zero value
+//	omitempty    skip the other rules when the field is the zero value
+//	min=N max=N  bounds of numbers, or lengths of strings, slices and maps
+//	len=N        exact length
+//	oneof=a b c  one of the space separated values
+//	email, url   well-formed address
+//
+// Nested structs are validated recursively.
+type TagValidator struct{}
+
+func (TagValidator) Validate(v any) error {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs ValidationErrors
+	if err := validateStruct(rv, "", &errs); err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func validateStruct(rv reflect.Value, prefix string, errs *ValidationErrors) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		f := rv.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := validateStruct(f, prefix, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name := prefix + wireName(field)
+		if tag := field.Tag.Get("validate"); tag != "" && tag != "-" {
+			if err := validateField(f, name, tag, errs); err != nil {
+				return err
+			}
+		}
+
+		for f.Kind() == reflect.Pointer && !f.IsNil() {
+			f = f.Elem()
+		}
+		if f.Kind() == reflect.Struct && f.Type().PkgPath() != "time" {
+			if err := validateStruct(f, name+".", errs); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wireName returns the name of the field in JSON or forms.
+func wireName(field reflect.StructField) string {
+	for _, key := range []string{"json", "form"} {
+		if name, _, _ := strings.Cut(field.Tag.Get(key), ","); name != "" && name != "-" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+func validateField(f reflect.Value, name, tag string, errs *ValidationErrors) error {
+	for _, rule := range strings.Split(tag, ",") {
+		rule, param, _ := strings.Cut(rule, "=")
+
+		if rule == "omitempty" {
+			if f.IsZero() {
+				return nil
+			}
+			continue
+		}
+		if rule == "required" {
+			if f.IsZero() {
+				*errs = append(*errs, FieldError{Field: name, Rule: rule, Message: "is required"})
+				return nil
+			}
+			continue
+		}
+
+		v := f
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				return nil
+			}
+			v = v.Elem()
+		}
+
+		msg, err := checkRule(v, rule, param)
+		if err != nil {
+			return fmt.Errorf("alsonow: field %s: %w", name, err)
+		}
+		if msg != "" {
+			*errs = append(*errs, FieldError{Field: name, Rule: rule, Param: param, Message: msg})
+			// Report a single failure per field.
+			return nil
+		}
+	}
+	return nil
+}
+
+// checkRule returns the message describing why v fails rule, or "".
+func checkRule(v reflect.Value, rule, param string) (string, error) {
+	switch rule {
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s parameter %q", rule, param)
+		}
+		size, isLen, ok := measure(v)
+		if !ok {
+			return "", fmt.Errorf("%s does not apply to %s", rule, v.Type())
+		}
+		unit := ""
+		if isLen {
+			unit = " in length"
+		}
+		switch {
+		case rule == "min" && size < n:
+			return fmt.Sprintf("must be at least %s%s", param, unit), nil
+		case rule == "max" && size > n:
+			return fmt.Sprintf("must be at most %s%s", param, unit), nil
+		case rule == "len" && size != n:
+			return fmt.Sprintf("must be exactly %s in length", param), nil
+		}
+	case "oneof":
+		s := fmt.Sprint(v.Interface())
+		for _, allowed := range strings.Fields(param) {
+			if s == allowed {
+				return "", nil
+			}
+		}
+		return "must be one of " + strings.Join(strings.Fields(param), ", "), nil
+	case "email":
+		addr, err := mail.ParseAddress(v.String())
+		if err != nil || addr.Address != v.String() {
+			return "must be a valid email address", nil
+		}
+	case "url":
+		u, err := url.ParseRequestURI(v.String())
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return "must be a valid URL", nil
+		}
+	default:
+		return "", fmt.Errorf("unknown validation rule %q", rule)
+	}
+	return "", nil
+}
+
+// measure returns the value of a number, or the length of a string, slice
+// or map.
+func measure(v reflect.Value) (size float64, isLen, ok bool) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), true, true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), true, true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), false, true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), false, true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), false, true
+	}
+	return 0, false, false
+}
@@ -0,0 +1,84 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type signup struct {
+	Email   string   `json:"email" validate:"required,email"`
+	Name    string   `json:"name" validate:"required,min=2,max=20"`
+	Age     int      `json:"age" validate:"omitempty,min=18"`
+	Plan    string   `json:"plan" validate:"oneof=free pro"`
+	Tags    []string `json:"tags" validate:"max=2"`
+	Website *string  `json:"website" validate:"omitempty,url"`
+	Address struct {
+		City string `json:"city" validate:"required"`
+	} `json:"address"`
+}
+
+func TestTagValidator(t *testing.T) {
+	site := "not a url"
+	v := signup{Email: "bob@", Name: "b", Age: 12, Plan: "gold", Tags: []string{"a", "b", "c"}, Website: &site}
+
+	var ve ValidationErrors
+	if err := (TagValidator{}).Validate(&v); !errors.As(err, &ve) {
+		t.Fatalf("Validate = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, fe := range ve {
+		got[fe.Field] = fe.Rule
+	}
+	want := map[string]string{
+		"email": "email", "name": "min", "age": "min", "plan": "oneof",
+		"tags": "max", "website": "url", "address.city": "required",
+	}
+	for field, rule := range want {
+		if got[field] != rule {
+			t.Errorf("%s: rule %q, want %q", field, got[field], rule)
+		}
+	}
+
+	ok := signup{Email: "bob@example.com", Name: "Bob", Plan: "pro"}
+	ok.Address.City = "Paris"
+	if err := (TagValidator{}).Validate(&ok); err != nil {
+		t.Errorf("valid value: %v", err)
+	}
+}
+
+type rejectAll struct{}
+
+func (rejectAll) Validate(any) error { return errors.New("nope") }
+
+func TestContext_BindValidates(t *testing.T) {
+	serve := func(an *AlsoNow) error {
+		var err error
+		an.POST("/", func(c *Context) {
+			var v signup
+			err = c.Bind(&v)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"email":"x"}`))
+		req.Header.Set("Content-Type", "application/json")
+		an.ServeHTTP(httptest.NewRecorder(), req)
+		return err
+	}
+
+	var ve ValidationErrors
+	if err := serve(New()); !errors.As(err, &ve) || len(ve) == 0 {
+		t.Errorf("default validator: %v", err)
+	}
+	if err := serve(New().WithValidator(rejectAll{})); !errors.As(err, &ve) || ve[0].Message != "nope" {
+		t.Errorf("custom validator: %v", err)
+	}
+	if err := serve(New().WithValidator(nil)); err != nil {
+		t.Errorf("disabled validator: %v", err)
+	}
+}
@@ -0,0 +1,39 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"log"
+	"net/http"
+
+	"golang.org/x/net/webdav"
+)
+
+// WebDAV serves fs over WebDAV under prefix, so file-sync clients can talk to
+// the same server as the web application. ls defaults to an in-memory lock
+// system. middlewares run before every WebDAV request, after the global ones,
+// which makes them the natural place for authentication.
+func (an *AlsoNow) WebDAV(prefix string, fs webdav.FileSystem, ls webdav.LockSystem, middlewares ...HandlerFunc) {
+	if ls == nil {
+		ls = webdav.NewMemLS()
+	}
+
+	h := &webdav.Handler{
+		Prefix:     normalizePath(prefix),
+		FileSystem: fs,
+		LockSystem: ls,
+		Logger: func(r *http.Request, err error) {
+			if err != nil {
+				log.Printf("[WEBDAV] %s %s: %v", r.Method, r.URL.Path, err)
+			}
+		},
+	}
+
+	handlers := make([]HandlerFunc, 0, len(middlewares)+1)
+	handlers = append(handlers, middlewares...)
+	handlers = append(handlers, WrapH(h))
+
+	an.router().mount(nil, prefix, handlers)
+}
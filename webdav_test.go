@@ -0,0 +1,52 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/webdav"
+)
+
+func TestAlsoNow_WebDAV(t *testing.T) {
+	an := New()
+	an.WebDAV("/dav", webdav.NewMemFS(), nil, func(c *Context) {
+		if _, _, ok := c.Req.BasicAuth(); !ok {
+			c.Status(http.StatusUnauthorized)
+			c.Abort()
+			return
+		}
+		c.Next()
+	})
+
+	do := func(method, target, body string, auth bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		if auth {
+			req.SetBasicAuth("user", "pass")
+		}
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := do(http.MethodGet, "/dav/a/b.txt", "", false); w.Code != http.StatusUnauthorized {
+		t.Errorf("unauthenticated: status = %d", w.Code)
+	}
+	if w := do("MKCOL", "/dav/a", "", true); w.Code != http.StatusCreated {
+		t.Errorf("MKCOL: status = %d", w.Code)
+	}
+	if w := do(http.MethodPut, "/dav/a/b.txt", "hello", true); w.Code != http.StatusCreated {
+		t.Errorf("PUT: status = %d", w.Code)
+	}
+	if w := do(http.MethodGet, "/dav/a/b.txt", "", true); w.Body.String() != "hello" {
+		t.Errorf("GET: body = %q", w.Body.String())
+	}
+	if w := do(http.MethodGet, "/other", "", true); w.Code != http.StatusNotFound {
+		t.Errorf("outside prefix: status = %d", w.Code)
+	}
+}
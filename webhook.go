@@ -0,0 +1,628 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrWebhookNotFound is returned by a WebhookStore for unknown endpoints and
+// deliveries.
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// ErrInvalidWebhookSignature is returned by VerifyWebhook.
+var ErrInvalidWebhookSignature = errors.New("invalid webhook signature")
+
+// Delivery statuses.
+const (
+	WebhookPending   = "pending"
+	WebhookDelivered = "delivered"
+	// WebhookDead marks the deliveries that failed every attempt, kept
+	// for inspection and Redeliver.
+	WebhookDead = "dead"
+)
+
+// WebhookEndpoint is a URL receiving events.
+type WebhookEndpoint struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+	// Events are the events sent to the endpoint, all of them when empty.
+	Events  []string        `json:"events,omitempty"`
+	Secrets []WebhookSecret `json:"-"`
+}
+
+// WebhookSecret is a signing key of an endpoint. Payloads are signed with
+// every key not yet expired, so receivers can switch to a new key while
+// the old one is still accepted.
+type WebhookSecret struct {
+	Key []byte
+	// Expires is when the key stops being used, never when zero.
+	Expires time.Time
+}
+
+// WebhookDelivery is an event sent to an endpoint, and the outcome of its
+// attempts.
+type WebhookDelivery struct {
+	ID          string          `json:"id"`
+	EndpointID  string          `json:"endpoint_id"`
+	Event       string          `json:"event"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	NextAttempt time.Time       `json:"next_attempt"`
+	// LastStatus is the response status of the last attempt, 0 when no
+	// response was received, LastError why it failed.
+	LastStatus int       `json:"last_status,omitempty"`
+	LastError  string    `json:"last_error,omitempty"`
+	Created    time.Time `json:"created"`
+	Updated    time.Time `json:"updated"`
+}
+
+// WebhookStore persists webhook endpoints and deliveries.
+type WebhookStore interface {
+	SaveEndpoint(ctx context.Context, ep *WebhookEndpoint) error
+	Endpoint(ctx context.Context, id string) (*WebhookEndpoint, error)
+	Endpoints(ctx context.Context) ([]*WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+
+	SaveDelivery(ctx context.Context, d *WebhookDelivery) error
+	Delivery(ctx context.Context, id string) (*WebhookDelivery, error)
+	// Claim returns up to limit pending deliveries due at now, and pushes
+	// their next attempt back by lease so that other dispatchers sharing
+	// the store leave them alone. A dispatcher that stops before saving
+	// the outcome leaves them to be retried once the lease runs out.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error)
+	// Deliveries returns the latest deliveries, newest first, of an
+	// endpoint and with a status, any when empty.
+	Deliveries(ctx context.Context, endpointID, status string, limit int) ([]*WebhookDelivery, error)
+}
+
+// WebhookConfig configures Webhooks.
+type WebhookConfig struct {
+	// Store keeps the endpoints and deliveries, a MemoryWebhookStore when
+	// nil. A shared store lets several instances dispatch together.
+	Store WebhookStore
+	// Client sends the deliveries, one with a 10s timeout when nil.
+	Client *http.Client
+	// MaxAttempts is the number of attempts before a delivery is dead,
+	// 8 when zero.
+	MaxAttempts int
+	// Backoff returns the delay after the failed attempt n, starting at 1:
+	// 30s doubling up to 6h by default.
+	Backoff func(n int) time.Duration
+	// Workers is the number of deliveries sent at once, 4 when zero.
+	Workers int
+	// PollInterval is how often the store is checked for due deliveries,
+	// 1s when zero.
+	PollInterval time.Duration
+}
+
+// webhookLease is how long a claimed delivery is left to its dispatcher.
+const webhookLease = time.Minute
+
+// Webhooks sends events to the registered endpoints, following the
+// Standard Webhooks conventions: payloads are POSTed as JSON with the
+// webhook-id, webhook-timestamp and webhook-signature headers, the latter
+// an HMAC-SHA256 per signing key that receivers check with VerifyWebhook.
+// Failed deliveries are retried with backoff until MaxAttempts, then kept
+// as dead; Admin reports and requeues them.
+type Webhooks struct {
+	cfg  WebhookConfig
+	wake chan struct{}
+}
+
+// NewWebhooks returns a Webhooks using cfg. Deliveries are sent while Run
+// is running, see WithWebhooks.
+func NewWebhooks(cfg WebhookConfig) *Webhooks {
+	if cfg.Store == nil {
+		cfg.Store = NewMemoryWebhookStore()
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 8
+	}
+	if cfg.Backoff == nil {
+		cfg.Backoff = webhookBackoff
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = 4
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	return &Webhooks{cfg: cfg, wake: make(chan struct{}, 1)}
+}
+
+func webhookBackoff(n int) time.Duration {
+	if n > 10 {
+		return 6 * time.Hour
+	}
+	return min(30*time.Second<<(n-1), 6*time.Hour)
+}
+
+// WithWebhooks sends the deliveries of w for the lifetime of the server,
+// the deliveries in progress completing on shutdown.
+func (an *AlsoNow) WithWebhooks(w *Webhooks) *AlsoNow {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	started := false
+	an.OnStart(func(context.Context) error {
+		started = true
+		go func() {
+			defer close(done)
+			w.Run(ctx)
+		}()
+		return nil
+	})
+	an.OnShutdown(func(sctx context.Context) error {
+		cancel()
+		if !started {
+			return nil
+		}
+		select {
+		case <-done:
+			return nil
+		case <-sctx.Done():
+			return fmt.Errorf("stop webhooks: %w", sctx.Err())
+		}
+	})
+	return an
+}
+
+// AddEndpoint registers an endpoint, with an ID generated when empty. It
+// needs at least one secret.
+func (w *Webhooks) AddEndpoint(ctx context.Context, ep *WebhookEndpoint) error {
+	if ep.URL == "" || len(ep.Secrets) == 0 {
+		return errors.New("webhook endpoint needs a URL and a secret")
+	}
+	if ep.ID == "" {
+		ep.ID = "ep_" + randomHex(8)
+	}
+	return w.cfg.Store.SaveEndpoint(ctx, ep)
+}
+
+// RemoveEndpoint unregisters an endpoint. Its pending deliveries are
+// marked dead when their turn comes.
+func (w *Webhooks) RemoveEndpoint(ctx context.Context, id string) error {
+	return w.cfg.Store.DeleteEndpoint(ctx, id)
+}
+
+// RotateSecret adds key to the signing keys of an endpoint, and lets the
+// current ones expire after overlap, the time given to the receiver to
+// switch to key. Expired keys are removed.
+func (w *Webhooks) RotateSecret(ctx context.Context, id string, key []byte, overlap time.Duration) error {
+	ep, err := w.cfg.Store.Endpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	secrets := []WebhookSecret{{Key: key}}
+	for _, s := range ep.Secrets {
+		if s.Expires.IsZero() || s.Expires.After(now.Add(overlap)) {
+			s.Expires = now.Add(overlap)
+		}
+		if s.Expires.After(now) {
+			secrets = append(secrets, s)
+		}
+	}
+	ep.Secrets = secrets
+	return w.cfg.Store.SaveEndpoint(ctx, ep)
+}
+
+// Send queues payload, marshaled to JSON, for the endpoints subscribed to
+// event.
+func (w *Webhooks) Send(ctx context.Context, event string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook payload: %w", err)
+	}
+	endpoints, err := w.cfg.Store.Endpoints(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, ep := range endpoints {
+		if !ep.subscribed(event) {
+			continue
+		}
+		d := &WebhookDelivery{
+			ID:          "msg_" + randomHex(12),
+			EndpointID:  ep.ID,
+			Event:       event,
+			Payload:     data,
+			Status:      WebhookPending,
+			NextAttempt: now,
+			Created:     now,
+			Updated:     now,
+		}
+		if err := w.cfg.Store.SaveDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	w.notify()
+	return nil
+}
+
+// Redeliver queues a delivery again, dead ones with a new set of attempts.
+func (w *Webhooks) Redeliver(ctx context.Context, id string) error {
+	d, err := w.cfg.Store.Delivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	if d.Status == WebhookDead {
+		d.Attempts = 0
+	}
+	d.Status, d.NextAttempt, d.Updated = WebhookPending, time.Now(), time.Now()
+	if err := w.cfg.Store.SaveDelivery(ctx, d); err != nil {
+		return err
+	}
+	w.notify()
+	return nil
+}
+
+func (w *Webhooks) notify() {
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (ep *WebhookEndpoint) subscribed(event string) bool {
+	if len(ep.Events) == 0 {
+		return true
+	}
+	for _, e := range ep.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Run sends the due deliveries until ctx is done, then waits for the
+// attempts in progress. Deliveries are claimed as workers become idle, so
+// a slow endpoint only holds up the worker sending to it.
+func (w *Webhooks) Run(ctx context.Context) {
+	// Attempts in progress complete on shutdown, within the lease.
+	actx := context.WithoutCancel(ctx)
+	jobs := make(chan *WebhookDelivery, w.cfg.Workers)
+	idle := make(chan struct{}, w.cfg.Workers)
+	var wg sync.WaitGroup
+	for i := 0; i < w.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for d := range jobs {
+				dctx, cancel := context.WithTimeout(actx, webhookLease)
+				w.attempt(dctx, d)
+				cancel()
+				<-idle
+			}
+		}()
+	}
+	defer func() {
+		close(jobs)
+		wg.Wait()
+	}()
+
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		// Wait for a worker, then claim one delivery per idle worker.
+		select {
+		case idle <- struct{}{}:
+		case <-ctx.Done():
+			return
+		}
+		n := 1
+	reserve:
+		for n < w.cfg.Workers {
+			select {
+			case idle <- struct{}{}:
+				n++
+			default:
+				break reserve
+			}
+		}
+
+		batch, err := w.cfg.Store.Claim(ctx, time.Now(), webhookLease, n)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("[WEBHOOK] claim deliveries: %v", err)
+		}
+		for _, d := range batch {
+			jobs <- d
+		}
+		for i := len(batch); i < n; i++ {
+			<-idle
+		}
+		if len(batch) == n && ctx.Err() == nil {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// attempt sends d once and saves the outcome.
+func (w *Webhooks) attempt(ctx context.Context, d *WebhookDelivery) {
+	ep, err := w.cfg.Store.Endpoint(ctx, d.EndpointID)
+	if errors.Is(err, ErrWebhookNotFound) {
+		d.Status, d.LastError = WebhookDead, "endpoint removed"
+		w.save(ctx, d)
+		return
+	}
+	if err != nil {
+		log.Printf("[WEBHOOK] delivery %s: %v", d.ID, err)
+		return
+	}
+
+	now := time.Now()
+	d.Attempts++
+	d.LastStatus, d.LastError = w.post(ctx, ep, d, now)
+	d.Updated = now
+	switch {
+	case d.LastError == "":
+		d.Status = WebhookDelivered
+	case d.Attempts >= w.cfg.MaxAttempts:
+		d.Status = WebhookDead
+		log.Printf("[WEBHOOK] delivery %s to %s dead after %d attempts: %s", d.ID, ep.URL, d.Attempts, d.LastError)
+	default:
+		d.NextAttempt = now.Add(w.cfg.Backoff(d.Attempts))
+	}
+	w.save(ctx, d)
+}
+
+func (w *Webhooks) save(ctx context.Context, d *WebhookDelivery) {
+	if err := w.cfg.Store.SaveDelivery(ctx, d); err != nil {
+		log.Printf("[WEBHOOK] save delivery %s: %v", d.ID, err)
+	}
+}
+
+// post sends d to ep and returns the response status and the error, ""
+// for a 2xx response.
+func (w *Webhooks) post(ctx context.Context, ep *WebhookEndpoint, d *WebhookDelivery, now time.Time) (int, string) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ep.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err.Error()
+	}
+	ts := strconv.FormatInt(now.Unix(), 10)
+	req.Header.Set("Content-Type", MIMEJSON)
+	req.Header.Set("User-Agent", "alsonow-webhooks")
+	req.Header.Set("webhook-id", d.ID)
+	req.Header.Set("webhook-timestamp", ts)
+	req.Header.Set("webhook-signature", signWebhook(ep.Secrets, d.ID, ts, d.Payload, now))
+
+	resp, err := w.cfg.Client.Do(req)
+	if err != nil {
+		return 0, err.Error()
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, resp.Status
+	}
+	return resp.StatusCode, ""
+}
+
+// signWebhook returns the signatures of a payload with the keys not expired
+// at now, space separated.
+func signWebhook(secrets []WebhookSecret, id, ts string, payload []byte, now time.Time) string {
+	var sigs []string
+	for _, s := range secrets {
+		if s.Expires.IsZero() || s.Expires.After(now) {
+			sigs = append(sigs, "v1,"+webhookSignature(s.Key, id, ts, payload))
+		}
+	}
+	return strings.Join(sigs, " ")
+}
+
+func webhookSignature(key []byte, id, ts string, payload []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + ts + "."))
+	mac.Write(payload)
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhook checks, for receivers, that a payload was signed with one of
+// keys less than tolerance ago, 5 minutes when zero, which defeats replays.
+func VerifyWebhook(keys [][]byte, h http.Header, payload []byte, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = 5 * time.Minute
+	}
+	id, ts := h.Get("webhook-id"), h.Get("webhook-timestamp")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if id == "" || err != nil {
+		return ErrInvalidWebhookSignature
+	}
+	if age := time.Since(time.Unix(sec, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("%w: timestamp too old", ErrInvalidWebhookSignature)
+	}
+
+	for _, sig := range strings.Fields(h.Get("webhook-signature")) {
+		sig, ok := strings.CutPrefix(sig, "v1,")
+		if !ok {
+			continue
+		}
+		for _, key := range keys {
+			if hmac.Equal([]byte(sig), []byte(webhookSignature(key, id, ts, payload))) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidWebhookSignature
+}
+
+// Admin returns a handler reporting the endpoints and the latest
+// deliveries as JSON, filtered by the endpoint and status query
+// parameters and bounded by limit, 100 by default. POST requests with a
+// redeliver parameter, a delivery ID, queue it again:
+//
+//	admin := an.Group("/admin", requireAdmin)
+//	admin.GET("/webhooks", webhooks.Admin())
+//	admin.POST("/webhooks", webhooks.Admin())
+func (w *Webhooks) Admin() HandlerFunc {
+	return func(c *Context) {
+		ctx := c.Context()
+		if c.Method() == http.MethodPost {
+			err := w.Redeliver(ctx, c.QueryParam("redeliver"))
+			switch {
+			case errors.Is(err, ErrWebhookNotFound):
+				c.Error(http.StatusNotFound, "unknown delivery")
+			case err != nil:
+				c.InternalServerError(err)
+			default:
+				c.Writer.WriteHeader(http.StatusAccepted)
+			}
+			return
+		}
+
+		limit, err := strconv.Atoi(c.QueryParam("limit"))
+		if err != nil || limit <= 0 {
+			limit = 100
+		}
+		endpoints, err := w.cfg.Store.Endpoints(ctx)
+		if err != nil {
+			c.InternalServerError(err)
+			return
+		}
+		deliveries, err := w.cfg.Store.Deliveries(ctx, c.QueryParam("endpoint"), c.QueryParam("status"), limit)
+		if err != nil {
+			c.InternalServerError(err)
+			return
+		}
+		_ = c.JSON(http.StatusOK, map[string]any{"endpoints": endpoints, "deliveries": deliveries})
+	}
+}
+
+// MemoryWebhookStore is an in-memory WebhookStore, for single instance
+// applications and tests. Deliveries are lost on restart.
+type MemoryWebhookStore struct {
+	mu         sync.Mutex
+	endpoints  map[string]WebhookEndpoint
+	deliveries map[string]WebhookDelivery
+}
+
+// NewMemoryWebhookStore returns an empty MemoryWebhookStore.
+func NewMemoryWebhookStore() *MemoryWebhookStore {
+	return &MemoryWebhookStore{
+		endpoints:  make(map[string]WebhookEndpoint),
+		deliveries: make(map[string]WebhookDelivery),
+	}
+}
+
+func (s *MemoryWebhookStore) SaveEndpoint(_ context.Context, ep *WebhookEndpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints[ep.ID] = *ep
+	return nil
+}
+
+func (s *MemoryWebhookStore) Endpoint(_ context.Context, id string) (*WebhookEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ep, ok := s.endpoints[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	return &ep, nil
+}
+
+func (s *MemoryWebhookStore) Endpoints(context.Context) ([]*WebhookEndpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]*WebhookEndpoint, 0, len(s.endpoints))
+	for _, ep := range s.endpoints {
+		ep := ep
+		out = append(out, &ep)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out, nil
+}
+
+func (s *MemoryWebhookStore) DeleteEndpoint(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.endpoints, id)
+	return nil
+}
+
+func (s *MemoryWebhookStore) SaveDelivery(_ context.Context, d *WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries[d.ID] = *d
+	return nil
+}
+
+func (s *MemoryWebhookStore) Delivery(_ context.Context, id string) (*WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d, ok := s.deliveries[id]
+	if !ok {
+		return nil, ErrWebhookNotFound
+	}
+	return &d, nil
+}
+
+func (s *MemoryWebhookStore) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]*WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*WebhookDelivery
+	for _, d := range s.deliveries {
+		if d.Status == WebhookPending && !d.NextAttempt.After(now) {
+			d := d
+			due = append(due, &d)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].NextAttempt.Before(due[j].NextAttempt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	for _, d := range due {
+		leased := *d
+		leased.NextAttempt = now.Add(lease)
+		s.deliveries[d.ID] = leased
+	}
+	return due, nil
+}
+
+func (s *MemoryWebhookStore) Deliveries(_ context.Context, endpointID, status string, limit int) ([]*WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*WebhookDelivery
+	for _, d := range s.deliveries {
+		if (endpointID == "" || d.EndpointID == endpointID) && (status == "" || d.Status == status) {
+			d := d
+			out = append(out, &d)
+		}
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Created.After(out[j].Created) })
+	if len(out) > limit {
+		out = out[:limit]
+	}
+	return out, nil
+}
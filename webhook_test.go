@@ -0,0 +1,206 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhooks(t *testing.T) {
+	oldKey, newKey := []byte("old-secret"), []byte("new-secret")
+	var calls atomic.Int32
+	received := make(chan string, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := VerifyWebhook([][]byte{newKey}, r.Header, body, 0); err != nil {
+			t.Errorf("signature with the new key: %v", err)
+		}
+		if err := VerifyWebhook([][]byte{oldKey}, r.Header, body, 0); err != nil {
+			t.Errorf("signature with the old key during the overlap: %v", err)
+		}
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		received <- string(body)
+	}))
+	defer srv.Close()
+	dead := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer dead.Close()
+
+	ctx := context.Background()
+	wh := NewWebhooks(WebhookConfig{
+		MaxAttempts:  3,
+		Backoff:      func(int) time.Duration { return time.Millisecond },
+		PollInterval: 5 * time.Millisecond,
+	})
+	ep := &WebhookEndpoint{URL: srv.URL, Events: []string{"order.created"}, Secrets: []WebhookSecret{{Key: oldKey}}}
+	if err := wh.AddEndpoint(ctx, ep); err != nil {
+		t.Fatal(err)
+	}
+	if err := wh.RotateSecret(ctx, ep.ID, newKey, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := wh.AddEndpoint(ctx, &WebhookEndpoint{ID: "dead", URL: dead.URL, Secrets: []WebhookSecret{{Key: newKey}}}); err != nil {
+		t.Fatal(err)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wh.Run(rctx)
+	}()
+	stop := func() {
+		cancel()
+		<-done
+	}
+	defer stop()
+
+	if err := wh.Send(ctx, "order.created", map[string]int{"id": 7}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case body := <-received:
+		if body != `{"id":7}` {
+			t.Errorf("payload = %s", body)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("delivery not retried until success")
+	}
+
+	an := New()
+	an.GET("/admin/webhooks", wh.Admin())
+	an.POST("/admin/webhooks", wh.Admin())
+	deliveries := func(query string) []*WebhookDelivery {
+		w := httptest.NewRecorder()
+		an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/webhooks?"+query, nil))
+		var out struct {
+			Endpoints  []*WebhookEndpoint
+			Deliveries []*WebhookDelivery
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil || len(out.Endpoints) != 2 {
+			t.Fatalf("admin: %d %s", w.Code, w.Body.String())
+		}
+		return out.Deliveries
+	}
+
+	var deadID string
+	deadline := time.Now().Add(2 * time.Second)
+	for deadID == "" && time.Now().Before(deadline) {
+		if ds := deliveries("status=dead"); len(ds) == 1 {
+			deadID = ds[0].ID
+			if ds[0].EndpointID != "dead" || ds[0].Attempts != 3 || ds[0].LastStatus != http.StatusInternalServerError {
+				t.Errorf("dead delivery = %+v", ds[0])
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if deadID == "" {
+		t.Fatal("failing delivery not dead-lettered")
+	}
+	ds := deliveries("endpoint=" + ep.ID)
+	if len(ds) != 1 || ds[0].Status != WebhookDelivered || ds[0].Attempts != 3 {
+		t.Errorf("delivered = %+v", ds)
+	}
+
+	stop()
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks?redeliver="+deadID, nil))
+	if w.Code != http.StatusAccepted {
+		t.Errorf("redeliver: %d", w.Code)
+	}
+	if d, _ := wh.cfg.Store.Delivery(ctx, deadID); d.Status != WebhookPending || d.Attempts != 0 {
+		t.Errorf("redelivered = %+v", d)
+	}
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/webhooks?redeliver=nope", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("redeliver unknown: %d", w.Code)
+	}
+}
+
+func TestVerifyWebhook(t *testing.T) {
+	key := []byte("secret")
+	now := time.Now()
+	ts := strconv.FormatInt(now.Unix(), 10)
+	h := http.Header{}
+	h.Set("webhook-id", "msg_1")
+	h.Set("webhook-timestamp", ts)
+	h.Set("webhook-signature", signWebhook([]WebhookSecret{
+		{Key: []byte("expired"), Expires: now.Add(-time.Second)},
+		{Key: key},
+	}, "msg_1", ts, []byte("{}"), now))
+
+	if err := VerifyWebhook([][]byte{key}, h, []byte("{}"), 0); err != nil {
+		t.Errorf("valid: %v", err)
+	}
+	if err := VerifyWebhook([][]byte{[]byte("expired")}, h, []byte("{}"), 0); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("expired key: %v", err)
+	}
+	if err := VerifyWebhook([][]byte{key}, h, []byte(`{"a":1}`), 0); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("tampered payload: %v", err)
+	}
+	old := strconv.FormatInt(now.Add(-time.Hour).Unix(), 10)
+	h.Set("webhook-timestamp", old)
+	h.Set("webhook-signature", "v1,"+webhookSignature(key, "msg_1", old, []byte("{}")))
+	if err := VerifyWebhook([][]byte{key}, h, []byte("{}"), 0); !errors.Is(err, ErrInvalidWebhookSignature) {
+		t.Errorf("replayed: %v", err)
+	}
+}
+
+func TestWebhooks_SlowEndpoint(t *testing.T) {
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	fastCalls := make(chan struct{}, 10)
+	fast := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fastCalls <- struct{}{}
+	}))
+	defer fast.Close()
+
+	ctx := context.Background()
+	wh := NewWebhooks(WebhookConfig{Workers: 2, PollInterval: 5 * time.Millisecond})
+	secrets := []WebhookSecret{{Key: []byte("k")}}
+	_ = wh.AddEndpoint(ctx, &WebhookEndpoint{URL: slow.URL, Events: []string{"slow"}, Secrets: secrets})
+	_ = wh.AddEndpoint(ctx, &WebhookEndpoint{URL: fast.URL, Events: []string{"fast"}, Secrets: secrets})
+	_ = wh.Send(ctx, "slow", 1)
+	for i := 0; i < 5; i++ {
+		_ = wh.Send(ctx, "fast", i)
+	}
+
+	rctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wh.Run(rctx)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+	defer close(release)
+
+	for i := 0; i < 5; i++ {
+		select {
+		case <-fastCalls:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("%d fast deliveries held up by a slow endpoint", 5-i)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import "net/http"
+
+// WrapH adapts a net/http handler to a HandlerFunc:
+//
+//	an.GET("/debug/vars", alsonow.WrapH(expvar.Handler()))
+func WrapH(h http.Handler) HandlerFunc {
+	return func(c *Context) {
+		h.ServeHTTP(c.Writer, c.Req)
+	}
+}
+
+// WrapF adapts a net/http handler function to a HandlerFunc.
+func WrapF(f http.HandlerFunc) HandlerFunc {
+	return WrapH(f)
+}
+
+// WrapMiddleware adapts a net/http middleware to a HandlerFunc. The rest of
+// the chain runs as the handler it wraps, with the writer and request it
+// passes on; when it does not call that handler, the chain is aborted.
+//
+//	an.Use(alsonow.WrapMiddleware(handlers.CompressHandler))
+func WrapMiddleware(mw func(http.Handler) http.Handler) HandlerFunc {
+	return func(c *Context) {
+		called := false
+		next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+			c.Writer, c.Req = w, r
+			c.Next()
+		})
+
+		w, req := c.Writer, c.Req
+		mw(next).ServeHTTP(w, req)
+		c.Writer, c.Req = w, req
+		if !called {
+			c.Abort()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type ctxKey struct{}
+
+func TestWrapMiddleware(t *testing.T) {
+	tag := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("X-Deny") != "" {
+				http.Error(w, "denied", http.StatusForbidden)
+				return
+			}
+			w.Header().Set("X-Tagged", "1")
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, "v")))
+		})
+	}
+
+	ran := false
+	an := New()
+	an.Use(WrapMiddleware(tag))
+	an.GET("/", WrapF(func(w http.ResponseWriter, r *http.Request) {
+		ran = true
+		_, _ = w.Write([]byte(r.Context().Value(ctxKey{}).(string)))
+	}))
+
+	w := httptest.NewRecorder()
+	an.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if w.Body.String() != "v" || w.Header().Get("X-Tagged") != "1" {
+		t.Errorf("got %q, headers %v", w.Body.String(), w.Header())
+	}
+
+	ran = false
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Deny", "1")
+	w = httptest.NewRecorder()
+	an.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden || ran {
+		t.Errorf("status = %d, handler ran = %v", w.Code, ran)
+	}
+}
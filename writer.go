@@ -0,0 +1,126 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"time"
+)
+
+// responseWriter wraps the http.ResponseWriter of a request to record the
+// status code and the number of bytes written. It implements Unwrap, so
+// http.ResponseController reaches the connection through it, as well as
+// http.Flusher and http.Hijacker for code that asserts them directly.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	size        int
+	wroteHeader bool
+
+	// streams registers the response on its first flush, as streaming.
+	streams   *streamRegistry
+	streaming bool
+}
+
+func (w *responseWriter) reset(rw http.ResponseWriter) {
+	w.ResponseWriter = rw
+	w.status = http.StatusOK
+	w.size = 0
+	w.wroteHeader = false
+	w.streaming = false
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	// Informational responses (103 Early Hints...) may precede the final one.
+	if code >= 100 && code < 200 && code != http.StatusSwitchingProtocols {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if w.wroteHeader {
+		return
+	}
+	w.wroteHeader = true
+	w.status = code
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.size += n
+	return n, err
+}
+
+// Flush implements http.Flusher.
+func (w *responseWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.streaming && w.streams != nil {
+		w.streaming = true
+		w.streams.add(w)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Hijack implements http.Hijacker.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, rw, err := http.NewResponseController(w.ResponseWriter).Hijack()
+	if err == nil {
+		w.wroteHeader = true
+	}
+	return conn, rw, err
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// StatusCode returns the status code written so far, or the one pending
+// from AbortWithPriority, 200 if none was.
+func (c *Context) StatusCode() int {
+	if !c.resp.wroteHeader && c.abortStatus != 0 {
+		return c.abortStatus
+	}
+	return c.resp.status
+}
+
+// Written reports whether the response headers have been sent.
+func (c *Context) Written() bool {
+	return c.resp.wroteHeader
+}
+
+// ResponseSize returns the number of body bytes written so far.
+func (c *Context) ResponseSize() int {
+	return c.resp.size
+}
+
+// Flush sends any buffered data to the client.
+func (c *Context) Flush() error {
+	return http.NewResponseController(c.Writer).Flush()
+}
+
+// SetReadDeadline sets the deadline for reading the request body, overriding
+// the server's ReadTimeout for this request. The zero time removes it.
+func (c *Context) SetReadDeadline(t time.Time) error {
+	return http.NewResponseController(c.Writer).SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for writing the response, overriding
+// the server's WriteTimeout for this request. The zero time removes it.
+func (c *Context) SetWriteDeadline(t time.Time) error {
+	return http.NewResponseController(c.Writer).SetWriteDeadline(t)
+}
+
+// EnableFullDuplex allows reading the request body after the response has
+// started, which HTTP/1 servers otherwise forbid.
+func (c *Context) EnableFullDuplex() error {
+	return http.NewResponseController(c.Writer).EnableFullDuplex()
+}
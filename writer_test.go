@@ -0,0 +1,47 @@
+// Package alsonow
+// Copyright 2025 alsonow. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+package alsonow
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestContext_ResponseController(t *testing.T) {
+	var deadlineErr, duplexErr, flushErr error
+
+	an := New()
+	an.GET("/", Throttle(ThrottleConfig{Download: 1 << 20}), func(c *Context) {
+		deadlineErr = c.SetWriteDeadline(time.Now().Add(time.Minute))
+		duplexErr = c.EnableFullDuplex()
+		c.Status(http.StatusAccepted)
+		_, _ = c.Writer.Write([]byte("hello"))
+		flushErr = c.Flush()
+
+		if c.StatusCode() != http.StatusAccepted || c.ResponseSize() != 5 || !c.Written() {
+			t.Errorf("status = %d, size = %d, written = %v", c.StatusCode(), c.ResponseSize(), c.Written())
+		}
+	})
+
+	srv := httptest.NewServer(an)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	if deadlineErr != nil || duplexErr != nil || flushErr != nil {
+		t.Errorf("through wrapped writer: deadline err = %v, full duplex err = %v, flush err = %v", deadlineErr, duplexErr, flushErr)
+	}
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("status = %d", resp.StatusCode)
+	}
+}